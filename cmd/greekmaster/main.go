@@ -26,6 +26,17 @@ func init() {
 	rootCmd.AddCommand(commands.NewAddCmd())
 	rootCmd.AddCommand(commands.NewListCmd())
 	rootCmd.AddCommand(commands.NewMigrateCmd())
+	rootCmd.AddCommand(commands.NewTemplateCmd())
+	rootCmd.AddCommand(commands.NewTagCmd())
+	rootCmd.AddCommand(commands.NewPresetCmd())
+	rootCmd.AddCommand(commands.NewProfileCmd())
+	rootCmd.AddCommand(commands.NewServeCmd())
+	rootCmd.AddCommand(commands.NewMCPCmd())
+	rootCmd.AddCommand(commands.NewConfigCmd())
+	rootCmd.AddCommand(commands.NewDBCmd())
+	rootCmd.AddCommand(commands.NewDoctorCmd())
+
+	commands.AddGlobalFlags(rootCmd)
 }
 
 func main() {
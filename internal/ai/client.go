@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -29,16 +29,32 @@ type DeclensionResponse struct {
 	AccPlArticle string `json:"acc_pl_article"`
 }
 
+// TemplateResponse represents a single AI-generated sentence template
+type TemplateResponse struct {
+	EnglishTemplate string   `json:"english_template"`
+	GreekTemplate   string   `json:"greek_template"`
+	Preposition     *string  `json:"preposition"`
+	RequiredTags    []string `json:"required_tags"`
+}
+
+// Options configures the Claude API client
+type Options struct {
+	Model     string // Model to call
+	MaxTokens int    // Token limit of each response
+	Retries   int    // Times a failed request is retried
+}
 
 // ClaudeClient wraps the Anthropic SDK client
 type ClaudeClient struct {
-	client *anthropic.Client
-	model  string
+	client    *anthropic.Client
+	model     string
+	maxTokens int
+	retries   int
 }
 
 // NewClaudeClient creates a new Claude API client
 // Reads ANTHROPIC_API_KEY from environment variable
-func NewClaudeClient() (*ClaudeClient, error) {
+func NewClaudeClient(opts Options) (*ClaudeClient, error) {
 	apiKey := os.Getenv("ANTHROPIC_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable not set")
@@ -47,25 +63,40 @@ func NewClaudeClient() (*ClaudeClient, error) {
 	client := anthropic.NewClient(option.WithAPIKey(apiKey))
 
 	return &ClaudeClient{
-		client: &client,
-		model:  "claude-sonnet-4-6",
+		client:    &client,
+		model:     opts.Model,
+		maxTokens: opts.MaxTokens,
+		retries:   opts.Retries,
 	}, nil
 }
 
 // callAPI makes an API call with the given prompt and parses the JSON response
 func (c *ClaudeClient) callAPI(ctx context.Context, prompt string) (string, error) {
+	slog.Debug("AI request", "model", c.model, "max_tokens", c.maxTokens, "prompt", prompt)
+	start := time.Now()
+
 	message, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:     anthropic.Model(c.model),
-		MaxTokens: 2000,
+		MaxTokens: int64(c.maxTokens),
 		Messages: []anthropic.MessageParam{
 			anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
 		},
 	})
 
 	if err != nil {
+		slog.Debug("AI request failed", "model", c.model, "duration", time.Since(start), "error", err)
 		return "", fmt.Errorf("API call failed: %w", err)
 	}
 
+	slog.Debug("AI response",
+		"model", c.model,
+		"duration", time.Since(start),
+		"stop_reason", message.StopReason,
+		"input_tokens", message.Usage.InputTokens,
+		"output_tokens", message.Usage.OutputTokens,
+		"content", responseText(message),
+	)
+
 	// Extract text from response
 	if len(message.Content) == 0 {
 		return "", fmt.Errorf("empty response from API")
@@ -104,23 +135,15 @@ func cleanJSONResponse(text string) string {
 	return text
 }
 
-// logError logs errors to import.log file
-func (c *ClaudeClient) logError(format string, args ...any) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return
-	}
-
-	logPath := filepath.Join(homeDir, ".greekmaster", "import.log")
-	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return
+// responseText joins the text blocks of a response for logging
+func responseText(message *anthropic.Message) string {
+	var parts []string
+	for _, block := range message.Content {
+		if block.Type == "text" {
+			parts = append(parts, block.Text)
+		}
 	}
-	defer f.Close()
-
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logMsg := fmt.Sprintf("[%s] "+format+"\n", append([]any{timestamp}, args...)...)
-	f.WriteString(logMsg)
+	return strings.Join(parts, "\n")
 }
 
 // GenerateDeclensions generates all declined forms for a Greek noun
@@ -132,20 +155,80 @@ func (c *ClaudeClient) GenerateDeclensions(greek, english, gender string) (*Decl
 		ctx := context.Background()
 		text, err := c.callAPI(ctx, prompt)
 		if err != nil {
-			c.logError("Declension API call failed for '%s': %v", greek, err)
+			slog.Warn("Declension request failed", "noun", greek, "error", err)
 			return err
 		}
 
 		// Parse JSON response
 		var decl DeclensionResponse
 		if err := json.Unmarshal([]byte(text), &decl); err != nil {
-			c.logError("Failed to parse declension JSON for '%s': %v\nResponse: %s", greek, err, text)
+			slog.Warn("Invalid declension JSON", "noun", greek, "error", err, "response", text)
 			return fmt.Errorf("invalid JSON response: %w", err)
 		}
 
 		response = &decl
 		return nil
-	}, 3) // Max 3 retries
+	}, c.retries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GenerateTemplates generates new sentence templates for a case and context
+func (c *ClaudeClient) GenerateTemplates(caseType, contextType, number string, count int) ([]TemplateResponse, error) {
+	prompt := GenerateTemplatePrompt(caseType, contextType, number, count)
+
+	var response []TemplateResponse
+	err := RetryWithBackoff(func() error {
+		ctx := context.Background()
+		text, err := c.callAPI(ctx, prompt)
+		if err != nil {
+			slog.Warn("Template request failed", "case", caseType, "context", contextType, "error", err)
+			return err
+		}
+
+		// Parse JSON response
+		var templates []TemplateResponse
+		if err := json.Unmarshal([]byte(text), &templates); err != nil {
+			slog.Warn("Invalid template JSON", "case", caseType, "context", contextType, "error", err, "response", text)
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+
+		response = templates
+		return nil
+	}, c.retries)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// generateTags calls the API with a tagging prompt and parses the JSON array of tags
+func (c *ClaudeClient) generateTags(prompt, subject string) ([]string, error) {
+	var response []string
+	err := RetryWithBackoff(func() error {
+		ctx := context.Background()
+		text, err := c.callAPI(ctx, prompt)
+		if err != nil {
+			slog.Warn("Tag request failed", "subject", subject, "error", err)
+			return err
+		}
+
+		// Parse JSON response
+		var tags []string
+		if err := json.Unmarshal([]byte(text), &tags); err != nil {
+			slog.Warn("Invalid tag JSON", "subject", subject, "error", err, "response", text)
+			return fmt.Errorf("invalid JSON response: %w", err)
+		}
+
+		response = tags
+		return nil
+	}, c.retries)
 
 	if err != nil {
 		return nil, err
@@ -154,4 +237,14 @@ func (c *ClaudeClient) GenerateDeclensions(greek, english, gender string) (*Decl
 	return response, nil
 }
 
+// GenerateNounTags classifies a Greek noun with semantic tags
+func (c *ClaudeClient) GenerateNounTags(greek, english string) ([]string, error) {
+	return c.generateTags(GenerateNounTagsPrompt(greek, english), greek)
+}
+
+// GenerateTemplateTags determines which noun tags a sentence template requires
+func (c *ClaudeClient) GenerateTemplateTags(englishTemplate, greekTemplate string) ([]string, error) {
+	return c.generateTags(GenerateTemplateTagsPrompt(englishTemplate, greekTemplate), englishTemplate)
+}
+
 // GenerateExplanations generates explanations for multiple sentences
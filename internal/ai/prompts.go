@@ -1,6 +1,11 @@
 package ai
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+)
 
 // GenerateDeclensionPrompt creates a prompt for generating all declined forms of a Greek noun
 func GenerateDeclensionPrompt(greek, english, gender string) string {
@@ -16,4 +21,84 @@ func GenerateDeclensionPrompt(greek, english, gender string) string {
 Return only valid JSON, no explanation.`, greek, english, gender)
 }
 
+// GenerateTemplatePrompt creates a prompt for generating reusable sentence templates
+func GenerateTemplatePrompt(caseType, contextType, number string, count int) string {
+	return fmt.Sprintf(`You are a Modern Greek grammar expert writing practice sentences for learners.
+Create %d different sentence templates where the missing noun is in the %s case (%s), used as %s.
+
+Each template is a pair of English and Greek sentences with placeholders:
+- The English template contains "___" for the blank followed by a hint, e.g. "I see ___ (the {noun})". Use "(the {noun}s)" for plural and "(the {noun}{pl:|s})" when the number is "both".
+- The Greek template contains "{article} {noun_form}" exactly once, in the position of the declined article and noun, e.g. "Βλέπω {article} {noun_form}".
+- Do not contract the article with a preposition yourself: write "σε {article} {noun_form}", never "στ{article}". The contraction (σε + τον → στον) is applied automatically.
+- The rest of the sentence must be grammatical for any noun. When a word must agree with the noun, write an agreement slot listing its variants separated by "|":
+  - number only: "{verb:τρέχει|τρέχουν}" (singular|plural), also usable in English, e.g. "{be:is|are}" or "{noun}{pl:|s}"
+  - gender and number: "{adj:σπασμένος|σπασμένη|σπασμένο|σπασμένοι|σπασμένες|σπασμένα}" (masculine, feminine, neuter singular, then plural)
+- For preposition templates set "preposition" to the Greek preposition that governs the noun, otherwise null.%s
+- Set "required_tags" to the kinds of noun that make sense in the sentence (%s), or [] if any noun fits.
+
+Return a JSON array in this format:
+[
+  {"english_template": "...", "greek_template": "...", "preposition": null, "required_tags": []}
+]
+Return only valid JSON, no explanation.`, count, caseType, number, strings.ReplaceAll(contextType, "_", " "), contextHint(contextType, caseType), tagList)
+}
+
+// contextHint points the model at the catalog entries for a context: the
+// prepositions that govern the case, the verbs that take a recipient in it, or
+// the time construction
+func contextHint(contextType, caseType string) string {
+	catalog := grammar.Default()
+
+	var entries []string
+	switch contextType {
+	case "preposition":
+		for _, prep := range catalog.Prepositions {
+			if prep.Governs(caseType) {
+				entries = append(entries, fmt.Sprintf("%s (%s)", prep.Greek, prep.Gloss))
+			}
+		}
+		if len(entries) > 0 {
+			return "\n- Use only these prepositions: " + strings.Join(entries, ", ") + "."
+		}
+
+	case "indirect_object":
+		for _, verb := range catalog.Verbs {
+			if verb.Indirect == caseType {
+				entries = append(entries, fmt.Sprintf("%s (%s)", verb.Lemma, verb.Gloss))
+			}
+		}
+		if len(entries) > 0 {
+			return "\n- The noun is the recipient of one of these verbs, without a preposition: " + strings.Join(entries, ", ") + "."
+		}
+
+	case "time":
+		if construction, ok := catalog.ConstructionFor(contextType, caseType); ok {
+			return fmt.Sprintf("\n- %s e.g. %s (%s)", construction.Description, construction.Example, construction.Translation)
+		}
+	}
+
+	return ""
+}
+
+// tagList is the comma-separated list of semantic tags offered to the model
+const tagList = "person, animal, place, food, object, abstract, time, mass, count"
 
+// GenerateNounTagsPrompt creates a prompt for classifying a noun with semantic tags
+func GenerateNounTagsPrompt(greek, english string) string {
+	return fmt.Sprintf(`You are a Modern Greek grammar expert. Classify the noun '%s' (%s) with semantic tags.
+Choose one or more tags describing what the noun is (person, animal, place, food, object, abstract, time)
+and exactly one of "mass" or "count" for whether it can be counted.
+Return a JSON array of tags, e.g. ["person", "count"].
+Return only valid JSON, no explanation.`, greek, english)
+}
+
+// GenerateTemplateTagsPrompt creates a prompt for the noun tags a sentence template requires
+func GenerateTemplateTagsPrompt(englishTemplate, greekTemplate string) string {
+	return fmt.Sprintf(`You are a Modern Greek grammar expert. The sentence template below has a blank that will be filled with a noun.
+English: %s
+Greek: %s
+Which kinds of noun make sense in the blank? Choose from: %s.
+Tags describing what the noun is are alternatives (any one may match); add "mass" or "count" only if the sentence needs it.
+Return a JSON array of tags, or [] if any noun fits.
+Return only valid JSON, no explanation.`, englishTemplate, greekTemplate, tagList)
+}
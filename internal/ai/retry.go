@@ -2,6 +2,7 @@ package ai
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 )
 
@@ -35,6 +36,7 @@ func RetryWithBackoff(fn RetryableFunc, maxRetries int) error {
 		// Don't sleep after the last attempt
 		if attempt < maxRetries {
 			backoff := CalculateBackoff(attempt)
+			slog.Debug("Retrying after failure", "attempt", attempt+1, "backoff", backoff, "error", err)
 			time.Sleep(backoff)
 		}
 	}
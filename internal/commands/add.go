@@ -3,19 +3,16 @@ package commands
 import (
 	"bufio"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 
-	"github.com/gataky/greekmaster/internal/ai"
 	"github.com/gataky/greekmaster/internal/models"
-	"github.com/gataky/greekmaster/internal/storage"
 	"github.com/spf13/cobra"
 )
 
 // NewAddCmd creates the add command
 func NewAddCmd() *cobra.Command {
-	var dbPath string
-
 	cmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add a single noun interactively",
@@ -86,16 +83,16 @@ This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
 			fmt.Printf("\nAdding: %s (%s, %s)\n\n", english, greek, gender)
 
 			// Initialize repository
-			repo, err := storage.NewSQLiteRepository(dbPath)
+			repo, err := openDatabase()
 			if err != nil {
-				return fmt.Errorf("failed to initialize database: %w", err)
+				return err
 			}
 			defer repo.Close()
 
 			// Initialize Claude client
-			client, err := ai.NewClaudeClient()
+			client, err := newClaudeClient()
 			if err != nil {
-				return fmt.Errorf("failed to initialize Claude API client: %w\n\nMake sure ANTHROPIC_API_KEY environment variable is set", err)
+				return err
 			}
 
 			// Generate declensions
@@ -130,13 +127,26 @@ This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
 			}
 			fmt.Println("✓")
 
+			// Classify noun with semantic tags
+			fmt.Print("Generating tags... ")
+			tags, err := client.GenerateNounTags(greek, english)
+			if err != nil {
+				fmt.Println("FAILED")
+				fmt.Printf("Warning: %v\n", err)
+				slog.Warn("Tag generation failed", "noun", greek, "error", err)
+			} else if err := repo.SetNounTags(noun.ID, tags); err != nil {
+				fmt.Println("FAILED")
+				fmt.Printf("Warning: failed to store tags: %v\n", err)
+			} else {
+				fmt.Println("✓")
+			}
+
+			slog.Info("Added noun", "noun", greek, "id", noun.ID)
 			fmt.Printf("\n✓ Successfully added '%s'\n", english)
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db-path", "", "Path to database file (default: ~/.greekmaster/greekmaster.db)")
-
 	return cmd
 }
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the config command
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show and change settings",
+		Long: fmt.Sprintf(`Show and change the settings stored in the config file.
+
+The config file is YAML, read from $XDG_CONFIG_HOME/greekmaster/config.yaml
+(usually ~/.config/greekmaster/config.yaml) unless --config or $%s
+names another file. Each setting can be overridden with an environment
+variable:
+
+%s`, config.PathEnv, describeSettings()),
+	}
+
+	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigSetCmd())
+	cmd.AddCommand(newConfigShowCmd())
+
+	return cmd
+}
+
+// describeSettings lists each key with its environment variable and meaning
+func describeSettings() string {
+	var lines []string
+	for _, key := range config.Keys() {
+		lines = append(lines, fmt.Sprintf("  %-11s %-23s %s", key, config.EnvVar(key), config.Description(key)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newConfigGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a setting, including environment overrides",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := settings.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(value)
+			return nil
+		},
+	}
+}
+
+func newConfigSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Save a setting in the config file",
+		Long: `Save a setting in the config file. Use an empty value to reset
+data_dir or log_path to its default location.
+
+Example:
+  greekmaster config set max_tokens 4000
+  greekmaster config set data_dir ~/Documents/greek`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+
+			path, err := settingsPath()
+			if err != nil {
+				return err
+			}
+
+			// Save only what is in the file, not the environment overrides
+			saved, err := config.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err := saved.Set(key, value); err != nil {
+				return err
+			}
+			if err := saved.Save(path); err != nil {
+				return err
+			}
+
+			value, _ = saved.Get(key)
+			fmt.Printf("Set %s = %s in %s\n", key, value, path)
+			if env := config.EnvVar(key); os.Getenv(env) != "" {
+				fmt.Printf("Note: %s is set and overrides this setting.\n", env)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the config file location and all settings in effect",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := settingsPath()
+			if err != nil {
+				return err
+			}
+
+			status := ""
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				status = " (not created yet, using defaults)"
+			}
+			fmt.Printf("Config file: %s%s\n\n", path, status)
+
+			for _, key := range config.Keys() {
+				value, _ := settings.Get(key)
+				if value == "" {
+					value = "(default)"
+				}
+				source := ""
+				if env := config.EnvVar(key); os.Getenv(env) != "" {
+					source = fmt.Sprintf("  (from %s)", env)
+				}
+				fmt.Printf("  %-11s %s%s\n", key, value, source)
+			}
+
+			db := dbPath
+			if db == "" {
+				if db, err = settings.DatabasePath(); err != nil {
+					return err
+				}
+			}
+			logFile, err := settings.LogFile()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nDatabase: %s\n", db)
+			fmt.Printf("Log file: %s\n", logFile)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewDBCmd creates the db command
+func NewDBCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Back up and restore the database",
+		Long: `Back up and restore the database.
+
+Snapshots are saved in the backups directory next to the database
+(~/.greekmaster/backups by default) and named after the time they were
+taken. Only the newest snapshots are kept; set how many with
+'greekmaster config set backup_keep <n>'.
+
+A snapshot is also taken automatically before imports, before
+'migrate-to-templates', before upgrades that rebuild tables, and before
+a restore.`,
+	}
+
+	cmd.AddCommand(newDBBackupCmd())
+	cmd.AddCommand(newDBRestoreCmd())
+	cmd.AddCommand(newDBListBackupsCmd())
+
+	return cmd
+}
+
+// takeSnapshot saves a snapshot of the database and prunes old ones
+func takeSnapshot(repo *storage.SQLiteRepository, reason string) (*storage.Backup, error) {
+	backup, err := repo.Snapshot(reason)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := storage.PruneBackups(filepath.Dir(backup.Path), settings.BackupKeep); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+func newDBBackupCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Save a snapshot of the database",
+		Long: `Save a consistent copy of the database while it is in use.
+
+Without --output the snapshot is saved in the backups directory and
+counts towards backup_keep.
+
+Example:
+  greekmaster db backup
+  greekmaster db backup --output ~/greek-before-cleanup.db`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			if output != "" {
+				if err := repo.BackupTo(output); err != nil {
+					return err
+				}
+				fmt.Printf("✓ Backed up the database to %s\n", output)
+				return nil
+			}
+
+			backup, err := takeSnapshot(repo, "manual")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Backed up the database to %s (%s)\n", backup.Path, formatSize(backup.Size))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the backup to this file instead of the backups directory")
+
+	return cmd
+}
+
+func newDBRestoreCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Replace the database with a backup",
+		Long: `Replace the database with a backup, given as a name from
+'greekmaster db list-backups' or a path to a backup file.
+
+The current database is snapshotted first, so a restore can be undone
+by restoring that snapshot.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := databasePath()
+			if err != nil {
+				return err
+			}
+
+			backupPath := args[0]
+			if !strings.ContainsRune(backupPath, filepath.Separator) {
+				if inDir := filepath.Join(storage.BackupDir(path), backupPath); fileExists(inDir) {
+					backupPath = inDir
+				}
+			}
+			if !fileExists(backupPath) {
+				return fmt.Errorf("backup '%s' not found. See 'greekmaster db list-backups'", args[0])
+			}
+
+			if !yes {
+				fmt.Printf("Replace %s with %s? (yes/no): ", path, backupPath)
+				var response string
+				fmt.Scanln(&response)
+				if response != "yes" && response != "y" && response != "Y" {
+					fmt.Println("Restore cancelled.")
+					return nil
+				}
+			}
+
+			if fileExists(path) {
+				repo, err := openDatabase()
+				if err != nil {
+					return err
+				}
+				// Pruning now could delete the backup being restored, so it waits
+				current, err := repo.Snapshot("pre-restore")
+				repo.Close()
+				if err != nil {
+					return fmt.Errorf("failed to snapshot the current database: %w", err)
+				}
+				fmt.Printf("Saved the current database to %s\n", current.Path)
+			}
+
+			if err := storage.RestoreBackup(backupPath, path); err != nil {
+				return err
+			}
+			if _, err := storage.PruneBackups(storage.BackupDir(path), settings.BackupKeep); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Restored %s\n", filepath.Base(backupPath))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+
+	return cmd
+}
+
+func newDBListBackupsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-backups",
+		Short: "List database snapshots, newest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := databasePath()
+			if err != nil {
+				return err
+			}
+
+			dir := storage.BackupDir(path)
+			backups, err := storage.ListBackups(dir)
+			if err != nil {
+				return err
+			}
+			if len(backups) == 0 {
+				fmt.Println("No backups yet. Create one with 'greekmaster db backup'.")
+				return nil
+			}
+
+			fmt.Printf("Backups in %s:\n\n", dir)
+			fmt.Printf("%-45s %-19s %-14s %s\n", "Name", "Taken", "Reason", "Size")
+			fmt.Println(strings.Repeat("-", 90))
+			for _, backup := range backups {
+				fmt.Printf("%-45s %-19s %-14s %s\n",
+					backup.Name,
+					backup.CreatedAt.Format("2006-01-02 15:04:05"),
+					backup.Reason,
+					formatSize(backup.Size),
+				)
+			}
+
+			return nil
+		},
+	}
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// formatSize renders a byte count for display
+func formatSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
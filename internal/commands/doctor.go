@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/doctor"
+	"github.com/gataky/greekmaster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCmd creates the doctor command
+func NewDoctorCmd() *cobra.Command {
+	var fix, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Find and fix mistakes in nouns and templates",
+		Long: `Check nouns and templates for mistakes that AI-generated data is prone to:
+
+  - articles that don't match the noun's gender
+  - forms with more than one accent mark
+  - Latin letters that look like Greek ones (o for ο, v for ν)
+  - empty plural forms on nouns not tagged as mass nouns
+  - templates whose article or noun form fields don't exist on a noun or
+    don't match the template's case, which produces sentences such as
+    "του ενήλικο"
+  - templates missing their {noun} or {article} {noun_form} placeholders
+
+For each problem you are asked whether to apply the suggested fix, or to
+type the corrected value when there is no automatic fix. With --fix every
+automatic fix is applied without asking. The database is snapshotted
+before the first change.
+
+Example:
+  greekmaster doctor --dry-run
+  greekmaster doctor --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fix && dryRun {
+				return fmt.Errorf("--fix and --dry-run cannot be used together")
+			}
+
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			issues, err := doctor.Scan(repo)
+			if err != nil {
+				return err
+			}
+			if len(issues) == 0 {
+				fmt.Println("✓ No problems found")
+				return nil
+			}
+
+			nouns, templates := make(map[int64]bool), make(map[int64]bool)
+			fixable := 0
+			for _, issue := range issues {
+				if issue.NounID != 0 {
+					nouns[issue.NounID] = true
+				} else {
+					templates[issue.TemplateID] = true
+				}
+				if issue.Fixable() {
+					fixable++
+				}
+			}
+			fmt.Printf("Found %d problems in %d nouns and %d templates (%d can be fixed automatically)\n\n",
+				len(issues), len(nouns), len(templates), fixable)
+
+			if dryRun {
+				for _, issue := range issues {
+					printIssue(issue)
+					fmt.Println()
+				}
+				return nil
+			}
+
+			d := &doctorRun{repo: repo, reader: bufio.NewReader(os.Stdin)}
+			for _, issue := range issues {
+				printIssue(issue)
+				if fix {
+					if issue.Fixable() {
+						if err := d.apply(issue); err != nil {
+							return err
+						}
+					}
+					fmt.Println()
+					continue
+				}
+				quit, err := d.ask(issue)
+				if err != nil {
+					return err
+				}
+				fmt.Println()
+				if quit {
+					break
+				}
+			}
+
+			fmt.Printf("✓ Fixed %d of %d problems\n", d.fixed, len(issues))
+			if remaining := len(issues) - d.fixed; remaining > 0 && fix {
+				fmt.Printf("%d problems need a person; run 'greekmaster doctor' to correct them one by one\n", remaining)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Apply every automatic fix without asking")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the problems")
+
+	return cmd
+}
+
+// doctorRun applies corrections, snapshotting the database before the first one
+type doctorRun struct {
+	repo        *storage.SQLiteRepository
+	reader      *bufio.Reader
+	snapshotted bool
+	fixed       int
+}
+
+// printIssue shows a problem and its automatic fix
+func printIssue(issue *doctor.Issue) {
+	fmt.Printf("[%s] %s\n  %s\n", issue.Kind, issue.Subject, issue.Problem)
+	if issue.Fixable() {
+		fmt.Printf("  Fix: %s\n", issue.Fix)
+	}
+}
+
+// snapshot saves a copy of the database before the first change
+func (d *doctorRun) snapshot() error {
+	if d.snapshotted {
+		return nil
+	}
+	backup, err := takeSnapshot(d.repo, "pre-doctor")
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the database: %w", err)
+	}
+	fmt.Printf("  (saved a snapshot to %s)\n", backup.Path)
+	d.snapshotted = true
+	return nil
+}
+
+// apply makes an issue's automatic fix
+func (d *doctorRun) apply(issue *doctor.Issue) error {
+	if err := d.snapshot(); err != nil {
+		return err
+	}
+	if err := doctor.Apply(d.repo, issue); err != nil {
+		return fmt.Errorf("failed to fix %s: %w", issue.Subject, err)
+	}
+	slog.Info("Doctor fixed a problem", "kind", issue.Kind, "subject", issue.Subject, "fix", issue.Fix)
+	fmt.Println("  ✓ Fixed")
+	d.fixed++
+	return nil
+}
+
+// ask offers the fix for an issue, or asks for the corrected value when there is
+// none. It reports whether the user chose to stop.
+func (d *doctorRun) ask(issue *doctor.Issue) (bool, error) {
+	if issue.Fixable() {
+		answer, err := d.readLine("  Apply the fix? (yes/no/quit): ")
+		if err != nil {
+			return true, err
+		}
+		switch answer {
+		case "yes", "y", "Y":
+			return false, d.apply(issue)
+		case "quit", "q":
+			return true, nil
+		}
+	}
+	if issue.Field == "" {
+		return false, nil
+	}
+
+	for {
+		value, err := d.readLine(fmt.Sprintf("  Corrected %s (blank to skip, q to quit): ", issue.FieldLabel()))
+		if err != nil {
+			return true, err
+		}
+		switch value {
+		case "":
+			return false, nil
+		case "q":
+			return true, nil
+		}
+
+		if err := d.snapshot(); err != nil {
+			return true, err
+		}
+		if err := doctor.Correct(d.repo, issue, value); err != nil {
+			fmt.Printf("  %v\n", err)
+			continue
+		}
+		slog.Info("Doctor corrected a problem", "kind", issue.Kind, "subject", issue.Subject, "field", issue.Field, "value", value)
+		fmt.Println("  ✓ Corrected")
+		d.fixed++
+		return false, nil
+	}
+}
+
+// readLine prompts for a line of input. The end of input counts as quitting.
+func (d *doctorRun) readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := d.reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return "q", nil
+	}
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
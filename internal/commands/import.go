@@ -1,10 +1,11 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
-	"github.com/gataky/greekmaster/internal/ai"
 	"github.com/gataky/greekmaster/internal/importer"
 	"github.com/gataky/greekmaster/internal/storage"
 	"github.com/spf13/cobra"
@@ -12,7 +13,8 @@ import (
 
 // NewImportCmd creates the import command
 func NewImportCmd() *cobra.Command {
-	var dbPath string
+	var retryFailed int64
+	var listRuns bool
 
 	cmd := &cobra.Command{
 		Use:   "import <csv-file>",
@@ -28,9 +30,29 @@ Example CSV format:
   book,βιβλίο,neuter
   woman,γυναίκα,feminine
 
+Every import is recorded as a numbered run listing what happened to each
+row: imported, skipped as a duplicate of a noun already in the database,
+or failed with an error. Rows that failed can be retried without the CSV
+file:
+
+  greekmaster import --list-runs
+  greekmaster import --retry-failed 12
+
 This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
-		Args: cobra.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if listRuns || cmd.Flags().Changed("retry-failed") {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listRuns {
+				return listImportRuns()
+			}
+			if cmd.Flags().Changed("retry-failed") {
+				return retryImport(retryFailed)
+			}
+
 			csvPath := args[0]
 
 			// Check if file exists
@@ -39,18 +61,25 @@ This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
 			}
 
 			// Initialize repository
-			repo, err := storage.NewSQLiteRepository(dbPath)
+			repo, err := openDatabase()
 			if err != nil {
-				return fmt.Errorf("failed to initialize database: %w", err)
+				return err
 			}
 			defer repo.Close()
 
 			// Initialize Claude client
-			client, err := ai.NewClaudeClient()
+			client, err := newClaudeClient()
 			if err != nil {
-				return fmt.Errorf("failed to initialize Claude API client: %w\n\nMake sure ANTHROPIC_API_KEY environment variable is set", err)
+				return err
 			}
 
+			// Snapshot the database so a bad import can be rolled back
+			backup, err := takeSnapshot(repo, "pre-import")
+			if err != nil {
+				return fmt.Errorf("failed to snapshot database before import: %w", err)
+			}
+			fmt.Printf("Saved a snapshot of the database to %s\n", backup.Path)
+
 			// Create processor and run import
 			processor := importer.NewImportProcessor(repo, client)
 			if err := processor.ProcessImport(csvPath); err != nil {
@@ -61,7 +90,81 @@ This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db-path", "", "Path to database file (default: ~/.greekmaster/greekmaster.db)")
+	cmd.Flags().Int64Var(&retryFailed, "retry-failed", 0, "Reprocess only the rows that failed in this import run")
+	cmd.Flags().BoolVar(&listRuns, "list-runs", false, "List recent import runs and their row counts")
+	cmd.MarkFlagsMutuallyExclusive("retry-failed", "list-runs")
 
 	return cmd
 }
+
+// retryImport reprocesses the failed rows of an import run
+func retryImport(runID int64) error {
+	repo, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	// Fail early on unknown runs, before asking for an API key
+	if _, err := repo.GetImportRun(runID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("import run %d not found. See 'greekmaster import --list-runs'", runID)
+		}
+		return err
+	}
+
+	client, err := newClaudeClient()
+	if err != nil {
+		return err
+	}
+
+	backup, err := takeSnapshot(repo, "pre-import")
+	if err != nil {
+		return fmt.Errorf("failed to snapshot database before import: %w", err)
+	}
+	fmt.Printf("Saved a snapshot of the database to %s\n", backup.Path)
+
+	processor := importer.NewImportProcessor(repo, client)
+	if err := processor.RetryFailed(runID); err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+	return nil
+}
+
+// listImportRuns prints recent import runs with their row counts
+func listImportRuns() error {
+	repo, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	runs, err := repo.ListImportRuns(20)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Println("No imports yet. Import nouns with 'greekmaster import <csv-file>'.")
+		return nil
+	}
+
+	fmt.Printf("%-5s %-19s %-12s %8s %10s %7s  %s\n", "Run", "Started", "Status", "Imported", "Duplicates", "Failed", "File")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, run := range runs {
+		file := run.CSVPath
+		if run.RetryOf != nil {
+			file = fmt.Sprintf("%s (retry of run %d)", file, *run.RetryOf)
+		}
+		fmt.Printf("%-5d %-19s %-12s %8d %10d %7d  %s\n",
+			run.ID,
+			run.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			strings.ReplaceAll(run.Status, "_", " "),
+			run.Imported,
+			run.Duplicates,
+			run.Failed,
+			file,
+		)
+	}
+
+	return nil
+}
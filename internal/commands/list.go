@@ -4,14 +4,11 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/gataky/greekmaster/internal/storage"
 	"github.com/spf13/cobra"
 )
 
 // NewListCmd creates the list command
 func NewListCmd() *cobra.Command {
-	var dbPath string
-
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all nouns in the database",
@@ -26,9 +23,9 @@ Shows:
 The list is ordered by ID (insertion order).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize repository
-			repo, err := storage.NewSQLiteRepository(dbPath)
+			repo, err := openDatabase()
 			if err != nil {
-				return fmt.Errorf("failed to initialize database: %w", err)
+				return err
 			}
 			defer repo.Close()
 
@@ -96,7 +93,5 @@ The list is ordered by ID (insertion order).`,
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db-path", "", "Path to database file (default: ~/.greekmaster/greekmaster.db)")
-
 	return cmd
 }
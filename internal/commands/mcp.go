@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/gataky/greekmaster/internal/mcp"
+	"github.com/spf13/cobra"
+)
+
+// NewMCPCmd creates the mcp command
+func NewMCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve declension and quiz tools to AI assistants over MCP",
+		Long: `Run a Model Context Protocol server on stdin/stdout so an AI assistant
+can look up nouns, show declension tables, generate exercises, grade
+answers and explain the correct forms using your noun catalog.
+
+Tools:
+  lookup_noun        Search nouns by English or Greek (accents optional)
+  get_paradigm       Show a noun's full declension table
+  generate_exercise  Generate fill-in-the-blank sentences
+  grade_answer       Grade an answer to a generated sentence
+  explain            Explain the case and form of a generated sentence
+
+Answers graded over MCP are not recorded in your progress.
+
+Configure your MCP client to launch the command, for example:
+  {"mcpServers": {"greekmaster": {"command": "greekmaster", "args": ["mcp"]}}}`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openRepository()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			return mcp.New(repo).Serve(os.Stdin, os.Stdout)
+		},
+	}
+
+	return cmd
+}
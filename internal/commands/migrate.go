@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/gataky/greekmaster/internal/storage"
 	"github.com/spf13/cobra"
@@ -9,8 +10,6 @@ import (
 
 // NewMigrateCmd creates the migrate-to-templates command
 func NewMigrateCmd() *cobra.Command {
-	var dbPath string
-
 	cmd := &cobra.Command{
 		Use:   "migrate-to-templates",
 		Short: "Migrate from sentence-based to template-based system",
@@ -22,6 +21,9 @@ This migration will:
 2. Create ~100 reusable templates in the sentence_templates table
 3. Validate that all sentences can be regenerated from templates
 4. Delete the old sentence data (reducing database size by ~80%)
+5. Tag the new templates and any untagged nouns with AI, so practice only
+   pairs templates with suitable nouns (skipped without ANTHROPIC_API_KEY;
+   run 'greekmaster tag' later)
 
 The migration is wrapped in a transaction and will automatically rollback
 if any step fails. Your data is safe.
@@ -30,9 +32,9 @@ NOTE: This is a one-time operation. After migration, practice sessions will
 generate sentences on-demand using templates + noun data.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize repository
-			repo, err := storage.NewSQLiteRepository(dbPath)
+			repo, err := openDatabase()
 			if err != nil {
-				return fmt.Errorf("failed to initialize database: %w", err)
+				return err
 			}
 			defer repo.Close()
 
@@ -50,7 +52,7 @@ generate sentences on-demand using templates + noun data.`,
 
 			// Confirm with user
 			fmt.Println("\n⚠️  IMPORTANT: This operation will modify your database.")
-			fmt.Println("A transaction is used to ensure data safety, but it's recommended to backup first.")
+			fmt.Println("A snapshot of the database is saved first; restore it with 'greekmaster db restore'.")
 			fmt.Print("\nProceed with migration? (yes/no): ")
 			var response string
 			fmt.Scanln(&response)
@@ -60,19 +62,55 @@ generate sentences on-demand using templates + noun data.`,
 				return nil
 			}
 
-			fmt.Println()
+			backup, err := takeSnapshot(repo, "pre-migration")
+			if err != nil {
+				return fmt.Errorf("failed to snapshot database before migrating: %w", err)
+			}
+			fmt.Printf("Saved a snapshot of the database to %s\n\n", backup.Path)
 
 			// Run migration
-			err = storage.MigrateToTemplates(repo)
+			fmt.Println("Migrating sentences to templates...")
+			summary, err := storage.MigrateToTemplates(repo)
 			if err != nil {
 				return fmt.Errorf("migration failed: %w", err)
 			}
 
+			fmt.Println("Migration completed successfully!")
+			fmt.Printf("Summary:\n")
+			fmt.Printf("  - Nouns: %d\n", summary.Nouns)
+			fmt.Printf("  - Templates created: %d\n", summary.Templates)
+			fmt.Printf("  - Sentences migrated: %d\n", summary.Sentences)
+			if summary.Skipped > 0 {
+				fmt.Printf("  - Sentences skipped: %d (see the log for details)\n", summary.Skipped)
+			}
+			if summary.Sentences > 0 {
+				fmt.Printf("  - Database size reduced by ~%d%%\n", (summary.Sentences-summary.Templates)*100/summary.Sentences)
+			}
+
+			// Untagged templates accept any noun, so tag them before they are practised
+			client, err := newClaudeClient()
+			if err != nil {
+				slog.Warn("Skipped tagging migrated templates", "error", err)
+				fmt.Println("\nNote: the new templates have no semantic tags, so practice may pair them")
+				fmt.Println("with nouns that make no sense in them. Set ANTHROPIC_API_KEY and run")
+				fmt.Println("'greekmaster tag' to fill them in.")
+				return nil
+			}
+
+			fmt.Println("\nTagging templates and nouns...")
+			tagged, failed, err := tagMissing(repo, client)
+			if err != nil {
+				return err
+			}
+			slog.Info("Tagged migrated templates", "tagged", tagged, "failed", failed)
+			fmt.Printf("✓ Tagged %d items\n", tagged)
+			if failed > 0 {
+				fmt.Printf("%d items could not be tagged; run 'greekmaster tag' to retry them\n", failed)
+			}
+
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db-path", "", "Path to database file (default: ~/.greekmaster/greekmaster.db)")
-
 	return cmd
 }
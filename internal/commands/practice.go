@@ -4,14 +4,20 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/session"
 	"github.com/gataky/greekmaster/internal/storage"
 	"github.com/gataky/greekmaster/internal/tui"
 	"github.com/spf13/cobra"
 )
 
+// defaultQuestionCount is the session length when setup is skipped
+const defaultQuestionCount = 25
+
 // NewPracticeCmd creates the practice command
 func NewPracticeCmd() *cobra.Command {
-	var dbPath string
+	var presetName string
+	var flags sessionFlags
 
 	cmd := &cobra.Command{
 		Use:   "practice",
@@ -22,15 +28,51 @@ You'll be presented with English prompts and must provide the correctly
 declined Greek article + noun combination.
 
 The application will guide you through difficulty selection, plural inclusion,
-and session type (quick, standard, long, or endless).
+gender and case focus, and session type (quick, standard, long, or endless).
 
 After each answer, you'll receive detailed grammar explanations including
-translation, syntactic role, and morphology.`,
+translation, syntactic role, and morphology.
+
+With --mode choice each question offers four options instead of a text
+box: the correct answer, other case and number forms of the same noun, and
+forms with a swapped article. Press 1-4 to answer.
+
+With --mode reverse you read a full Greek sentence with the noun phrase
+highlighted and identify its case, number and gender, and optionally the
+nominative singular it comes from.
+
+With --mode table you are shown a noun's nominative singular instead and
+must fill in all six article + noun forms of its declension table. Each
+cell is graded separately, and missed cells are tracked per gender and
+declension class.
+
+If your last session was interrupted in the past 24 hours, practice offers
+to resume it when started without session flags or a preset. Sentence,
+choice and reverse sessions are saved after every answer; table drills
+are not.
+
+Session flags (--difficulty, --plural, --count and the filters) or a saved
+--preset skip the setup screen. Flags given alongside a preset override
+its settings. Without a preset, a session started from flags draws from
+every difficulty level, uses singular forms and has 25 questions. Each
+filter flag may be repeated or given a comma-separated list.
+
+--time-limit gives each question a countdown; an unanswered question is
+marked wrong when it runs out. --sprint runs the session against a clock
+and, unless --count is also given, keeps asking questions until time is
+up. Timed sessions finish with your answers per minute, and answers that
+were correct but slow are offered for review alongside mistakes.
+
+Examples:
+  greekmaster practice --gender feminine --case genitive --nouns 1-50
+  greekmaster practice --difficulty intermediate --plural --count 10
+  greekmaster practice --preset "genitive plural warm-up"
+  greekmaster practice --mode choice --sprint 2m --time-limit 8s`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize repository
-			repo, err := storage.NewSQLiteRepository(dbPath)
+			repo, err := openRepository()
 			if err != nil {
-				return fmt.Errorf("failed to initialize database: %w", err)
+				return err
 			}
 			defer repo.Close()
 
@@ -43,48 +85,152 @@ translation, syntactic role, and morphology.`,
 				return fmt.Errorf("no nouns found in database. Please run 'greekmaster import <csv-file>' first")
 			}
 
-			// Start with setup screen
-			setupModel := tui.NewSetupModel()
-			p := tea.NewProgram(setupModel)
-
-			finalModel, err := p.Run()
-			if err != nil {
-				return fmt.Errorf("error running setup: %w", err)
-			}
-
-			// Get the final setup model
-			setupFinal, ok := finalModel.(tui.SetupModel)
-			if !ok {
-				return fmt.Errorf("unexpected model type")
+			// Offer to pick up where an interrupted session left off
+			if presetName == "" && !flags.given(cmd) {
+				saved, err := savedSessionToResume(repo)
+				if err != nil {
+					return err
+				}
+				if saved != nil {
+					return runPractice(repo, func() (tea.Model, error) {
+						if saved.Config.Mode == "reverse" {
+							return tui.ResumeReverseModel(repo, saved)
+						}
+						return tui.ResumePracticeModel(repo, saved)
+					})
+				}
 			}
 
-			// Check if user quit during setup
-			if setupFinal.View() == "" {
-				return nil
+			// Build the session from a preset and flags, or from the setup screen
+			config := defaultSessionConfig()
+			if presetName != "" {
+				preset, err := repo.GetPreset(presetName)
+				if err != nil {
+					return err
+				}
+				config = preset.Config
 			}
-
-			// Get session config
-			config, complete := setupFinal.GetConfig()
-			if !complete {
-				return fmt.Errorf("setup was not completed")
+			if err := flags.apply(cmd, &config); err != nil {
+				return err
 			}
 
-			// Start practice session
-			practiceModel, err := tui.NewPracticeModel(repo, config)
-			if err != nil {
-				return fmt.Errorf("failed to initialize practice session: %w", err)
+			if config.Mode == "table" {
+				// Table drills cover fewer items unless a length was chosen
+				if presetName == "" && !cmd.Flags().Changed("count") {
+					config.QuestionCount = tableDrillNouns
+				}
+				return runTableDrill(repo, config)
 			}
 
-			p = tea.NewProgram(practiceModel)
-			if _, err := p.Run(); err != nil {
-				return fmt.Errorf("error running practice: %w", err)
+			if presetName == "" && !flags.configured(cmd) {
+				setupConfig, ok, err := runSetup()
+				if err != nil || !ok {
+					return err
+				}
+				setupConfig.Mode = config.Mode
+				setupConfig.TimeLimit = config.TimeLimit
+				setupConfig.SprintSeconds = config.SprintSeconds
+				config = setupConfig
 			}
 
-			return nil
+			// Start practice session
+			return runPractice(repo, func() (tea.Model, error) {
+				if config.Mode == "reverse" {
+					return tui.NewReverseModel(repo, config)
+				}
+				return tui.NewPracticeModel(repo, config)
+			})
 		},
 	}
 
-	cmd.Flags().StringVar(&dbPath, "db-path", "", "Path to database file (default: ~/.greekmaster/greekmaster.db)")
+	cmd.Flags().StringVar(&presetName, "preset", "", "Start from a saved session preset (see 'greekmaster preset list')")
+	addSessionFlags(cmd, &flags)
 
 	return cmd
 }
+
+// runPractice builds a practice model and runs it
+func runPractice(repo storage.Repository, newModel func() (tea.Model, error)) error {
+	practiceModel, err := newModel()
+	if err != nil {
+		return fmt.Errorf("failed to initialize practice session: %w", err)
+	}
+
+	p := tea.NewProgram(practiceModel)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running practice: %w", err)
+	}
+
+	return nil
+}
+
+// savedSessionToResume returns the interrupted session if there is one the
+// user wants to resume. Expired and declined sessions are discarded.
+func savedSessionToResume(repo storage.Repository) (*models.SavedSession, error) {
+	saved, err := session.LoadSaved(repo)
+	if err != nil || saved == nil {
+		return nil, err
+	}
+
+	progress := fmt.Sprintf("%d answered, %d correct", saved.Answered(), saved.CorrectCount)
+	if saved.Config.QuestionCount > 0 {
+		progress = fmt.Sprintf("%d of %d answered, %d correct", saved.Answered(), saved.Config.QuestionCount, saved.CorrectCount)
+	}
+	fmt.Printf("Unfinished session from %s (%s): %s\n",
+		saved.UpdatedAt.Local().Format("Jan 2 15:04"), describeConfig(saved.Config), progress)
+	fmt.Print("Resume it? (Y/n): ")
+	var response string
+	fmt.Scanln(&response)
+
+	if response == "" || response == "y" || response == "Y" || response == "yes" {
+		return saved, nil
+	}
+	return nil, repo.DeleteSavedSession()
+}
+
+// runSetup shows the session setup screen. ok is false if the user quit.
+func runSetup() (config models.SessionConfig, ok bool, err error) {
+	p := tea.NewProgram(tui.NewSetupModel())
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return config, false, fmt.Errorf("error running setup: %w", err)
+	}
+
+	// Get the final setup model
+	setupFinal, isSetup := finalModel.(tui.SetupModel)
+	if !isSetup {
+		return config, false, fmt.Errorf("unexpected model type")
+	}
+
+	// Check if user quit during setup
+	if setupFinal.View() == "" {
+		return config, false, nil
+	}
+
+	// Get session config
+	config, complete := setupFinal.GetConfig()
+	if !complete {
+		return config, false, fmt.Errorf("setup was not completed")
+	}
+
+	return config, true, nil
+}
+
+// tableDrillNouns is how many nouns a table drill session covers by default
+const tableDrillNouns = 10
+
+// runTableDrill runs a declension table drill session
+func runTableDrill(repo storage.Repository, config models.SessionConfig) error {
+	tableModel, err := tui.NewTableDrillModel(repo, config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize table drill: %w", err)
+	}
+
+	p := tea.NewProgram(tableModel)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running table drill: %w", err)
+	}
+
+	return nil
+}
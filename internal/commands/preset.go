@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// NewPresetCmd creates the preset command
+func NewPresetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Manage saved practice session presets",
+		Long: `Save, list and delete named practice session configurations.
+
+Start a session from a preset with 'greekmaster practice --preset <name>'.`,
+	}
+
+	cmd.AddCommand(newPresetSaveCmd())
+	cmd.AddCommand(newPresetListCmd())
+	cmd.AddCommand(newPresetDeleteCmd())
+
+	return cmd
+}
+
+func newPresetSaveCmd() *cobra.Command {
+	var flags sessionFlags
+
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save a session preset",
+		Long: `Save the session described by the flags under a name, replacing any
+preset with the same name. Unset flags keep their defaults: every
+difficulty level, singular forms, 25 questions and no filters.
+
+Example:
+  greekmaster preset save "genitive plural warm-up" --case genitive --plural --count 10`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return fmt.Errorf("preset name cannot be empty")
+			}
+
+			config := defaultSessionConfig()
+			if err := flags.apply(cmd, &config); err != nil {
+				return err
+			}
+
+			repo, err := openRepository()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			preset := &models.Preset{Name: name, Config: config}
+			if err := repo.SavePreset(preset); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Saved preset '%s': %s\n", name, describeConfig(config))
+			return nil
+		},
+	}
+
+	addSessionFlags(cmd, &flags)
+
+	return cmd
+}
+
+func newPresetListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved session presets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openRepository()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			presets, err := repo.ListPresets()
+			if err != nil {
+				return err
+			}
+
+			if len(presets) == 0 {
+				fmt.Println("No presets saved. Create one with 'greekmaster preset save <name>'.")
+				return nil
+			}
+
+			for _, preset := range presets {
+				fmt.Printf("%s\n  %s\n", preset.Name, describeConfig(preset.Config))
+			}
+			return nil
+		},
+	}
+}
+
+func newPresetDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a session preset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openRepository()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			if err := repo.DeletePreset(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Deleted preset '%s'\n", args[0])
+			return nil
+		},
+	}
+}
+
+// describeConfig summarizes a session configuration on one line
+func describeConfig(config models.SessionConfig) string {
+	difficulty := config.DifficultyLevel
+	if difficulty == "" {
+		difficulty = "all levels"
+	}
+
+	count := fmt.Sprintf("%d questions", config.QuestionCount)
+	if config.QuestionCount == 0 {
+		count = "endless"
+	}
+
+	number := "singular"
+	if config.IncludePlural {
+		number = "singular + plural"
+	}
+
+	parts := []string{config.Mode, difficulty, number, count}
+
+	filter := config.Filter
+	for _, f := range []struct {
+		label  string
+		values []string
+	}{
+		{"gender", filter.Genders},
+		{"case", filter.Cases},
+		{"preposition", filter.Prepositions},
+		{"context", filter.ContextTypes},
+		{"tag", filter.Tags},
+	} {
+		if len(f.values) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", f.label, strings.Join(f.values, ", ")))
+		}
+	}
+	if len(filter.NounRanges) > 0 {
+		ranges := make([]string, len(filter.NounRanges))
+		for i, r := range filter.NounRanges {
+			ranges[i] = fmt.Sprintf("%d-%d", r.Min, r.Max)
+		}
+		parts = append(parts, "nouns: "+strings.Join(ranges, ","))
+	}
+	if config.TimeLimit > 0 {
+		parts = append(parts, fmt.Sprintf("%ds per question", config.TimeLimit))
+	}
+	if config.SprintSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("%s sprint", time.Duration(config.SprintSeconds)*time.Second))
+	}
+
+	return strings.Join(parts, " · ")
+}
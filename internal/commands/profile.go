@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewProfileCmd creates the profile command
+func NewProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage learner profiles",
+		Long: `Create, list, switch and delete learner profiles.
+
+Everyone sharing a database practises the same nouns and templates, but
+attempt history, table drill results, presets and interrupted sessions
+belong to a profile. Commands use the active profile unless --profile is
+given.`,
+	}
+
+	cmd.AddCommand(newProfileCreateCmd())
+	cmd.AddCommand(newProfileListCmd())
+	cmd.AddCommand(newProfileSwitchCmd())
+	cmd.AddCommand(newProfileDeleteCmd())
+
+	return cmd
+}
+
+func newProfileCreateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			profile, err := repo.CreateProfile(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Created profile '%s'. Use it with 'greekmaster profile switch %s'.\n", profile.Name, profile.Name)
+			return nil
+		},
+	}
+}
+
+func newProfileListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles, marking the active one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			profiles, err := repo.ListProfiles()
+			if err != nil {
+				return err
+			}
+
+			for _, profile := range profiles {
+				marker := " "
+				if profile.Active {
+					marker = "*"
+				}
+				fmt.Printf("%s %s\n", marker, profile.Name)
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileSwitchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "switch <name>",
+		Short: "Make a profile the active one",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			if err := repo.SwitchProfile(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Switched to profile '%s'\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newProfileDeleteCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile and all of its progress",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			if !yes {
+				fmt.Printf("Delete profile '%s' with its attempt history, presets and saved session? (yes/no): ", args[0])
+				var response string
+				fmt.Scanln(&response)
+
+				if response != "yes" && response != "y" && response != "Y" {
+					fmt.Println("Deletion cancelled.")
+					return nil
+				}
+			}
+
+			if err := repo.DeleteProfile(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Deleted profile '%s'\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete without asking for confirmation")
+
+	return cmd
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/gataky/greekmaster/internal/ai"
+	"github.com/gataky/greekmaster/internal/config"
+	"github.com/gataky/greekmaster/internal/logging"
+	"github.com/gataky/greekmaster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// Global flags shared by every command
+var (
+	profileName string
+	dbPath      string
+	configPath  string
+	verbose     bool
+	quiet       bool
+	logFormat   string
+)
+
+// settings holds the configuration, loaded before any command runs
+var settings = config.Default()
+
+// logFile is the open log file, closed after the command finishes
+var logFile io.Closer
+
+// AddGlobalFlags registers the flags shared by every command and loads the
+// configuration before each command runs
+func AddGlobalFlags(root *cobra.Command) {
+	root.PersistentFlags().StringVar(&dbPath, "db-path", "", "Path to database file (default: greekmaster.db in the data directory, ~/.greekmaster)")
+	root.PersistentFlags().StringVar(&configPath, "config", "", "Path to config file (default: $XDG_CONFIG_HOME/greekmaster/config.yaml)")
+	root.PersistentFlags().StringVar(&profileName, "profile", "", "Profile to practise as (default: the active profile, see 'greekmaster profile list')")
+	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log debug details, including AI requests and responses, to stderr and the log file")
+	root.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only log warnings and errors, and only to the log file")
+	root.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log record format, text or json (default: log_format setting)")
+
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := loadSettings(); err != nil {
+			return err
+		}
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		slog.Debug("Running command", "command", cmd.CommandPath(), "args", args)
+		return nil
+	}
+	root.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		if logFile == nil {
+			return nil
+		}
+		return logFile.Close()
+	}
+}
+
+// settingsPath returns the config file chosen with --config, or the default one
+func settingsPath() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	return config.DefaultPath()
+}
+
+// loadSettings reads the config file and environment overrides
+func loadSettings() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	loaded, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	settings = loaded
+	return nil
+}
+
+// setupLogging installs the logger chosen by --verbose, --quiet, --log-format
+// and the configured log file
+func setupLogging() error {
+	if verbose && quiet {
+		return errors.New("--verbose and --quiet cannot be used together")
+	}
+
+	verbosity := logging.Normal
+	switch {
+	case verbose:
+		verbosity = logging.Verbose
+	case quiet:
+		verbosity = logging.Quiet
+	}
+
+	format := settings.LogFormat
+	if logFormat != "" {
+		format = logFormat
+	}
+
+	path, err := settings.LogFile()
+	if err != nil {
+		return err
+	}
+
+	_, closer, err := logging.Setup(logging.Options{
+		Path:      path,
+		Format:    format,
+		Verbosity: verbosity,
+	})
+	if err != nil {
+		return err
+	}
+
+	logFile = closer
+	return nil
+}
+
+// databasePath returns the database chosen with --db-path, or the one in the
+// configured data directory, which is created if needed
+func databasePath() (string, error) {
+	if dbPath != "" {
+		return dbPath, nil
+	}
+
+	path, err := settings.DatabasePath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	return path, nil
+}
+
+// openDatabase opens the database for commands that only touch the shared
+// noun and template catalog
+func openDatabase() (*storage.SQLiteRepository, error) {
+	path, err := databasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := storage.NewSQLiteRepository(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	return repo, nil
+}
+
+// openRepository opens the database for the profile chosen with --profile, or
+// the active profile
+func openRepository() (*storage.SQLiteRepository, error) {
+	repo, err := openDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	if profileName != "" {
+		if err := repo.UseProfile(profileName); err != nil {
+			repo.Close()
+			return nil, fmt.Errorf("%w. Create it with 'greekmaster profile create %s'", err, profileName)
+		}
+	}
+
+	return repo, nil
+}
+
+// newClaudeClient creates an API client using the configured model, token
+// limit and retries
+func newClaudeClient() (*ai.ClaudeClient, error) {
+	client, err := ai.NewClaudeClient(ai.Options{
+		Model:     settings.Model,
+		MaxTokens: settings.MaxTokens,
+		Retries:   settings.Retries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Claude API client: %w\n\nMake sure ANTHROPIC_API_KEY environment variable is set", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/server"
+	"github.com/gataky/greekmaster/internal/web"
+	"github.com/spf13/cobra"
+)
+
+// NewServeCmd creates the serve command
+func NewServeCmd() *cobra.Command {
+	var addr string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the browser practice UI and a local HTTP/JSON API",
+		Long: `Start an HTTP server with a browser version of the practice session and
+a JSON API exposing nouns, sentence generation, answer grading with
+explanations, and progress statistics.
+
+Open the printed address in a browser to practise. The server runs the
+same session as 'greekmaster practice' in sentence or choice mode, with
+countdowns, sprints, review rounds and resuming; the browser only shows
+it. Reverse and table drills are terminal only.
+
+Answers are recorded for the active profile, or the one given with
+--profile. The OpenAPI description of the API is served at
+/api/openapi.yaml.
+
+The server listens on localhost only unless another address is given.
+
+Example:
+  greekmaster serve --addr localhost:8080
+  curl 'http://localhost:8080/api/sentences?case=genitive&count=5'`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := openRepository()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			mux := http.NewServeMux()
+			mux.Handle("/api/", server.New(repo))
+			mux.Handle("/", web.Handler())
+
+			httpServer := &http.Server{
+				Addr:              addr,
+				Handler:           logRequests(mux),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			// Shut down cleanly on Ctrl+C
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				httpServer.Shutdown(shutdownCtx)
+			}()
+
+			fmt.Printf("Practise in your browser at http://%s (Ctrl+C to stop)\n", addr)
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("server error: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+
+	return cmd
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs each request at debug level, and server errors as errors
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		level := slog.LevelDebug
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(r.Context(), level, "HTTP request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", time.Since(start),
+		)
+	})
+}
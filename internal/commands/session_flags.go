@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// sessionModes lists the exercise types a session can run
+var sessionModes = []string{"sentence", "choice", "reverse", "table"}
+
+// difficultyLevels lists the accepted --difficulty values
+var difficultyLevels = []string{"beginner", "intermediate", "advanced", "all"}
+
+// configFlags lists the flags that describe a session; setting any of them skips setup
+var configFlags = []string{"difficulty", "plural", "count", "gender", "case", "preposition", "context", "nouns", "tag"}
+
+// sessionFlags holds the command-line description of a practice session
+type sessionFlags struct {
+	mode       string
+	difficulty string
+	plural     bool
+	count      int
+	filter     models.SessionFilter
+	nounRanges string
+	timeLimit  time.Duration
+	sprint     time.Duration
+}
+
+// addSessionFlags registers the session configuration flags on a command
+func addSessionFlags(cmd *cobra.Command, f *sessionFlags) {
+	cmd.Flags().StringVar(&f.mode, "mode", "sentence", "Exercise type (sentence, choice, reverse, table)")
+	cmd.Flags().StringVar(&f.difficulty, "difficulty", "all", "Difficulty level (beginner, intermediate, advanced, all)")
+	cmd.Flags().BoolVar(&f.plural, "plural", false, "Include plural forms")
+	cmd.Flags().IntVar(&f.count, "count", defaultQuestionCount, "Number of questions (0 for endless)")
+	cmd.Flags().StringSliceVar(&f.filter.Genders, "gender", nil, "Only nouns of these genders (masculine, feminine, neuter, invariable)")
+	cmd.Flags().StringSliceVar(&f.filter.Cases, "case", nil, "Only these cases (nominative, genitive, accusative)")
+	cmd.Flags().StringSliceVar(&f.filter.Prepositions, "preposition", nil, "Only templates with these prepositions (e.g. σε, για)")
+	cmd.Flags().StringSliceVar(&f.filter.ContextTypes, "context", nil, "Only these contexts (direct_object, indirect_object, possession, preposition, time)")
+	cmd.Flags().StringVar(&f.nounRanges, "nouns", "", "Only nouns with these IDs (e.g. 1-50,60,70-80)")
+	cmd.Flags().StringSliceVar(&f.filter.Tags, "tag", nil, "Only nouns with any of these semantic tags (e.g. person, food)")
+	cmd.Flags().DurationVar(&f.timeLimit, "time-limit", 0, "Time allowed per question, e.g. 10s (0 for no countdown)")
+	cmd.Flags().DurationVar(&f.sprint, "sprint", 0, "Answer as many questions as possible in this time, e.g. 2m")
+}
+
+// configured reports whether any session configuration flag was given
+func (f *sessionFlags) configured(cmd *cobra.Command) bool {
+	return slices.ContainsFunc(configFlags, cmd.Flags().Changed)
+}
+
+// given reports whether any session flag at all was given, including the mode and timing
+func (f *sessionFlags) given(cmd *cobra.Command) bool {
+	changed := cmd.Flags().Changed
+	return f.configured(cmd) || changed("mode") || changed("time-limit") || changed("sprint")
+}
+
+// apply validates the flags and writes the ones given on the command line over config
+func (f *sessionFlags) apply(cmd *cobra.Command, config *models.SessionConfig) error {
+	changed := cmd.Flags().Changed
+
+	if changed("mode") {
+		if !slices.Contains(sessionModes, f.mode) {
+			return fmt.Errorf("invalid mode '%s', must be one of: sentence, choice, reverse, table", f.mode)
+		}
+		config.Mode = f.mode
+	}
+
+	if changed("difficulty") {
+		if !slices.Contains(difficultyLevels, f.difficulty) {
+			return fmt.Errorf("invalid difficulty '%s', must be one of: beginner, intermediate, advanced, all", f.difficulty)
+		}
+		config.DifficultyLevel = f.difficulty
+		if f.difficulty == "all" {
+			config.DifficultyLevel = ""
+		}
+	}
+
+	if changed("plural") {
+		config.IncludePlural = f.plural
+	}
+
+	if changed("count") {
+		if f.count < 0 {
+			return fmt.Errorf("count must be 0 (endless) or greater")
+		}
+		config.QuestionCount = f.count
+	}
+
+	filter := &config.Filter
+	if changed("gender") {
+		filter.Genders = f.filter.Genders
+	}
+	if changed("case") {
+		filter.Cases = f.filter.Cases
+	}
+	if changed("preposition") {
+		filter.Prepositions = f.filter.Prepositions
+	}
+	if changed("context") {
+		filter.ContextTypes = f.filter.ContextTypes
+	}
+	if changed("tag") {
+		filter.Tags = models.NormalizeTags(f.filter.Tags)
+	}
+	if changed("nouns") {
+		ranges, err := models.ParseIDRanges(f.nounRanges)
+		if err != nil {
+			return fmt.Errorf("invalid --nouns: %w", err)
+		}
+		filter.NounRanges = ranges
+	}
+
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	if changed("time-limit") {
+		seconds, err := wholeSeconds("time-limit", f.timeLimit)
+		if err != nil {
+			return err
+		}
+		config.TimeLimit = seconds
+	}
+	if changed("sprint") {
+		seconds, err := wholeSeconds("sprint", f.sprint)
+		if err != nil {
+			return err
+		}
+		config.SprintSeconds = seconds
+		// A sprint runs until time is up unless a length is also given
+		if seconds > 0 && !changed("count") {
+			config.QuestionCount = 0
+		}
+	}
+	if config.IsTimed() && (config.Mode == "reverse" || config.Mode == "table") {
+		return fmt.Errorf("timed sessions are only available in sentence and choice modes")
+	}
+
+	return nil
+}
+
+// wholeSeconds converts a timing flag to seconds, rejecting negative or sub-second values
+func wholeSeconds(name string, d time.Duration) (int, error) {
+	if d < 0 || (d > 0 && d < time.Second) {
+		return 0, fmt.Errorf("--%s must be 0 or at least 1s", name)
+	}
+	return int(d / time.Second), nil
+}
+
+// defaultSessionConfig returns the configuration of a session started without setup
+func defaultSessionConfig() models.SessionConfig {
+	return models.SessionConfig{
+		QuestionCount: defaultQuestionCount,
+		Mode:          "sentence",
+	}
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/ai"
+	"github.com/gataky/greekmaster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// NewTagCmd creates the tag command
+func NewTagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Fill in missing semantic tags with AI",
+		Long: `Classify nouns and templates that haven't been tagged yet.
+
+Nouns are tagged with what they are (person, animal, place, food, object,
+abstract, time) and whether they are mass or count nouns. Templates are
+tagged with the kinds of noun that make sense in their blank; a template
+that accepts any noun is remembered as tagged without any. Practice
+sessions use the tags to avoid pairing templates with nonsensical nouns.
+
+Nouns added with 'import' or 'add' are tagged automatically; this command
+is for data created before tagging existed.
+
+This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize repository
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			// Initialize Claude client
+			client, err := newClaudeClient()
+			if err != nil {
+				return err
+			}
+
+			tagged, failed, err := tagMissing(repo, client)
+			if err != nil {
+				return err
+			}
+
+			slog.Info("Tagging finished", "tagged", tagged, "failed", failed)
+			fmt.Printf("\n✓ Tagged %d items\n", tagged)
+			if failed > 0 {
+				fmt.Printf("%d items could not be tagged; run 'greekmaster tag' again to retry them\n", failed)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// tagMissing classifies the nouns and templates that haven't been tagged yet. It
+// returns how many were tagged and how many failed; failures are reported and skipped.
+func tagMissing(repo *storage.SQLiteRepository, client *ai.ClaudeClient) (tagged, failed int, err error) {
+	nouns, err := repo.ListNouns()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list nouns: %w", err)
+	}
+	nounTags, err := repo.ListNounTags()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list noun tags: %w", err)
+	}
+
+	templates, err := repo.ListTemplates()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	for _, noun := range nouns {
+		if len(nounTags[noun.ID]) > 0 {
+			continue
+		}
+
+		fmt.Printf("Noun '%s' (%s)... ", noun.English, noun.NominativeSg)
+		tags, err := client.GenerateNounTags(noun.NominativeSg, noun.English)
+		if err != nil {
+			fmt.Printf("FAILED: %v\n", err)
+			failed++
+			slog.Warn("Tag generation failed", "noun", noun.NominativeSg, "error", err)
+			continue
+		}
+		if err := repo.SetNounTags(noun.ID, tags); err != nil {
+			fmt.Printf("FAILED: %v\n", err)
+			failed++
+			continue
+		}
+		tagged++
+		fmt.Printf("%s ✓\n", strings.Join(tags, ", "))
+	}
+
+	// Templates that accept any noun store no tags but are marked as classified
+	for _, template := range templates {
+		if template.TaggedAt != nil {
+			continue
+		}
+
+		fmt.Printf("Template %d '%s'... ", template.ID, template.EnglishTemplate)
+		tags, err := client.GenerateTemplateTags(template.EnglishTemplate, template.GreekTemplate)
+		if err != nil {
+			fmt.Printf("FAILED: %v\n", err)
+			failed++
+			slog.Warn("Tag generation failed", "template", template.ID, "error", err)
+			continue
+		}
+		if err := repo.SetTemplateTags(template.ID, tags); err != nil {
+			fmt.Printf("FAILED: %v\n", err)
+			failed++
+			continue
+		}
+		tagged++
+		fmt.Printf("%s ✓\n", strings.Join(tags, ", "))
+	}
+
+	return tagged, failed, nil
+}
@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+// contextPhases maps a template context type to its difficulty phase
+var contextPhases = map[string]int{
+	"direct_object":   1,
+	"indirect_object": 2,
+	"possession":      2,
+	"preposition":     3,
+	"time":            3,
+}
+
+// validationNounCount is how many stored nouns each generated template is tested against
+const validationNounCount = 5
+
+// NewTemplateCmd creates the template command
+func NewTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage sentence templates",
+		Long:  `Manage the sentence templates used to generate practice questions.`,
+	}
+
+	cmd.AddCommand(newTemplateGenerateCmd())
+
+	return cmd
+}
+
+func newTemplateGenerateCmd() *cobra.Command {
+	var caseType string
+	var contextType string
+	var number string
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate new sentence templates with AI",
+		Long: `Ask Claude for new English/Greek sentence templates for a case and context.
+
+Each generated template is validated by substituting several stored nouns and
+checking that the correct answer appears verbatim in the Greek sentence.
+Only templates that pass validation are stored.
+
+Example:
+  greekmaster template generate --case genitive --context possession --count 20
+  greekmaster template generate --case genitive --context indirect_object --count 10
+
+This command requires the ANTHROPIC_API_KEY environment variable to be set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			phase, ok := contextPhases[contextType]
+			if !ok {
+				return fmt.Errorf("invalid context '%s', must be one of: %s", contextType, strings.Join(models.FilterValues("context"), ", "))
+			}
+
+			// Templates for both numbers store their singular fields
+			fieldNumber := number
+			if number == "both" {
+				fieldNumber = "singular"
+			}
+			articleField, nounFormField, err := storage.TemplateFields(caseType, fieldNumber)
+			if err != nil {
+				return fmt.Errorf("invalid template options: %w", err)
+			}
+
+			if count <= 0 {
+				return fmt.Errorf("count must be greater than 0")
+			}
+
+			// Initialize repository
+			repo, err := openDatabase()
+			if err != nil {
+				return err
+			}
+			defer repo.Close()
+
+			nouns, err := repo.ListNouns()
+			if err != nil {
+				return fmt.Errorf("failed to list nouns: %w", err)
+			}
+			if len(nouns) == 0 {
+				return fmt.Errorf("no nouns found in database. Please run 'greekmaster import <csv-file>' first")
+			}
+
+			// Pick a sample of nouns to validate against
+			sample := make([]*models.Noun, 0, validationNounCount)
+			for _, i := range rand.Perm(len(nouns)) {
+				if len(sample) == validationNounCount {
+					break
+				}
+				sample = append(sample, nouns[i])
+			}
+
+			// Load existing templates to skip duplicates
+			existing, err := repo.ListTemplates()
+			if err != nil {
+				return fmt.Errorf("failed to list templates: %w", err)
+			}
+			seen := make(map[string]bool)
+			for _, t := range existing {
+				seen[t.GreekTemplate] = true
+			}
+
+			// Initialize Claude client
+			client, err := newClaudeClient()
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Generating %d %s %s templates (%s)... ", count, caseType, contextType, number)
+			generated, err := client.GenerateTemplates(caseType, contextType, number, count)
+			if err != nil {
+				fmt.Println("FAILED")
+				return fmt.Errorf("failed to generate templates: %w", err)
+			}
+			fmt.Println("✓")
+			fmt.Println()
+
+			stored := 0
+			for i, g := range generated {
+				template := &models.SentenceTemplate{
+					EnglishTemplate: g.EnglishTemplate,
+					GreekTemplate:   g.GreekTemplate,
+					ArticleField:    articleField,
+					NounFormField:   nounFormField,
+					CaseType:        caseType,
+					Number:          number,
+					DifficultyPhase: phase,
+					ContextType:     contextType,
+				}
+				if contextType == "preposition" {
+					template.Preposition = g.Preposition
+				}
+
+				fmt.Printf("[%d/%d] %s\n", i+1, len(generated), g.GreekTemplate)
+
+				if seen[template.GreekTemplate] {
+					fmt.Println("  → Skipped (duplicate)")
+					continue
+				}
+
+				if err := storage.ValidateTemplate(template, sample); err != nil {
+					fmt.Printf("  → Rejected: %v\n", err)
+					slog.Info("Rejected generated template", "template", template.GreekTemplate, "reason", err)
+					continue
+				}
+
+				if err := repo.CreateTemplate(template); err != nil {
+					fmt.Printf("  → Error storing template: %v\n", err)
+					continue
+				}
+
+				if err := repo.SetTemplateTags(template.ID, g.RequiredTags); err != nil {
+					fmt.Printf("  → Warning: failed to store tags: %v\n", err)
+				}
+
+				seen[template.GreekTemplate] = true
+				stored++
+				fmt.Println("  → Stored ✓")
+			}
+
+			slog.Info("Template generation finished", "case", caseType, "context", contextType, "stored", stored, "generated", len(generated))
+			fmt.Printf("\n✓ Stored %d of %d generated templates\n", stored, len(generated))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&caseType, "case", "", "Case of the missing noun (nominative, genitive, accusative)")
+	cmd.Flags().StringVar(&contextType, "context", "", "Context type (direct_object, indirect_object, possession, preposition, time)")
+	cmd.Flags().StringVar(&number, "number", "singular", "Number of the missing noun (singular, plural, both)")
+	cmd.Flags().IntVar(&count, "count", 10, "Number of templates to request")
+	cmd.MarkFlagRequired("case")
+	cmd.MarkFlagRequired("context")
+
+	return cmd
+}
@@ -0,0 +1,326 @@
+// Package config loads user settings from the config file and environment
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults for settings missing from the config file and environment
+const (
+	DefaultModel      = "claude-sonnet-4-6"
+	DefaultMaxTokens  = 2000
+	DefaultRetries    = 3
+	DefaultBackupKeep = 10
+)
+
+// PathEnv overrides the location of the config file
+const PathEnv = "GREEKMASTER_CONFIG"
+
+// Config holds the user's settings. Empty paths mean the default location.
+type Config struct {
+	Model      string `yaml:"model"`       // Claude model used for imports, templates and tags
+	MaxTokens  int    `yaml:"max_tokens"`  // Token limit of each AI response
+	Retries    int    `yaml:"retries"`     // Times a failed AI request is retried
+	DataDir    string `yaml:"data_dir"`    // Directory holding the database (default: ~/.greekmaster)
+	LogPath    string `yaml:"log_path"`    // Log file (default: greekmaster.log in the data directory)
+	LogFormat  string `yaml:"log_format"`  // Log record format, text or json
+	BackupKeep int    `yaml:"backup_keep"` // Database snapshots kept in the data directory
+}
+
+// Default returns the built-in settings
+func Default() *Config {
+	return &Config{
+		Model:      DefaultModel,
+		MaxTokens:  DefaultMaxTokens,
+		Retries:    DefaultRetries,
+		LogFormat:  "text",
+		BackupKeep: DefaultBackupKeep,
+	}
+}
+
+// setting describes one key of the config file
+type setting struct {
+	key         string
+	env         string
+	description string
+	get         func(c *Config) string
+	set         func(c *Config, value string) error
+}
+
+// settings lists the config keys in display order
+var settings = []setting{
+	{
+		key: "model", env: "GREEKMASTER_MODEL",
+		description: "Claude model used for imports, templates and tags",
+		get:         func(c *Config) string { return c.Model },
+		set: func(c *Config, value string) error {
+			if strings.TrimSpace(value) == "" {
+				return errors.New("model cannot be empty")
+			}
+			c.Model = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key: "max_tokens", env: "GREEKMASTER_MAX_TOKENS",
+		description: "Token limit of each AI response",
+		get:         func(c *Config) string { return strconv.Itoa(c.MaxTokens) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid max_tokens '%s', must be a positive number", value)
+			}
+			c.MaxTokens = n
+			return nil
+		},
+	},
+	{
+		key: "retries", env: "GREEKMASTER_RETRIES",
+		description: "Times a failed AI request is retried",
+		get:         func(c *Config) string { return strconv.Itoa(c.Retries) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid retries '%s', must be 0 or more", value)
+			}
+			c.Retries = n
+			return nil
+		},
+	},
+	{
+		key: "data_dir", env: "GREEKMASTER_DATA_DIR",
+		description: "Directory holding the database",
+		get:         func(c *Config) string { return c.DataDir },
+		set: func(c *Config, value string) error {
+			c.DataDir = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key: "log_path", env: "GREEKMASTER_LOG_PATH",
+		description: "Log file",
+		get:         func(c *Config) string { return c.LogPath },
+		set: func(c *Config, value string) error {
+			c.LogPath = strings.TrimSpace(value)
+			return nil
+		},
+	},
+	{
+		key: "log_format", env: "GREEKMASTER_LOG_FORMAT",
+		description: "Log record format, text or json",
+		get:         func(c *Config) string { return c.LogFormat },
+		set: func(c *Config, value string) error {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value != "text" && value != "json" {
+				return fmt.Errorf("invalid log_format '%s', must be text or json", value)
+			}
+			c.LogFormat = value
+			return nil
+		},
+	},
+	{
+		key: "backup_keep", env: "GREEKMASTER_BACKUP_KEEP",
+		description: "Database snapshots kept in the data directory",
+		get:         func(c *Config) string { return strconv.Itoa(c.BackupKeep) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid backup_keep '%s', must be a positive number", value)
+			}
+			c.BackupKeep = n
+			return nil
+		},
+	},
+}
+
+// lookup finds a setting by key
+func lookup(key string) (setting, error) {
+	for _, s := range settings {
+		if s.key == key {
+			return s, nil
+		}
+	}
+	return setting{}, fmt.Errorf("unknown config key '%s', must be one of: %s", key, strings.Join(Keys(), ", "))
+}
+
+// Keys lists the config keys
+func Keys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// EnvVar returns the environment variable overriding a key
+func EnvVar(key string) string {
+	s, err := lookup(key)
+	if err != nil {
+		return ""
+	}
+	return s.env
+}
+
+// Description explains what a key controls
+func Description(key string) string {
+	s, err := lookup(key)
+	if err != nil {
+		return ""
+	}
+	return s.description
+}
+
+// Get returns the value of a key as it would be written in the config file
+func (c *Config) Get(key string) (string, error) {
+	s, err := lookup(key)
+	if err != nil {
+		return "", err
+	}
+	return s.get(c), nil
+}
+
+// Set parses and stores the value of a key
+func (c *Config) Set(key, value string) error {
+	s, err := lookup(key)
+	if err != nil {
+		return err
+	}
+	return s.set(c, value)
+}
+
+// DefaultPath returns the config file location: $GREEKMASTER_CONFIG, or
+// greekmaster/config.yaml in the XDG config directory
+func DefaultPath() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "greekmaster", "config.yaml"), nil
+}
+
+// ReadFile reads the settings saved in a config file over the defaults. A
+// missing file is not an error.
+func ReadFile(path string) (*Config, error) {
+	c := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Load reads a config file and applies the environment overrides
+func Load(path string) (*Config, error) {
+	c, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(s.env); ok {
+			if err := s.set(c, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// validate checks values read from the config file
+func (c *Config) validate() error {
+	for _, s := range settings {
+		if err := s.set(c, s.get(c)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save writes the settings to a config file, creating its directory
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// DataDirectory returns the data directory, expanding a leading ~
+func (c *Config) DataDirectory() (string, error) {
+	if c.DataDir != "" {
+		return expandHome(c.DataDir)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".greekmaster"), nil
+}
+
+// DatabasePath returns the database file in the data directory
+func (c *Config) DatabasePath() (string, error) {
+	dir, err := c.DataDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "greekmaster.db"), nil
+}
+
+// LogFile returns the log file, greekmaster.log in the data directory by default
+func (c *Config) LogFile() (string, error) {
+	if c.LogPath != "" {
+		return expandHome(c.LogPath)
+	}
+
+	dir, err := c.DataDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "greekmaster.log"), nil
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadFileMissing(t *testing.T) {
+	c, err := ReadFile(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if *c != *Default() {
+		t.Errorf("ReadFile() = %+v, want defaults", c)
+	}
+}
+
+func TestSaveAndReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greekmaster", "config.yaml")
+
+	c := Default()
+	if err := c.Set("model", "claude-opus-4-1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := c.Set("retries", "0"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got.Model != "claude-opus-4-1" || got.Retries != 0 || got.MaxTokens != DefaultMaxTokens {
+		t.Errorf("ReadFile() = %+v, want saved model, 0 retries and default max tokens", got)
+	}
+}
+
+func TestReadFilePartial(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("max_tokens: 4000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if c.MaxTokens != 4000 || c.Model != DefaultModel || c.Retries != DefaultRetries {
+		t.Errorf("ReadFile() = %+v, want max_tokens 4000 and other defaults", c)
+	}
+}
+
+func TestReadFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"bad yaml", "model: [", "invalid config file"},
+		{"negative retries", "retries: -1", "invalid retries"},
+		{"zero max tokens", "max_tokens: 0", "invalid max_tokens"},
+		{"empty model", "model: ''", "model cannot be empty"},
+		{"unknown log format", "log_format: xml", "invalid log_format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := ReadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadFile() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("model: from-file\nretries: 5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("GREEKMASTER_MODEL", "from-env")
+	t.Setenv("GREEKMASTER_DATA_DIR", "/srv/greek")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Model != "from-env" {
+		t.Errorf("Model = %s, want the environment's from-env", c.Model)
+	}
+	if c.Retries != 5 {
+		t.Errorf("Retries = %d, want 5 from the file", c.Retries)
+	}
+	if db, _ := c.DatabasePath(); db != filepath.Join("/srv/greek", "greekmaster.db") {
+		t.Errorf("DatabasePath() = %s, want it in the environment's data dir", db)
+	}
+
+	t.Setenv("GREEKMASTER_MAX_TOKENS", "lots")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "GREEKMASTER_MAX_TOKENS") {
+		t.Errorf("Load() error = %v, want it to name GREEKMASTER_MAX_TOKENS", err)
+	}
+}
+
+func TestDefaultPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Setenv(PathEnv, "")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error = %v", err)
+	}
+	if want := filepath.Join(home, "xdg", "greekmaster", "config.yaml"); path != want {
+		t.Errorf("DefaultPath() = %s, want %s", path, want)
+	}
+
+	c := Default()
+	if db, _ := c.DatabasePath(); db != filepath.Join(home, ".greekmaster", "greekmaster.db") {
+		t.Errorf("DatabasePath() = %s, want the database in ~/.greekmaster", db)
+	}
+	if log, _ := c.LogFile(); log != filepath.Join(home, ".greekmaster", "greekmaster.log") {
+		t.Errorf("LogFile() = %s, want greekmaster.log in ~/.greekmaster", log)
+	}
+
+	c.LogPath = "~/logs/gm.log"
+	if log, _ := c.LogFile(); log != filepath.Join(home, "logs", "gm.log") {
+		t.Errorf("LogFile() = %s, want ~ expanded", log)
+	}
+}
+
+func TestUnknownKey(t *testing.T) {
+	c := Default()
+	if _, err := c.Get("colour"); err == nil || !strings.Contains(err.Error(), "model, max_tokens") {
+		t.Errorf("Get() error = %v, want the list of keys", err)
+	}
+	if err := c.Set("colour", "blue"); err == nil {
+		t.Error("Set() should reject unknown keys")
+	}
+}
@@ -0,0 +1,571 @@
+// Package doctor finds and corrects mistakes in AI-generated nouns and templates
+package doctor
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// Kinds of problem reported by the checks
+const (
+	KindArticleGender      = "article-gender"
+	KindMultipleTonos      = "multiple-tonos"
+	KindLatinLetters       = "latin-letters"
+	KindEmptyPlural        = "empty-plural"
+	KindUnknownField       = "unknown-field"
+	KindFieldMismatch      = "field-mismatch"
+	KindMissingPlaceholder = "missing-placeholder"
+)
+
+// Issue is a problem found in a noun or template
+type Issue struct {
+	Kind       string
+	NounID     int64  // Noun with the problem, or 0
+	TemplateID int64  // Template with the problem, or 0
+	Subject    string // The noun or template, for display
+	Problem    string // What is wrong
+	Fix        string // The automatic correction, empty if it needs a person
+	Field      string // Noun or template field a person can correct, if any
+
+	fixNoun     func(*models.Noun)
+	fixTemplate func(*models.SentenceTemplate)
+}
+
+// Fixable reports whether the issue has an automatic correction
+func (i *Issue) Fixable() bool {
+	return i.fixNoun != nil || i.fixTemplate != nil
+}
+
+// FieldLabel names the issue's correctable field for prompts
+func (i *Issue) FieldLabel() string {
+	if label, ok := fieldLabels[i.Field]; ok {
+		return label
+	}
+	return i.Field
+}
+
+// nounSlot is a case and number with its article and form fields
+type nounSlot struct {
+	name    string
+	article string
+	form    string
+	plural  bool
+}
+
+// nounSlots lists the six cells of a declension table in the order articles are listed
+var nounSlots = []nounSlot{
+	{"nominative singular", "NomSgArticle", "NominativeSg", false},
+	{"genitive singular", "GenSgArticle", "GenitiveSg", false},
+	{"accusative singular", "AccSgArticle", "AccusativeSg", false},
+	{"nominative plural", "NomPlArticle", "NominativePl", true},
+	{"genitive plural", "GenPlArticle", "GenitivePl", true},
+	{"accusative plural", "AccPlArticle", "AccusativePl", true},
+}
+
+// genderArticles lists the definite articles of each gender in nounSlots order
+var genderArticles = map[string][]string{
+	"masculine": {"ο", "του", "τον", "οι", "των", "τους"},
+	"feminine":  {"η", "της", "την", "οι", "των", "τις"},
+	"neuter":    {"το", "του", "το", "τα", "των", "τα"},
+}
+
+// fieldLabels describes the fields a person may be asked to correct
+var fieldLabels = map[string]string{
+	"EnglishTemplate": "English template",
+	"GreekTemplate":   "Greek template",
+	"CaseType":        "case (nominative, genitive or accusative)",
+	"Number":          "number (singular or plural)",
+}
+
+func init() {
+	for _, slot := range nounSlots {
+		fieldLabels[slot.form] = slot.name
+		fieldLabels[slot.article] = slot.name + " article"
+	}
+}
+
+// Scan checks every noun and template in the repository
+func Scan(repo storage.Repository) ([]*Issue, error) {
+	nouns, err := repo.ListNouns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nouns: %w", err)
+	}
+	tags, err := repo.ListNounTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list noun tags: %w", err)
+	}
+	templates, err := repo.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	var issues []*Issue
+	for _, noun := range nouns {
+		issues = append(issues, CheckNoun(noun, tags[noun.ID])...)
+	}
+	for _, template := range templates {
+		issues = append(issues, CheckTemplate(template)...)
+	}
+	return issues, nil
+}
+
+// CheckNoun finds problems in a noun's articles and forms. Nouns tagged "mass"
+// may have no plural.
+func CheckNoun(noun *models.Noun, tags []string) []*Issue {
+	subject := fmt.Sprintf("noun %d (%s, %s)", noun.ID, noun.NominativeSg, noun.English)
+	newIssue := func(kind, problem string) *Issue {
+		return &Issue{Kind: kind, NounID: noun.ID, Subject: subject, Problem: problem}
+	}
+
+	var issues []*Issue
+	if issue := checkArticles(noun); issue != nil {
+		issue.NounID, issue.Subject = noun.ID, subject
+		issues = append(issues, issue)
+	}
+
+	countable := !containsTag(tags, "mass")
+	for _, slot := range nounSlots {
+		for _, field := range []string{slot.article, slot.form} {
+			value := stringField(noun, field)
+
+			if latin := latinLetters(value); len(latin) > 0 {
+				issue := newIssue(KindLatinLetters, fmt.Sprintf("%s '%s' contains the Latin letters %s", fieldLabels[field], value, string(latin)))
+				issue.Field = field
+				if fixed, ok := replaceLookalikes(value); ok {
+					issue.Fix = fmt.Sprintf("change it to '%s'", fixed)
+					issue.fixNoun = func(n *models.Noun) {
+						if fixed, ok := replaceLookalikes(stringField(n, field)); ok {
+							setStringField(n, field, fixed)
+						}
+					}
+				}
+				issues = append(issues, issue)
+			}
+
+			if word := overaccented(value); word != "" {
+				issue := newIssue(KindMultipleTonos, fmt.Sprintf("%s '%s' has more than one accent mark in '%s'", fieldLabels[field], value, word))
+				issue.Field = field
+				issues = append(issues, issue)
+			}
+		}
+
+		if slot.plural && countable && strings.TrimSpace(stringField(noun, slot.form)) == "" {
+			issue := newIssue(KindEmptyPlural, fmt.Sprintf("%s is empty, but the noun is not tagged as a mass noun", slot.name))
+			issue.Field = slot.form
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// checkArticles compares a noun's articles with those of its gender. When every
+// article belongs to another gender the gender is assumed wrong, otherwise the
+// articles are.
+func checkArticles(noun *models.Noun) *Issue {
+	gender := grammar.GrammaticalGender(noun)
+	expected, ok := genderArticles[gender]
+	if !ok {
+		return nil // Common-gender invariable nouns such as ο/η γιατρός
+	}
+
+	var wrong []string
+	for i, slot := range nounSlots {
+		article := stringField(noun, slot.article)
+		if slot.plural && article == "" && stringField(noun, slot.form) == "" {
+			continue // No plural
+		}
+		if !articleMatches(article, expected[i], stringField(noun, slot.form)) {
+			wrong = append(wrong, fmt.Sprintf("'%s' for the %s should be '%s'", article, slot.name, expected[i]))
+		}
+	}
+	if len(wrong) == 0 {
+		return nil
+	}
+
+	issue := &Issue{
+		Kind:    KindArticleGender,
+		Problem: fmt.Sprintf("articles don't match the %s gender: %s", gender, strings.Join(wrong, ", ")),
+	}
+
+	if noun.Gender != "invariable" {
+		if other := articlesGender(noun); other != "" {
+			issue.Fix = fmt.Sprintf("change the gender to %s", other)
+			issue.fixNoun = func(n *models.Noun) {
+				if other := articlesGender(n); other != "" {
+					n.Gender = other
+				}
+			}
+			return issue
+		}
+	}
+
+	issue.Fix = fmt.Sprintf("set the articles to %s", strings.Join(expected, ", "))
+	issue.fixNoun = func(n *models.Noun) {
+		expected := genderArticles[grammar.GrammaticalGender(n)]
+		for i, slot := range nounSlots {
+			article := stringField(n, slot.article)
+			if expected == nil || (slot.plural && article == "" && stringField(n, slot.form) == "") {
+				continue
+			}
+			if !articleMatches(article, expected[i], stringField(n, slot.form)) {
+				setStringField(n, slot.article, expected[i])
+			}
+		}
+	}
+	return issue
+}
+
+// articlesGender returns the gender all of a noun's articles agree on, if any
+func articlesGender(noun *models.Noun) string {
+	for gender, expected := range genderArticles {
+		matches := true
+		for i, slot := range nounSlots {
+			if !articleMatches(stringField(noun, slot.article), expected[i], stringField(noun, slot.form)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return gender
+		}
+	}
+	return ""
+}
+
+// articleMatches reports whether an article is the expected one in front of form,
+// allowing τη for την where the ν may be dropped
+func articleMatches(article, expected, form string) bool {
+	return article == expected || (article != "" && article == grammar.ArticleVariant(expected, form))
+}
+
+// placeholderPattern matches template placeholders and the opening of agreement
+// slots, whose names are written in Latin letters
+var placeholderPattern = regexp.MustCompile(`\{\w+[:}]`)
+
+// templateFields maps each Noun article and form field to the case and number it holds
+var templateFields = map[string]string{}
+
+func init() {
+	for _, caseType := range []string{"nominative", "genitive", "accusative"} {
+		for _, number := range []string{"singular", "plural"} {
+			articleField, formField, _ := storage.TemplateFields(caseType, number)
+			templateFields[articleField] = caseType + " " + number
+			templateFields[formField] = caseType + " " + number
+		}
+	}
+}
+
+// CheckTemplate finds problems in a template's placeholders, fields and Greek text
+func CheckTemplate(template *models.SentenceTemplate) []*Issue {
+	subject := fmt.Sprintf("template %d (%s)", template.ID, template.GreekTemplate)
+	newIssue := func(kind, problem string) *Issue {
+		return &Issue{Kind: kind, TemplateID: template.ID, Subject: subject, Problem: problem}
+	}
+
+	var issues []*Issue
+
+	if !strings.Contains(template.EnglishTemplate, "{noun}") {
+		issue := newIssue(KindMissingPlaceholder, fmt.Sprintf("English template '%s' has no {noun} placeholder", template.EnglishTemplate))
+		issue.Field = "EnglishTemplate"
+		issues = append(issues, issue)
+	}
+	if problem := greekPlaceholderProblem(template.GreekTemplate); problem != "" {
+		issue := newIssue(KindMissingPlaceholder, problem)
+		issue.Field = "GreekTemplate"
+		issues = append(issues, issue)
+	}
+
+	if latin := latinLetters(template.GreekTemplate); len(latin) > 0 {
+		issue := newIssue(KindLatinLetters, fmt.Sprintf("Greek template contains the Latin letters %s", string(latin)))
+		issue.Field = "GreekTemplate"
+		if fixed, ok := replaceLookalikes(template.GreekTemplate); ok {
+			issue.Fix = fmt.Sprintf("change it to '%s'", fixed)
+			issue.fixTemplate = func(t *models.SentenceTemplate) {
+				if fixed, ok := replaceLookalikes(t.GreekTemplate); ok {
+					t.GreekTemplate = fixed
+				}
+			}
+		}
+		issues = append(issues, issue)
+	}
+
+	if issue := checkFields(template); issue != nil {
+		issue.TemplateID, issue.Subject = template.ID, subject
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// greekPlaceholderProblem describes what is wrong with a Greek template's
+// {article} {noun_form} placeholder, or returns the empty string
+func greekPlaceholderProblem(greek string) string {
+	var missing []string
+	for _, placeholder := range []string{"{article}", "{noun_form}"} {
+		switch strings.Count(greek, placeholder) {
+		case 0:
+			missing = append(missing, placeholder+" placeholder")
+		case 1:
+		default:
+			return fmt.Sprintf("Greek template '%s' has more than one %s placeholder", greek, placeholder)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("Greek template '%s' has no %s", greek, strings.Join(missing, " or "))
+	}
+	if !strings.Contains(greek, "{article} {noun_form}") {
+		return fmt.Sprintf("Greek template '%s' does not have {article} directly before {noun_form}", greek)
+	}
+	return ""
+}
+
+// checkFields compares a template's article and noun form fields with its case
+// and number. Fields that contradict each other or don't exist are derived from
+// the case and number; fields that agree with each other but not with the case or
+// number need a person to decide which is right.
+func checkFields(template *models.SentenceTemplate) *Issue {
+	number := templateNumber(template)
+	articleField, formField, err := storage.TemplateFields(template.CaseType, number)
+	if err != nil {
+		return nil // The schema only allows valid cases and numbers
+	}
+	if template.ArticleField == articleField && template.NounFormField == formField {
+		return nil
+	}
+
+	issue := &Issue{Kind: KindFieldMismatch}
+	var unknown []string
+	if problem := fieldProblem("article field", template.ArticleField, true); problem != "" {
+		unknown = append(unknown, problem)
+	}
+	if problem := fieldProblem("noun form field", template.NounFormField, false); problem != "" {
+		unknown = append(unknown, problem)
+	}
+
+	fieldsSay := templateFields[template.ArticleField]
+	if len(unknown) > 0 {
+		issue.Kind = KindUnknownField
+		issue.Problem = strings.Join(unknown, ", ")
+	} else if fieldsSay == templateFields[template.NounFormField] {
+		// The fields agree with each other, so either they or the labels are wrong
+		issue.Problem = fmt.Sprintf("fields %s and %s are %s, but the template is %s %s",
+			template.ArticleField, template.NounFormField, fieldsSay, template.CaseType, number)
+		issue.Field = "CaseType"
+		if strings.HasPrefix(fieldsSay, template.CaseType+" ") {
+			issue.Field = "Number"
+		}
+		return issue
+	} else {
+		issue.Problem = fmt.Sprintf("article field %s is %s but noun form field %s is %s",
+			template.ArticleField, fieldsSay, template.NounFormField, templateFields[template.NounFormField])
+	}
+
+	issue.Fix = fmt.Sprintf("use %s and %s for the %s %s", articleField, formField, template.CaseType, number)
+	issue.fixTemplate = setTemplateFields
+	return issue
+}
+
+// fieldProblem describes why a template field is not a Noun article (or form)
+// field, or returns the empty string
+func fieldProblem(name, field string, article bool) string {
+	if _, ok := templateFields[field]; ok && strings.HasSuffix(field, "Article") == article {
+		return ""
+	}
+	if _, ok := reflect.TypeOf(models.Noun{}).FieldByName(field); !ok {
+		return fmt.Sprintf("%s %s is not a field of Noun", name, field)
+	}
+	if article {
+		return fmt.Sprintf("%s %s is not a declined article", name, field)
+	}
+	return fmt.Sprintf("%s %s is not a declined noun form", name, field)
+}
+
+// templateNumber returns the number a template's fields should hold. Templates for
+// both numbers store the fields of one of them, judged by the noun form field.
+func templateNumber(template *models.SentenceTemplate) string {
+	if template.Number != "both" {
+		return template.Number
+	}
+	if strings.Contains(template.NounFormField, "Pl") {
+		return "plural"
+	}
+	return "singular"
+}
+
+// setTemplateFields derives a template's article and noun form fields from its
+// case and number
+func setTemplateFields(t *models.SentenceTemplate) {
+	if articleField, formField, err := storage.TemplateFields(t.CaseType, templateNumber(t)); err == nil {
+		t.ArticleField, t.NounFormField = articleField, formField
+	}
+}
+
+// Apply makes an issue's automatic correction to the current copy of its noun or template
+func Apply(repo storage.Repository, issue *Issue) error {
+	if !issue.Fixable() {
+		return fmt.Errorf("%s has no automatic fix", issue.Kind)
+	}
+	if issue.fixNoun != nil {
+		noun, err := repo.GetNoun(issue.NounID)
+		if err != nil {
+			return err
+		}
+		issue.fixNoun(noun)
+		return repo.UpdateNoun(noun)
+	}
+
+	template, err := repo.GetTemplate(issue.TemplateID)
+	if err != nil {
+		return err
+	}
+	issue.fixTemplate(template)
+	return repo.UpdateTemplate(template)
+}
+
+// Correct sets the issue's field to a value given by a person. Correcting a
+// template's case or number also updates its article and noun form fields.
+func Correct(repo storage.Repository, issue *Issue, value string) error {
+	if issue.Field == "" {
+		return fmt.Errorf("%s has no field to correct", issue.Kind)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("the %s cannot be empty", issue.FieldLabel())
+	}
+
+	if issue.NounID != 0 {
+		noun, err := repo.GetNoun(issue.NounID)
+		if err != nil {
+			return err
+		}
+		setStringField(noun, issue.Field, value)
+		return repo.UpdateNoun(noun)
+	}
+
+	template, err := repo.GetTemplate(issue.TemplateID)
+	if err != nil {
+		return err
+	}
+	setStringField(template, issue.Field, value)
+	switch issue.Field {
+	case "CaseType", "Number":
+		if template.Number != "singular" && template.Number != "plural" && template.Number != "both" {
+			return fmt.Errorf("invalid number '%s', must be singular, plural or both", template.Number)
+		}
+		if _, _, err := storage.TemplateFields(template.CaseType, templateNumber(template)); err != nil {
+			return fmt.Errorf("invalid case '%s', must be nominative, genitive or accusative", template.CaseType)
+		}
+		setTemplateFields(template)
+	case "GreekTemplate":
+		if problem := greekPlaceholderProblem(value); problem != "" {
+			return fmt.Errorf("%s", problem)
+		}
+	case "EnglishTemplate":
+		if !strings.Contains(value, "{noun}") {
+			return fmt.Errorf("the English template needs a {noun} placeholder")
+		}
+	}
+	return repo.UpdateTemplate(template)
+}
+
+// stringField reads a string field of a struct pointer by name
+func stringField(v any, name string) string {
+	field := reflect.ValueOf(v).Elem().FieldByName(name)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return ""
+	}
+	return field.String()
+}
+
+// setStringField sets a string field of a struct pointer by name
+func setStringField(v any, name, value string) {
+	field := reflect.ValueOf(v).Elem().FieldByName(name)
+	if field.IsValid() && field.Kind() == reflect.String {
+		field.SetString(value)
+	}
+}
+
+// containsTag reports whether tags includes tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// tonos lists the Greek vowels carrying an acute accent, and the combining accent
+const tonos = "άέήίόύώΐΰΆΈΉΊΌΎΏ\u0301"
+
+// overaccented returns the first word of text with more than one accent mark, or
+// the empty string. Compound nouns such as σταθμός λεωφορείων have one per word.
+func overaccented(text string) string {
+	for _, word := range strings.Fields(text) {
+		count := 0
+		for _, r := range word {
+			if strings.ContainsRune(tonos, r) {
+				count++
+			}
+		}
+		if count > 1 {
+			return word
+		}
+	}
+	return ""
+}
+
+// lookalikes maps Latin letters to the Greek letters they are mistaken for
+var lookalikes = map[rune]rune{
+	'A': 'Α', 'B': 'Β', 'E': 'Ε', 'H': 'Η', 'I': 'Ι', 'K': 'Κ', 'M': 'Μ',
+	'N': 'Ν', 'O': 'Ο', 'P': 'Ρ', 'T': 'Τ', 'X': 'Χ', 'Y': 'Υ', 'Z': 'Ζ',
+	'a': 'α', 'i': 'ι', 'k': 'κ', 'o': 'ο', 'p': 'ρ', 'u': 'υ', 'v': 'ν', 'x': 'χ',
+	'á': 'ά', 'é': 'έ', 'í': 'ί', 'ó': 'ό', 'ú': 'ύ',
+}
+
+// latinLetters returns the Latin letters in Greek text, ignoring placeholders
+func latinLetters(text string) []rune {
+	var latin []rune
+	for _, r := range placeholderPattern.ReplaceAllString(text, "") {
+		if unicode.In(r, unicode.Latin) && !strings.ContainsRune(string(latin), r) {
+			latin = append(latin, r)
+		}
+	}
+	return latin
+}
+
+// replaceLookalikes swaps Latin letters outside placeholders for the Greek letters
+// they resemble. It reports false if some Latin letter has no Greek lookalike.
+func replaceLookalikes(text string) (string, bool) {
+	var b strings.Builder
+	last := 0
+	ok := true
+	replace := func(s string) {
+		for _, r := range s {
+			if unicode.In(r, unicode.Latin) {
+				greek, found := lookalikes[r]
+				if !found {
+					ok = false
+				} else {
+					r = greek
+				}
+			}
+			b.WriteRune(r)
+		}
+	}
+	for _, loc := range placeholderPattern.FindAllStringIndex(text, -1) {
+		replace(text[last:loc[0]])
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	replace(text[last:])
+	return b.String(), ok
+}
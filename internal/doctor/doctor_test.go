@@ -0,0 +1,280 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// teacher returns a correct masculine noun
+func teacher() *models.Noun {
+	return &models.Noun{
+		ID: 1, English: "teacher", Gender: "masculine",
+		NominativeSg: "δάσκαλος", GenitiveSg: "δασκάλου", AccusativeSg: "δάσκαλο",
+		NominativePl: "δάσκαλοι", GenitivePl: "δασκάλων", AccusativePl: "δασκάλους",
+		NomSgArticle: "ο", GenSgArticle: "του", AccSgArticle: "τον",
+		NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τους",
+	}
+}
+
+// smellTemplate returns a correct genitive template
+func smellTemplate() *models.SentenceTemplate {
+	return &models.SentenceTemplate{
+		ID:              1,
+		EnglishTemplate: "The smell of {noun} is nice",
+		GreekTemplate:   "Η μυρωδιά {article} {noun_form} είναι ωραία",
+		ArticleField:    "GenSgArticle",
+		NounFormField:   "GenitiveSg",
+		CaseType:        "genitive",
+		Number:          "singular",
+		DifficultyPhase: 2,
+		ContextType:     "possession",
+	}
+}
+
+// kinds lists the kinds of the issues found
+func kinds(issues []*Issue) string {
+	var names []string
+	for _, issue := range issues {
+		names = append(names, issue.Kind)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestCheckNoun(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*models.Noun)
+		tags   []string
+		want   string
+		fix    bool
+	}{
+		{"correct", func(n *models.Noun) {}, nil, "", false},
+		{"feminine τη", func(n *models.Noun) {
+			*n = models.Noun{Gender: "feminine", NominativeSg: "γυναίκα", GenitiveSg: "γυναίκας", AccusativeSg: "γυναίκα",
+				NominativePl: "γυναίκες", GenitivePl: "γυναικών", AccusativePl: "γυναίκες",
+				NomSgArticle: "η", GenSgArticle: "της", AccSgArticle: "τη", NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τις"}
+		}, nil, "", false},
+		{"feminine τη before π", func(n *models.Noun) {
+			*n = models.Noun{Gender: "feminine", NominativeSg: "πόρτα", GenitiveSg: "πόρτας", AccusativeSg: "πόρτα",
+				NominativePl: "πόρτες", GenitivePl: "πορτών", AccusativePl: "πόρτες",
+				NomSgArticle: "η", GenSgArticle: "της", AccSgArticle: "τη", NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τις"}
+		}, nil, KindArticleGender, true},
+		{"wrong article", func(n *models.Noun) { n.GenSgArticle = "της" }, nil, KindArticleGender, true},
+		{"two accents", func(n *models.Noun) { n.GenitivePl = "δάσκάλων" }, nil, KindMultipleTonos, false},
+		{"compound noun", func(n *models.Noun) { n.NominativeSg = "σταθμός λεωφορείων" }, nil, "", false},
+		{"latin lookalike", func(n *models.Noun) { n.AccusativeSg = "δάσκαλo" }, nil, KindLatinLetters, true},
+		{"latin without lookalike", func(n *models.Noun) { n.AccusativeSg = "δάσκαλq" }, nil, KindLatinLetters, false},
+		{"empty plural", func(n *models.Noun) { n.GenitivePl = "" }, nil, KindEmptyPlural, false},
+		{"mass noun without plural", func(n *models.Noun) {
+			n.NominativePl, n.GenitivePl, n.AccusativePl = "", "", ""
+			n.NomPlArticle, n.GenPlArticle, n.AccPlArticle = "", "", ""
+		}, []string{"mass"}, "", false},
+		{"common gender", func(n *models.Noun) {
+			n.Gender, n.NomSgArticle, n.GenSgArticle, n.AccSgArticle = "invariable", "ο/η", "του/της", "τον/την"
+		}, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noun := teacher()
+			tt.modify(noun)
+
+			issues := CheckNoun(noun, tt.tags)
+			if got := kinds(issues); got != tt.want {
+				t.Fatalf("CheckNoun() kinds = %q, want %q", got, tt.want)
+			}
+			if len(issues) == 1 && issues[0].Fixable() != tt.fix {
+				t.Errorf("Fixable() = %v, want %v", issues[0].Fixable(), tt.fix)
+			}
+		})
+	}
+}
+
+func TestCheckArticlesFix(t *testing.T) {
+	// Neuter articles and forms stored as masculine: the gender is wrong
+	forest := &models.Noun{
+		English: "forest", Gender: "masculine",
+		NominativeSg: "δάσος", GenitiveSg: "δάσους", AccusativeSg: "δάσος",
+		NominativePl: "δάση", GenitivePl: "δασών", AccusativePl: "δάση",
+		NomSgArticle: "το", GenSgArticle: "του", AccSgArticle: "το",
+		NomPlArticle: "τα", GenPlArticle: "των", AccPlArticle: "τα",
+	}
+	issues := CheckNoun(forest, nil)
+	if len(issues) != 1 || !strings.Contains(issues[0].Fix, "gender to neuter") {
+		t.Fatalf("issues = %+v, want a gender fix", issues)
+	}
+	issues[0].fixNoun(forest)
+	if forest.Gender != "neuter" {
+		t.Errorf("Gender = %s, want neuter", forest.Gender)
+	}
+
+	// A single stray article is corrected to match the gender
+	noun := teacher()
+	noun.GenSgArticle = "της"
+	issues = CheckNoun(noun, nil)
+	issues[0].fixNoun(noun)
+	if noun.GenSgArticle != "του" || noun.Gender != "masculine" {
+		t.Errorf("fixed noun = %+v, want the genitive article corrected", noun)
+	}
+}
+
+func TestCheckTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*models.SentenceTemplate)
+		want   string
+		fix    bool
+		field  string
+	}{
+		{"correct", func(tp *models.SentenceTemplate) {}, "", false, ""},
+		{"both numbers", func(tp *models.SentenceTemplate) {
+			tp.Number, tp.ArticleField, tp.NounFormField = "both", "GenPlArticle", "GenitivePl"
+		}, "", false, ""},
+		{"agreement slots", func(tp *models.SentenceTemplate) {
+			tp.GreekTemplate = "{adj:Ο|Η|Το} σκύλος {article} {noun_form} {verb:τρέχει|τρέχουν}"
+		}, "", false, ""},
+		{"form from another case", func(tp *models.SentenceTemplate) { tp.NounFormField = "AccusativeSg" }, KindFieldMismatch, true, ""},
+		{"unknown field", func(tp *models.SentenceTemplate) { tp.NounFormField = "GenitiveSingular" }, KindUnknownField, true, ""},
+		{"article in form field", func(tp *models.SentenceTemplate) { tp.NounFormField = "GenSgArticle" }, KindUnknownField, true, ""},
+		{"fields disagree with case", func(tp *models.SentenceTemplate) {
+			tp.ArticleField, tp.NounFormField = "AccSgArticle", "AccusativeSg"
+		}, KindFieldMismatch, false, "CaseType"},
+		{"fields disagree with number", func(tp *models.SentenceTemplate) {
+			tp.ArticleField, tp.NounFormField = "GenPlArticle", "GenitivePl"
+		}, KindFieldMismatch, false, "Number"},
+		{"no noun placeholder", func(tp *models.SentenceTemplate) { tp.EnglishTemplate = "The smell of the bread" }, KindMissingPlaceholder, false, "EnglishTemplate"},
+		{"no article placeholder", func(tp *models.SentenceTemplate) { tp.GreekTemplate = "Η μυρωδιά του ψωμιού" }, KindMissingPlaceholder, false, "GreekTemplate"},
+		{"latin letters", func(tp *models.SentenceTemplate) {
+			tp.GreekTemplate = "H μυρωδιά {article} {noun_form} είναι ωραία"
+		}, KindLatinLetters, true, "GreekTemplate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := smellTemplate()
+			tt.modify(template)
+
+			issues := CheckTemplate(template)
+			if got := kinds(issues); got != tt.want {
+				t.Fatalf("CheckTemplate() kinds = %q, want %q", got, tt.want)
+			}
+			if len(issues) == 1 {
+				if issues[0].Fixable() != tt.fix {
+					t.Errorf("Fixable() = %v, want %v", issues[0].Fixable(), tt.fix)
+				}
+				if issues[0].Field != tt.field {
+					t.Errorf("Field = %q, want %q", issues[0].Field, tt.field)
+				}
+			}
+		})
+	}
+}
+
+func TestReplaceLookalikes(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"Βλέπω {article} {noun_form}", "Βλέπω {article} {noun_form}", true},
+		{"Bλέπω {article} {noun_form} {verb:τρέχει|τρέχoυν}", "Βλέπω {article} {noun_form} {verb:τρέχει|τρέχουν}", true},
+		{"δάσκαλó", "δάσκαλό", true},
+		{"δάσκαλq", "δάσκαλq", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := replaceLookalikes(tt.text)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("replaceLookalikes(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestScanApplyAndCorrect(t *testing.T) {
+	repo, err := storage.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer repo.Close()
+
+	noun := teacher()
+	noun.AccusativeSg = "δάσκαλo"
+	noun.GenitivePl = "δάσκάλων"
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	// The template from the "του ενήλικο" bug: genitive article with an accusative form
+	template := smellTemplate()
+	template.NounFormField = "AccusativeSg"
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	issues, err := Scan(repo)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if got := kinds(issues); got != "latin-letters,multiple-tonos,field-mismatch" {
+		t.Fatalf("Scan() kinds = %q", got)
+	}
+
+	for _, issue := range issues {
+		if issue.Fixable() {
+			if err := Apply(repo, issue); err != nil {
+				t.Fatalf("Apply(%s) error = %v", issue.Kind, err)
+			}
+		} else if err := Correct(repo, issue, "δασκάλων"); err != nil {
+			t.Fatalf("Correct(%s) error = %v", issue.Kind, err)
+		}
+	}
+
+	if issues, _ := Scan(repo); len(issues) != 0 {
+		t.Errorf("Scan() after fixing found %s", kinds(issues))
+	}
+	fixed, _ := repo.GetTemplate(template.ID)
+	if fixed.NounFormField != "GenitiveSg" {
+		t.Errorf("NounFormField = %s, want GenitiveSg", fixed.NounFormField)
+	}
+}
+
+func TestCorrectTemplateCase(t *testing.T) {
+	repo, err := storage.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer repo.Close()
+
+	template := smellTemplate()
+	template.GreekTemplate = "Μιλάει για {article} {noun_form}"
+	template.ArticleField, template.NounFormField = "AccSgArticle", "AccusativeSg"
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	issue := CheckTemplate(template)[0]
+	if err := Correct(repo, issue, "dative"); err == nil {
+		t.Error("Correct() should reject unknown cases")
+	}
+	if err := Correct(repo, issue, "accusative"); err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+
+	fixed, _ := repo.GetTemplate(template.ID)
+	if fixed.CaseType != "accusative" || len(CheckTemplate(fixed)) != 0 {
+		t.Errorf("corrected template = %+v, want a consistent accusative template", fixed)
+	}
+
+	// Correcting the other way moves the fields to the case
+	fixed.ArticleField, fixed.NounFormField = "GenSgArticle", "GenitiveSg"
+	repo.UpdateTemplate(fixed)
+	if err := Correct(repo, CheckTemplate(fixed)[0], "accusative"); err != nil {
+		t.Fatalf("Correct() error = %v", err)
+	}
+	if fixed, _ = repo.GetTemplate(template.ID); fixed.ArticleField != "AccSgArticle" {
+		t.Errorf("ArticleField = %s, want AccSgArticle", fixed.ArticleField)
+	}
+}
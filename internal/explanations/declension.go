@@ -0,0 +1,127 @@
+package explanations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// stressNames names accent positions counted from the end of a word
+var stressNames = map[int]string{
+	1: "final",
+	2: "penultimate",
+	3: "antepenultimate",
+}
+
+// paradigmCases lists the paradigm rows in display order
+var paradigmCases = []string{"nominative", "genitive", "accusative"}
+
+// FormatPattern explains the ending change from the nominative singular to the
+// target form: the declension class, the changed suffix and any stress movement
+func FormatPattern(noun *models.Noun, caseType string, number string) string {
+	_, target, ok := FormFor(noun, caseType, number)
+	if !ok {
+		return ""
+	}
+	nominative := noun.NominativeSg
+
+	if grammar.IsInvariable(noun) {
+		return "Invariable noun: the form never changes; only the article shows case and number"
+	}
+
+	var lines []string
+
+	class, ok := grammar.Default().Classify(noun)
+	var nomStem, nomSuffix, targetStem, targetSuffix string
+	if ok {
+		nomStem, nomSuffix = grammar.SplitEnding(nominative, class.Endings.NomSg)
+		targetStem, targetSuffix = grammar.SplitEnding(target, class.Ending(caseType, number))
+		lines = append(lines, fmt.Sprintf("%s (like %s): -%s → -%s",
+			class.Title, class.Example, class.Endings.NomSg, class.Ending(caseType, number)))
+	} else {
+		nomStem, nomSuffix, targetStem, targetSuffix = splitCommonStem(nominative, target)
+		lines = append(lines, fmt.Sprintf("Ending: -%s → -%s", grammar.StripAccents(nomSuffix), grammar.StripAccents(targetSuffix)))
+	}
+
+	if target == nominative {
+		lines = append(lines, fmt.Sprintf("%s: same form as the nominative singular", target))
+	} else {
+		lines = append(lines, fmt.Sprintf("%s-%s → %s-%s", nomStem, nomSuffix, targetStem, targetSuffix))
+	}
+
+	if note := stressNote(nominative, target); note != "" {
+		lines = append(lines, note)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// splitCommonStem splits two forms after their longest accent-insensitive common prefix
+func splitCommonStem(a, b string) (aStem, aSuffix, bStem, bSuffix string) {
+	ar, br := []rune(a), []rune(b)
+	plainA, plainB := []rune(grammar.StripAccents(a)), []rune(grammar.StripAccents(b))
+
+	n := 0
+	for n < len(plainA) && n < len(plainB) && plainA[n] == plainB[n] {
+		n++
+	}
+
+	return string(ar[:n]), string(ar[n:]), string(br[:n]), string(br[n:])
+}
+
+// stressNote describes the accent moving to a different syllable, if it does
+func stressNote(from, to string) string {
+	fromPos, toPos := grammar.StressPosition(from), grammar.StressPosition(to)
+	if fromPos == 0 || toPos == 0 {
+		return ""
+	}
+
+	// Compare syllables counted from the start, since the ending may add syllables
+	fromIndex := grammar.SyllableCount(from) - fromPos
+	toIndex := grammar.SyllableCount(to) - toPos
+	if fromIndex == toIndex {
+		return ""
+	}
+
+	fromName, toName := stressNames[fromPos], stressNames[toPos]
+	if fromName == "" || toName == "" {
+		return fmt.Sprintf("Stress moves (%s → %s)", from, to)
+	}
+	return fmt.Sprintf("Stress moves from the %s to the %s syllable (%s → %s)", fromName, toName, from, to)
+}
+
+// FormatParadigm renders the six article and noun forms as a table,
+// with the target form in brackets
+func FormatParadigm(noun *models.Noun, caseType string, number string) string {
+	cells := make(map[string]string)
+	width := len("Singular")
+	for _, c := range paradigmCases {
+		for _, n := range []string{"singular", "plural"} {
+			article, form, _ := FormFor(noun, c, n)
+			cell := fmt.Sprintf("%s %s", article, form)
+			if c == caseType && n == number {
+				cell = "[" + cell + "]"
+			}
+			cells[c+"/"+n] = cell
+			width = max(width, len([]rune(cell)))
+		}
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "%-12s%-*s  %s", "", width, "Singular", "Plural")
+	for _, c := range paradigmCases {
+		fmt.Fprintf(&s, "\n%-12s%-*s  %s", CaseName(c), width, cells[c+"/singular"], cells[c+"/plural"])
+	}
+
+	return s.String()
+}
+
+// CaseName capitalizes a case type for display
+func CaseName(caseType string) string {
+	if caseType == "" {
+		return ""
+	}
+	return strings.ToUpper(caseType[:1]) + caseType[1:]
+}
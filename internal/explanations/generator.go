@@ -21,12 +21,30 @@ func Generate(sentence *models.Sentence, noun *models.Noun) (*models.Explanation
 	// Generate syntactic role explanation
 	syntacticRole := SyntacticRoleTemplate(sentence.ContextType, sentence.CaseType, sentence.Preposition)
 
+	// Show the preposition-article fusion when the answer is contracted
+	if sentence.Contracted && sentence.Preposition != nil {
+		article, _, _ := FormFor(noun, sentence.CaseType, sentence.Number)
+		if note := ContractionNote(*sentence.Preposition, article); note != "" {
+			syntacticRole = fmt.Sprintf("%s (%s)", syntacticRole, note)
+		}
+	}
+
 	// Generate morphology transformation
 	morphology := FormatMorphology(noun, sentence.CaseType, sentence.Number)
 
+	// Explain the ending change within the noun's declension class
+	pattern := FormatPattern(noun, sentence.CaseType, sentence.Number)
+	paradigm := FormatParadigm(noun, sentence.CaseType, sentence.Number)
+
+	// Look up the governing preposition, verb or construction in the grammar catalog
+	usage := Usage(sentence.ContextType, sentence.CaseType, sentence.Preposition, sentence.GreekSentence)
+
 	return &models.Explanation{
 		Translation:   translation,
 		SyntacticRole: syntacticRole,
 		Morphology:    morphology,
+		Usage:         usage,
+		Pattern:       pattern,
+		Paradigm:      paradigm,
 	}, nil
 }
@@ -140,6 +140,36 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateContraction(t *testing.T) {
+	noun := &models.Noun{
+		NominativeSg: "δάσκαλος",
+		AccusativeSg: "δάσκαλο",
+		NomSgArticle: "ο",
+		AccSgArticle: "τον",
+	}
+
+	sentence := &models.Sentence{
+		EnglishPrompt: "I go to ___ (the teacher)",
+		GreekSentence: "Πηγαίνω στον δάσκαλο",
+		CorrectAnswer: "στον δάσκαλο",
+		CaseType:      "accusative",
+		Number:        "singular",
+		ContextType:   "preposition",
+		Preposition:   stringPtr("σε"),
+		Contracted:    true,
+	}
+
+	explanation, err := Generate(sentence, noun)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := "The preposition 'σε' requires accusative case (σε + τον → στον)"
+	if explanation.SyntacticRole != want {
+		t.Errorf("SyntacticRole = %q, want %q", explanation.SyntacticRole, want)
+	}
+}
+
 func TestSyntacticRoleTemplate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -202,6 +232,80 @@ func TestSyntacticRoleTemplate(t *testing.T) {
 	}
 }
 
+func TestUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		contextType string
+		caseType    string
+		prep        *string
+		greek       string
+		want        string
+	}{
+		{
+			name:        "preposition",
+			contextType: "preposition",
+			caseType:    "accusative",
+			prep:        stringPtr("για"),
+			greek:       "Μιλάμε για τον δάσκαλο",
+			want:        "για (for, about) + accusative\ne.g. Μιλάμε για τον καιρό. (We are talking about the weather.)",
+		},
+		{
+			name:        "verb",
+			contextType: "direct_object",
+			caseType:    "accusative",
+			greek:       "Βλέπω τον δάσκαλο",
+			want:        "βλέπω (to see) + accusative\ne.g. Βλέπω τη θάλασσα. (I see the sea.)",
+		},
+		{
+			name:        "possession",
+			contextType: "possession",
+			caseType:    "genitive",
+			greek:       "Το βιβλίο του δασκάλου",
+			want:        "Possessive genitive: The owner follows the thing owned and takes the genitive.\ne.g. Το βιβλίο του δασκάλου είναι εδώ. (The teacher's book is here.)",
+		},
+		{
+			name:        "indirect object",
+			contextType: "indirect_object",
+			caseType:    "genitive",
+			greek:       "Δίνω του δασκάλου το βιβλίο",
+			want: "δίνω (to give) + genitive of the recipient\ne.g. Δίνω της γιαγιάς το δώρο. (I give grandma the present.)\n" +
+				"Indirect object in the genitive: The recipient of verbs like δίνω, λέω, στέλνω can be a genitive, mostly a clitic pronoun; with full nouns σε + accusative is more common.\n" +
+				"e.g. Του έδωσα το βιβλίο. (I gave him the book.)",
+		},
+		{
+			name:        "time",
+			contextType: "time",
+			caseType:    "accusative",
+			greek:       "Την Κυριακή πάμε εκδρομή",
+			want:        "Accusative of time: Points and stretches of time take the accusative without a preposition.\ne.g. Την Κυριακή πάμε εκδρομή. (On Sunday we are going on a trip.)",
+		},
+		{
+			name:        "unknown verb",
+			contextType: "direct_object",
+			caseType:    "accusative",
+			greek:       "Κοιτάζω τον δάσκαλο",
+			want:        "",
+		},
+		{
+			name:        "unknown preposition",
+			contextType: "preposition",
+			caseType:    "accusative",
+			prep:        stringPtr("foo"),
+			greek:       "foo τον δάσκαλο",
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Usage(tt.contextType, tt.caseType, tt.prep, tt.greek)
+			if got != tt.want {
+				t.Errorf("Usage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenerateTranslation(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -350,3 +454,121 @@ func TestFormatMorphology(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestFormatPattern(t *testing.T) {
+	teacher := &models.Noun{
+		Gender:       "masculine",
+		NominativeSg: "δάσκαλος",
+		GenitiveSg:   "δασκάλου",
+		AccusativeSg: "δάσκαλο",
+		NominativePl: "δάσκαλοι",
+		GenitivePl:   "δασκάλων",
+		AccusativePl: "δασκάλους",
+	}
+	taxi := &models.Noun{
+		Gender:       "neuter",
+		NominativeSg: "ταξί",
+		GenitiveSg:   "ταξί",
+		AccusativeSg: "ταξί",
+		NominativePl: "ταξί",
+		GenitivePl:   "ταξί",
+		AccusativePl: "ταξί",
+	}
+
+	tests := []struct {
+		name     string
+		noun     *models.Noun
+		caseType string
+		number   string
+		want     string
+	}{
+		{
+			name:     "stress moves in the genitive",
+			noun:     teacher,
+			caseType: "genitive",
+			number:   "singular",
+			want: "Masculine in -ος (like ο δάσκαλος): -ος → -ου\n" +
+				"δάσκαλ-ος → δασκάλ-ου\n" +
+				"Stress moves from the antepenultimate to the penultimate syllable (δάσκαλος → δασκάλου)",
+		},
+		{
+			name:     "stress stays in the accusative",
+			noun:     teacher,
+			caseType: "accusative",
+			number:   "singular",
+			want: "Masculine in -ος (like ο δάσκαλος): -ος → -ο\n" +
+				"δάσκαλ-ος → δάσκαλ-ο",
+		},
+		{
+			name:     "invariable noun",
+			noun:     taxi,
+			caseType: "genitive",
+			number:   "plural",
+			want:     "Invariable noun: the form never changes; only the article shows case and number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPattern(tt.noun, tt.caseType, tt.number); got != tt.want {
+				t.Errorf("FormatPattern() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatParadigm(t *testing.T) {
+	noun := &models.Noun{
+		NominativeSg: "γάτα",
+		GenitiveSg:   "γάτας",
+		AccusativeSg: "γάτα",
+		NominativePl: "γάτες",
+		GenitivePl:   "γατών",
+		AccusativePl: "γάτες",
+		NomSgArticle: "η",
+		GenSgArticle: "της",
+		AccSgArticle: "τη",
+		NomPlArticle: "οι",
+		GenPlArticle: "των",
+		AccPlArticle: "τις",
+	}
+
+	want := "            Singular     Plural\n" +
+		"Nominative  η γάτα       οι γάτες\n" +
+		"Genitive    [της γάτας]  των γατών\n" +
+		"Accusative  τη γάτα      τις γάτες"
+
+	if got := FormatParadigm(noun, "genitive", "singular"); got != want {
+		t.Errorf("FormatParadigm() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHints(t *testing.T) {
+	noun := &models.Noun{
+		Gender:       "masculine",
+		NominativeSg: "δάσκαλος",
+		NomSgArticle: "ο",
+	}
+	sentence := &models.Sentence{
+		CorrectAnswer: "του δασκάλου",
+		CaseType:      "genitive",
+		Number:        "singular",
+	}
+
+	want := []string{
+		"Gender: masculine",
+		"Case: genitive singular",
+		"Article: του",
+		"Starts with: του δασ…",
+	}
+
+	got := Hints(sentence, noun)
+	if len(got) != len(want) {
+		t.Fatalf("Hints() returned %d hints, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Hints()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package explanations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// hintPrefixLetters is how many letters of the noun form the last hint reveals
+const hintPrefixLetters = 3
+
+// Hints returns progressively more revealing hints for a sentence: the noun's gender,
+// the target case and number, the article, then the article with the first letters of the form
+func Hints(sentence *models.Sentence, noun *models.Noun) []string {
+	gender := grammar.GrammaticalGender(noun)
+	if noun.Gender == "invariable" {
+		gender += " (invariable: only the article changes)"
+	}
+
+	article, form := sentence.CorrectAnswer, ""
+	if i := strings.LastIndex(sentence.CorrectAnswer, " "); i >= 0 {
+		article, form = sentence.CorrectAnswer[:i], sentence.CorrectAnswer[i+1:]
+	}
+
+	// Keep at least one letter hidden
+	letters := []rune(form)
+	reveal := min(hintPrefixLetters, len(letters)-1)
+	prefix := string(letters[:max(reveal, 0)])
+
+	return []string{
+		fmt.Sprintf("Gender: %s", gender),
+		fmt.Sprintf("Case: %s %s", sentence.CaseType, sentence.Number),
+		fmt.Sprintf("Article: %s", article),
+		fmt.Sprintf("Starts with: %s %s…", article, prefix),
+	}
+}
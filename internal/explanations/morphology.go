@@ -6,44 +6,42 @@ import (
 	"github.com/gataky/greekmaster/internal/models"
 )
 
-// FormatMorphology shows the declension transformation
-func FormatMorphology(noun *models.Noun, caseType string, number string) string {
-	// Get nominative form (starting point)
-	nomArticle := noun.NomSgArticle
-	nomNoun := noun.NominativeSg
-
-	// Get target form based on case and number
-	var targetArticle, targetNoun string
-
+// FormFor returns the article and noun form for a case and number.
+// ok is false for an unknown case.
+func FormFor(noun *models.Noun, caseType string, number string) (article, form string, ok bool) {
 	switch caseType {
 	case "nominative":
 		if number == "singular" {
-			targetArticle = noun.NomSgArticle
-			targetNoun = noun.NominativeSg
-		} else {
-			targetArticle = noun.NomPlArticle
-			targetNoun = noun.NominativePl
+			return noun.NomSgArticle, noun.NominativeSg, true
 		}
+		return noun.NomPlArticle, noun.NominativePl, true
 
 	case "genitive":
 		if number == "singular" {
-			targetArticle = noun.GenSgArticle
-			targetNoun = noun.GenitiveSg
-		} else {
-			targetArticle = noun.GenPlArticle
-			targetNoun = noun.GenitivePl
+			return noun.GenSgArticle, noun.GenitiveSg, true
 		}
+		return noun.GenPlArticle, noun.GenitivePl, true
 
 	case "accusative":
 		if number == "singular" {
-			targetArticle = noun.AccSgArticle
-			targetNoun = noun.AccusativeSg
-		} else {
-			targetArticle = noun.AccPlArticle
-			targetNoun = noun.AccusativePl
+			return noun.AccSgArticle, noun.AccusativeSg, true
 		}
+		return noun.AccPlArticle, noun.AccusativePl, true
 
 	default:
+		return "", "", false
+	}
+}
+
+// FormatMorphology shows the declension transformation
+func FormatMorphology(noun *models.Noun, caseType string, number string) string {
+	// Get nominative form (starting point)
+	nomArticle := noun.NomSgArticle
+	nomNoun := noun.NominativeSg
+
+	// Get target form based on case and number
+	targetArticle, targetNoun, ok := FormFor(noun, caseType, number)
+	if !ok {
 		return fmt.Sprintf("%s %s", nomArticle, nomNoun)
 	}
 
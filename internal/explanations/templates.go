@@ -1,17 +1,11 @@
 package explanations
 
-import "fmt"
-
-// prepositionCaseMap defines which case each preposition requires
-var prepositionCaseMap = map[string]string{
-	"σε":     "accusative", // to, at, in
-	"από":    "genitive",   // from
-	"για":    "genitive",   // for
-	"με":     "accusative", // with
-	"χωρίς":  "accusative", // without
-	"μετά":   "accusative", // after
-	"πριν":   "accusative", // before
-}
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+)
 
 // SyntacticRoleTemplate returns the rule explanation for a given context
 func SyntacticRoleTemplate(contextType string, caseType string, prep *string) string {
@@ -22,6 +16,12 @@ func SyntacticRoleTemplate(contextType string, caseType string, prep *string) st
 	case "possession":
 		return "Possession requires genitive case"
 
+	case "indirect_object":
+		return fmt.Sprintf("The recipient of the action is an indirect object in %s case", caseType)
+
+	case "time":
+		return fmt.Sprintf("This time expression uses %s case without a preposition", caseType)
+
 	case "preposition":
 		if prep != nil && *prep != "" {
 			return fmt.Sprintf("The preposition '%s' requires %s case", *prep, caseType)
@@ -32,3 +32,67 @@ func SyntacticRoleTemplate(contextType string, caseType string, prep *string) st
 		return fmt.Sprintf("This context uses %s case", caseType)
 	}
 }
+
+// ContractionNote shows how a preposition fuses with the article (σε + τον → στον).
+// It returns the empty string when the pair does not contract.
+func ContractionNote(prep string, article string) string {
+	contracted, ok := grammar.Contract(prep, article)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s + %s → %s", prep, article, contracted)
+}
+
+// Usage describes the catalog entry behind a sentence's case: the governing preposition
+// or verb, or the construction for the context, with its gloss and an example. It returns the empty
+// string when the catalog has no matching entry.
+func Usage(contextType string, caseType string, prep *string, greekSentence string) string {
+	catalog := grammar.Default()
+
+	switch contextType {
+	case "preposition":
+		if prep == nil {
+			return ""
+		}
+		entry, ok := catalog.Preposition(*prep)
+		if !ok {
+			return ""
+		}
+		usage := fmt.Sprintf("%s (%s) + %s", entry.Greek, entry.Gloss, strings.Join(entry.Cases, " or "))
+		if entry.Note != "" {
+			usage += ". " + entry.Note
+		}
+		return fmt.Sprintf("%s\ne.g. %s (%s)", usage, entry.Example, entry.Translation)
+
+	case "direct_object":
+		verb, ok := catalog.FindVerb(greekSentence)
+		if !ok || verb.Case != caseType {
+			return ""
+		}
+		return fmt.Sprintf("%s (%s) + %s\ne.g. %s (%s)", verb.Lemma, verb.Gloss, verb.Case, verb.Example, verb.Translation)
+
+	case "indirect_object":
+		construction, ok := catalog.ConstructionFor(contextType, caseType)
+		if !ok {
+			return ""
+		}
+		usage := constructionUsage(construction)
+		if verb, ok := catalog.FindVerb(greekSentence); ok && verb.Indirect == caseType {
+			usage = fmt.Sprintf("%s (%s) + %s of the recipient\ne.g. %s (%s)\n%s",
+				verb.Lemma, verb.Gloss, verb.Indirect, verb.Example, verb.Translation, usage)
+		}
+		return usage
+
+	default:
+		construction, ok := catalog.ConstructionFor(contextType, caseType)
+		if !ok {
+			return ""
+		}
+		return constructionUsage(construction)
+	}
+}
+
+// constructionUsage describes a construction with its example
+func constructionUsage(construction *grammar.Construction) string {
+	return fmt.Sprintf("%s: %s\ne.g. %s (%s)", construction.Title, construction.Description, construction.Example, construction.Translation)
+}
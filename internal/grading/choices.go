@@ -0,0 +1,85 @@
+package grading
+
+import (
+	"math/rand"
+
+	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// ChoiceCount is the number of options offered in multiple-choice mode
+const ChoiceCount = 4
+
+// choiceCells lists every case and number a distractor can be drawn from
+var choiceCells = [][2]string{
+	{"nominative", "singular"},
+	{"genitive", "singular"},
+	{"accusative", "singular"},
+	{"nominative", "plural"},
+	{"genitive", "plural"},
+	{"accusative", "plural"},
+}
+
+// Choices returns the correct answer and up to ChoiceCount-1 distractors in random order.
+// Distractors are the noun's other case and number forms, and the correct noun form
+// with another article or the correct article with another noun form.
+// Contracted answers get contracted distractors so the preposition never gives it away.
+func Choices(sentence *models.Sentence, noun *models.Noun, rng *rand.Rand) []string {
+	article, form, ok := explanations.FormFor(noun, sentence.CaseType, sentence.Number)
+	if !ok {
+		return []string{sentence.CorrectAnswer}
+	}
+
+	// Match the contraction of the correct answer, dropping articles that don't fuse (ο, οι)
+	var otherForms, swapped []string
+	add := func(pool *[]string, a, f string) {
+		if sentence.Contracted && sentence.Preposition != nil {
+			contracted, ok := grammar.Contract(*sentence.Preposition, a)
+			if !ok {
+				return
+			}
+			a = contracted
+		}
+		*pool = append(*pool, a+" "+f)
+	}
+
+	for _, cell := range choiceCells {
+		a, f, _ := explanations.FormFor(noun, cell[0], cell[1])
+		add(&otherForms, a, f)
+		if a != article {
+			add(&swapped, a, form)
+		}
+		if f != form {
+			add(&swapped, article, f)
+		}
+	}
+	rng.Shuffle(len(otherForms), func(i, j int) { otherForms[i], otherForms[j] = otherForms[j], otherForms[i] })
+	rng.Shuffle(len(swapped), func(i, j int) { swapped[i], swapped[j] = swapped[j], swapped[i] })
+
+	choices := []string{sentence.CorrectAnswer}
+	seen := map[string]bool{sentence.CorrectAnswer: true}
+	pick := func(candidate string) bool {
+		if len(choices) == ChoiceCount || seen[candidate] || GradeAnswer(candidate, sentence.CorrectAnswer) {
+			return false
+		}
+		seen[candidate] = true
+		choices = append(choices, candidate)
+		return true
+	}
+
+	// Take at least one of each kind before filling up from both pools
+	for _, pool := range [][]string{otherForms, swapped} {
+		for _, candidate := range pool {
+			if pick(candidate) {
+				break
+			}
+		}
+	}
+	for _, candidate := range append(otherForms, swapped...) {
+		pick(candidate)
+	}
+
+	rng.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+	return choices
+}
@@ -0,0 +1,96 @@
+package grading
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestChoices(t *testing.T) {
+	teacher := &models.Noun{
+		Gender:       "masculine",
+		NominativeSg: "δάσκαλος",
+		GenitiveSg:   "δασκάλου",
+		AccusativeSg: "δάσκαλο",
+		NominativePl: "δάσκαλοι",
+		GenitivePl:   "δασκάλων",
+		AccusativePl: "δασκάλους",
+		NomSgArticle: "ο",
+		GenSgArticle: "του",
+		AccSgArticle: "τον",
+		NomPlArticle: "οι",
+		GenPlArticle: "των",
+		AccPlArticle: "τους",
+	}
+	taxi := &models.Noun{
+		Gender:       "neuter",
+		NominativeSg: "ταξί",
+		GenitiveSg:   "ταξί",
+		AccusativeSg: "ταξί",
+		NominativePl: "ταξί",
+		GenitivePl:   "ταξί",
+		AccusativePl: "ταξί",
+		NomSgArticle: "το",
+		GenSgArticle: "του",
+		AccSgArticle: "το",
+		NomPlArticle: "τα",
+		GenPlArticle: "των",
+		AccPlArticle: "τα",
+	}
+	se := "σε"
+
+	tests := []struct {
+		name     string
+		sentence *models.Sentence
+		noun     *models.Noun
+		prefix   string
+	}{
+		{
+			name:     "regular noun",
+			sentence: &models.Sentence{CorrectAnswer: "τον δάσκαλο", CaseType: "accusative", Number: "singular"},
+			noun:     teacher,
+		},
+		{
+			name: "contracted answer",
+			sentence: &models.Sentence{CorrectAnswer: "στον δάσκαλο", CaseType: "accusative", Number: "singular",
+				Preposition: &se, Contracted: true},
+			noun:   teacher,
+			prefix: "στ",
+		},
+		{
+			name:     "invariable noun",
+			sentence: &models.Sentence{CorrectAnswer: "του ταξί", CaseType: "genitive", Number: "singular"},
+			noun:     taxi,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			choices := Choices(tt.sentence, tt.noun, rng)
+
+			if len(choices) != ChoiceCount {
+				t.Fatalf("Choices() returned %d options, want %d: %v", len(choices), ChoiceCount, choices)
+			}
+
+			correct := 0
+			for i, choice := range choices {
+				if Grade(choice, tt.sentence) {
+					correct++
+				}
+				if slices.Contains(choices[i+1:], choice) {
+					t.Errorf("Choices() has duplicate option %q", choice)
+				}
+				if !strings.HasPrefix(choice, tt.prefix) {
+					t.Errorf("Choices() option %q does not start with %q", choice, tt.prefix)
+				}
+			}
+			if correct != 1 {
+				t.Errorf("Choices() has %d correct options, want 1: %v", correct, choices)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package grading
+
+import (
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// AcceptedAnswers returns every answer graded as correct for a sentence.
+// Besides the exact correct answer, feminine articles may drop their final ν before
+// consonants that do not need it (την γυναίκα / τη γυναίκα, στην θάλασσα / στη θάλασσα).
+func AcceptedAnswers(sentence *models.Sentence) []string {
+	return acceptedForms(sentence.CorrectAnswer)
+}
+
+// acceptedForms returns an article + noun answer and its accepted variants
+func acceptedForms(answer string) []string {
+	answer = strings.TrimSpace(answer)
+	accepted := []string{answer}
+
+	article, rest, found := strings.Cut(answer, " ")
+	if !found {
+		return accepted
+	}
+	if variant := grammar.ArticleVariant(article, rest); variant != "" {
+		accepted = append(accepted, variant+" "+rest)
+	}
+
+	return accepted
+}
+
+// Grade reports whether the user's answer is correct for a sentence.
+// Comparison is an exact Unicode match after trimming and collapsing whitespace.
+func Grade(userAnswer string, sentence *models.Sentence) bool {
+	return GradeAnswer(userAnswer, sentence.CorrectAnswer)
+}
+
+// GradeAnswer reports whether the user's answer matches an expected article + noun
+// answer, with the same normalization and variants as Grade
+func GradeAnswer(userAnswer, correctAnswer string) bool {
+	normalized := strings.Join(strings.Fields(userAnswer), " ")
+	for _, accepted := range acceptedForms(correctAnswer) {
+		if normalized == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// HintPenalty is the score deducted for each hint taken before answering
+const HintPenalty = 0.25
+
+// Score returns an item's score: 1 for a correct answer, reduced by each hint taken,
+// and 0 for a wrong answer
+func Score(correct bool, hintsUsed int) float64 {
+	if !correct {
+		return 0
+	}
+	return max(0, 1-HintPenalty*float64(hintsUsed))
+}
@@ -0,0 +1,63 @@
+package grading
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		correct string
+		want    bool
+	}{
+		{"exact", "τον δάσκαλο", "τον δάσκαλο", true},
+		{"surrounding whitespace", "  τον δάσκαλο ", "τον δάσκαλο", true},
+		{"double space", "τον  δάσκαλο", "τον δάσκαλο", true},
+		{"wrong article", "το δάσκαλο", "τον δάσκαλο", false},
+		{"missing accent", "τον δασκαλο", "τον δάσκαλο", false},
+		{"contracted", "στον δάσκαλο", "στον δάσκαλο", true},
+		{"uncontracted for contracted answer", "σε τον δάσκαλο", "στον δάσκαλο", false},
+		{"article only for contracted answer", "τον δάσκαλο", "στον δάσκαλο", false},
+		{"feminine without final ν", "τη γυναίκα", "την γυναίκα", true},
+		{"feminine with final ν", "την πόρτα", "τη πόρτα", true},
+		{"feminine without final ν before a vowel", "τη αγορά", "την αγορά", false},
+		{"feminine without final ν before π", "τη πόρτα", "την πόρτα", false},
+		{"feminine without final ν before κ", "τη κουζίνα", "την κουζίνα", false},
+		{"contracted feminine without final ν", "στη γυναίκα", "στην γυναίκα", true},
+		{"contracted feminine without final ν before a vowel", "στη Αθήνα", "στην Αθήνα", false},
+		{"empty", "", "τον δάσκαλο", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sentence := &models.Sentence{CorrectAnswer: tt.correct}
+			if got := Grade(tt.answer, sentence); got != tt.want {
+				t.Errorf("Grade(%q) against %q = %v, want %v", tt.answer, tt.correct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		correct   bool
+		hintsUsed int
+		want      float64
+	}{
+		{true, 0, 1},
+		{true, 1, 0.75},
+		{true, 4, 0},
+		{true, 6, 0},
+		{false, 0, 0},
+		{false, 2, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Score(tt.correct, tt.hintsUsed); got != tt.want {
+			t.Errorf("Score(%v, %d) = %v, want %v", tt.correct, tt.hintsUsed, got, tt.want)
+		}
+	}
+}
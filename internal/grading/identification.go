@@ -0,0 +1,68 @@
+package grading
+
+import (
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// Identification is a learner's analysis of a noun phrase in a Greek sentence
+type Identification struct {
+	CaseType string
+	Number   string
+	Gender   string
+	Lemma    string // Nominative singular, optional
+}
+
+// IdentificationResult reports which parts of an identification are correct
+type IdentificationResult struct {
+	Case   bool
+	Number bool
+	Gender bool
+	Lemma  bool // Always true when no lemma was given
+}
+
+// Correct reports whether every part of the identification is correct
+func (r IdentificationResult) Correct() bool {
+	return r.Case && r.Number && r.Gender && r.Lemma
+}
+
+// GradeIdentification checks an identification against the sentence's noun phrase.
+// The lemma may be given with or without its article and is skipped when blank.
+func GradeIdentification(id Identification, sentence *models.Sentence, noun *models.Noun) IdentificationResult {
+	result := IdentificationResult{
+		Case:   id.CaseType == sentence.CaseType,
+		Number: id.Number == sentence.Number,
+		Gender: id.Gender == grammar.GrammaticalGender(noun),
+		Lemma:  true,
+	}
+
+	lemma := strings.Join(strings.Fields(id.Lemma), " ")
+	if lemma != "" {
+		lemma = strings.TrimPrefix(lemma, noun.NomSgArticle+" ")
+		result.Lemma = lemma == noun.NominativeSg
+	}
+
+	return result
+}
+
+// ExpectedIdentification returns the correct identification of a sentence's noun phrase
+func ExpectedIdentification(sentence *models.Sentence, noun *models.Noun) Identification {
+	return Identification{
+		CaseType: sentence.CaseType,
+		Number:   sentence.Number,
+		Gender:   grammar.GrammaticalGender(noun),
+		Lemma:    noun.NominativeSg,
+	}
+}
+
+// String describes an identification as it is recorded in the attempt history,
+// such as "accusative singular masculine δάσκαλος"
+func (id Identification) String() string {
+	parts := []string{id.CaseType, id.Number, id.Gender}
+	if lemma := strings.Join(strings.Fields(id.Lemma), " "); lemma != "" {
+		parts = append(parts, lemma)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,65 @@
+package grading
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestGradeIdentification(t *testing.T) {
+	teacher := &models.Noun{
+		Gender:       "masculine",
+		NominativeSg: "δάσκαλος",
+		NomSgArticle: "ο",
+	}
+	taxi := &models.Noun{
+		Gender:       "invariable",
+		NominativeSg: "ταξί",
+		NomSgArticle: "το",
+	}
+	sentence := &models.Sentence{CaseType: "genitive", Number: "plural"}
+
+	tests := []struct {
+		name string
+		id   Identification
+		noun *models.Noun
+		want IdentificationResult
+	}{
+		{
+			name: "all correct without lemma",
+			id:   Identification{CaseType: "genitive", Number: "plural", Gender: "masculine"},
+			noun: teacher,
+			want: IdentificationResult{Case: true, Number: true, Gender: true, Lemma: true},
+		},
+		{
+			name: "lemma with article",
+			id:   Identification{CaseType: "genitive", Number: "plural", Gender: "masculine", Lemma: " ο  δάσκαλος"},
+			noun: teacher,
+			want: IdentificationResult{Case: true, Number: true, Gender: true, Lemma: true},
+		},
+		{
+			name: "wrong case and lemma",
+			id:   Identification{CaseType: "accusative", Number: "plural", Gender: "masculine", Lemma: "δασκάλων"},
+			noun: teacher,
+			want: IdentificationResult{Case: false, Number: true, Gender: true, Lemma: false},
+		},
+		{
+			name: "invariable noun takes the article's gender",
+			id:   Identification{CaseType: "genitive", Number: "singular", Gender: "neuter"},
+			noun: taxi,
+			want: IdentificationResult{Case: true, Number: false, Gender: true, Lemma: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GradeIdentification(tt.id, sentence, tt.noun)
+			if got != tt.want {
+				t.Errorf("GradeIdentification() = %+v, want %+v", got, tt.want)
+			}
+			if got.Correct() != (tt.want.Case && tt.want.Number && tt.want.Gender && tt.want.Lemma) {
+				t.Errorf("Correct() = %v", got.Correct())
+			}
+		})
+	}
+}
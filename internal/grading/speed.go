@@ -0,0 +1,29 @@
+package grading
+
+import "time"
+
+// SlowThreshold is the response time above which a correct answer counts as
+// slow in a sprint without a per-question countdown
+const SlowThreshold = 8 * time.Second
+
+// SlowFraction is the share of a per-question countdown after which a correct
+// answer counts as slow
+const SlowFraction = 0.75
+
+// IsSlow reports whether a correct answer took long enough to practise again.
+// limit is the per-question countdown, or 0 when there is none.
+func IsSlow(latency, limit time.Duration) bool {
+	threshold := SlowThreshold
+	if limit > 0 {
+		threshold = time.Duration(float64(limit) * SlowFraction)
+	}
+	return latency > threshold
+}
+
+// AnswersPerMinute returns the answering rate over the time spent on questions
+func AnswersPerMinute(answers int, elapsed time.Duration) float64 {
+	if answers == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(answers) / elapsed.Minutes()
+}
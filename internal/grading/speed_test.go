@@ -0,0 +1,44 @@
+package grading
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsSlow(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		limit   time.Duration
+		want    bool
+	}{
+		{"quick without countdown", 3 * time.Second, 0, false},
+		{"slow without countdown", 9 * time.Second, 0, true},
+		{"within three quarters of countdown", 7 * time.Second, 10 * time.Second, false},
+		{"late in countdown", 8 * time.Second, 10 * time.Second, true},
+		{"short countdown", 4 * time.Second, 5 * time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsSlow(tt.latency, tt.limit); got != tt.want {
+				t.Errorf("IsSlow(%v, %v) = %v, want %v", tt.latency, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnswersPerMinute(t *testing.T) {
+	if got := AnswersPerMinute(10, 2*time.Minute); got != 5 {
+		t.Errorf("AnswersPerMinute(10, 2m) = %v, want 5", got)
+	}
+	if got := AnswersPerMinute(3, 30*time.Second); got != 6 {
+		t.Errorf("AnswersPerMinute(3, 30s) = %v, want 6", got)
+	}
+	if got := AnswersPerMinute(0, time.Minute); got != 0 {
+		t.Errorf("AnswersPerMinute(0, 1m) = %v, want 0", got)
+	}
+	if got := AnswersPerMinute(5, 0); got != 0 {
+		t.Errorf("AnswersPerMinute(5, 0) = %v, want 0", got)
+	}
+}
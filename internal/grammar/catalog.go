@@ -0,0 +1,191 @@
+package grammar
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalog.yaml
+var catalogYAML []byte
+
+// Preposition is a preposition and the cases it governs
+type Preposition struct {
+	Greek       string   `yaml:"greek"`
+	Gloss       string   `yaml:"gloss"`
+	Cases       []string `yaml:"cases"`
+	Example     string   `yaml:"example"`
+	Translation string   `yaml:"translation"`
+	Note        string   `yaml:"note"`
+}
+
+// Verb is a verb that governs the case of its object, and of its indirect object
+// (the recipient) when it takes one
+type Verb struct {
+	Lemma       string   `yaml:"lemma"`
+	Gloss       string   `yaml:"gloss"`
+	Case        string   `yaml:"case"`
+	Indirect    string   `yaml:"indirect"` // Case of the recipient, empty if the verb takes none
+	Forms       []string `yaml:"forms"`
+	Example     string   `yaml:"example"`
+	Translation string   `yaml:"translation"`
+}
+
+// Construction is a case usage that isn't tied to a single word
+type Construction struct {
+	Name        string `yaml:"name"`
+	Title       string `yaml:"title"`
+	Context     string `yaml:"context"` // Template context the construction explains
+	Case        string `yaml:"case"`
+	Description string `yaml:"description"`
+	Example     string `yaml:"example"`
+	Translation string `yaml:"translation"`
+}
+
+// Catalog holds the grammar reference data
+type Catalog struct {
+	Prepositions  []Preposition     `yaml:"prepositions"`
+	Verbs         []Verb            `yaml:"verbs"`
+	Constructions []Construction    `yaml:"constructions"`
+	Declensions   []DeclensionClass `yaml:"declensions"`
+
+	verbForms map[string]*Verb
+}
+
+var (
+	defaultCatalog *Catalog
+	loadOnce       sync.Once
+)
+
+// Default returns the embedded grammar catalog.
+// The catalog is compiled into the binary, so a parse failure is a programming error.
+func Default() *Catalog {
+	loadOnce.Do(func() {
+		catalog, err := ParseCatalog(catalogYAML)
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded grammar catalog: %v", err))
+		}
+		defaultCatalog = catalog
+	})
+	return defaultCatalog
+}
+
+// ParseCatalog parses a grammar catalog from YAML
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	catalog.verbForms = make(map[string]*Verb)
+	for i := range catalog.Verbs {
+		verb := &catalog.Verbs[i]
+		for _, form := range verb.Forms {
+			catalog.verbForms[strings.ToLower(form)] = verb
+		}
+	}
+
+	return &catalog, nil
+}
+
+// Preposition looks up a preposition by its Greek form
+func (c *Catalog) Preposition(greek string) (*Preposition, bool) {
+	for i := range c.Prepositions {
+		if c.Prepositions[i].Greek == greek {
+			return &c.Prepositions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Construction looks up a construction by name
+func (c *Catalog) Construction(name string) (*Construction, bool) {
+	for i := range c.Constructions {
+		if c.Constructions[i].Name == name {
+			return &c.Constructions[i], true
+		}
+	}
+	return nil, false
+}
+
+// ConstructionFor looks up the construction behind a template context in a case
+func (c *Catalog) ConstructionFor(contextType, caseType string) (*Construction, bool) {
+	for i := range c.Constructions {
+		if c.Constructions[i].Context == contextType && c.Constructions[i].Case == caseType {
+			return &c.Constructions[i], true
+		}
+	}
+	return nil, false
+}
+
+// ContextCases lists the cases the constructions of a template context use, or nil
+// when no construction explains the context
+func (c *Catalog) ContextCases(contextType string) []string {
+	var cases []string
+	for _, construction := range c.Constructions {
+		if construction.Context == contextType && !slices.Contains(cases, construction.Case) {
+			cases = append(cases, construction.Case)
+		}
+	}
+	return cases
+}
+
+// FindVerb returns the first catalog verb conjugated anywhere in a Greek sentence
+func (c *Catalog) FindVerb(sentence string) (*Verb, bool) {
+	words := strings.FieldsFunc(strings.ToLower(sentence), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if verb, ok := c.verbForms[word]; ok {
+			return verb, true
+		}
+	}
+	return nil, false
+}
+
+// Governs reports whether the preposition can be followed by the given case
+func (p *Preposition) Governs(caseType string) bool {
+	return slices.Contains(p.Cases, caseType)
+}
+
+// CheckTemplate verifies a template's case against the catalog: its preposition must
+// be known and govern the case, a direct or indirect object must match its verb's
+// case, and contexts explained by constructions must use one of their cases
+func (c *Catalog) CheckTemplate(greekTemplate, caseType, contextType string, preposition *string) error {
+	if preposition != nil && *preposition != "" {
+		prep, ok := c.Preposition(*preposition)
+		if !ok {
+			return fmt.Errorf("preposition '%s' is not in the grammar catalog", *preposition)
+		}
+		if !prep.Governs(caseType) {
+			return fmt.Errorf("preposition '%s' takes %s, not %s", prep.Greek, strings.Join(prep.Cases, " or "), caseType)
+		}
+	}
+
+	switch contextType {
+	case "direct_object":
+		if verb, ok := c.FindVerb(greekTemplate); ok && verb.Case != caseType {
+			return fmt.Errorf("verb '%s' takes %s, not %s", verb.Lemma, verb.Case, caseType)
+		}
+	case "indirect_object":
+		if verb, ok := c.FindVerb(greekTemplate); ok {
+			if verb.Indirect == "" {
+				return fmt.Errorf("verb '%s' takes no indirect object", verb.Lemma)
+			}
+			if verb.Indirect != caseType {
+				return fmt.Errorf("verb '%s' takes its indirect object in the %s, not %s", verb.Lemma, verb.Indirect, caseType)
+			}
+		}
+	}
+
+	if cases := c.ContextCases(contextType); len(cases) > 0 && !slices.Contains(cases, caseType) {
+		return fmt.Errorf("%s takes %s, not %s", strings.ReplaceAll(contextType, "_", " "), strings.Join(cases, " or "), caseType)
+	}
+
+	return nil
+}
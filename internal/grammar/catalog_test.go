@@ -0,0 +1,171 @@
+package grammar
+
+import "testing"
+
+func TestDefaultCatalog(t *testing.T) {
+	catalog := Default()
+
+	if len(catalog.Prepositions) == 0 || len(catalog.Verbs) == 0 || len(catalog.Constructions) == 0 {
+		t.Fatalf("Default() catalog is missing sections: %d prepositions, %d verbs, %d constructions",
+			len(catalog.Prepositions), len(catalog.Verbs), len(catalog.Constructions))
+	}
+
+	for _, prep := range catalog.Prepositions {
+		if prep.Gloss == "" || prep.Example == "" || prep.Translation == "" || len(prep.Cases) == 0 {
+			t.Errorf("preposition %q is incomplete", prep.Greek)
+		}
+	}
+	for _, verb := range catalog.Verbs {
+		if verb.Gloss == "" || verb.Case == "" || len(verb.Forms) == 0 || verb.Example == "" {
+			t.Errorf("verb %q is incomplete", verb.Lemma)
+		}
+	}
+	for _, name := range []string{"possessive_genitive", "indirect_object_genitive", "time_accusative", "time_genitive"} {
+		construction, ok := catalog.Construction(name)
+		if !ok {
+			t.Errorf("construction %q not found", name)
+			continue
+		}
+		if construction.Context == "" {
+			t.Errorf("construction %q has no context", name)
+		}
+	}
+
+	genitiveVerbs := 0
+	for _, verb := range catalog.Verbs {
+		if verb.Indirect == "genitive" {
+			genitiveVerbs++
+		}
+	}
+	if genitiveVerbs == 0 {
+		t.Error("Expected verbs taking a genitive indirect object")
+	}
+}
+
+func TestCatalogConstructionFor(t *testing.T) {
+	catalog := Default()
+
+	tests := []struct {
+		contextType string
+		caseType    string
+		want        string
+	}{
+		{"possession", "genitive", "possessive_genitive"},
+		{"indirect_object", "genitive", "indirect_object_genitive"},
+		{"time", "accusative", "time_accusative"},
+		{"time", "genitive", "time_genitive"},
+		{"time", "nominative", ""},
+		{"direct_object", "accusative", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contextType+" "+tt.caseType, func(t *testing.T) {
+			construction, ok := catalog.ConstructionFor(tt.contextType, tt.caseType)
+			if ok != (tt.want != "") {
+				t.Fatalf("ConstructionFor() ok = %v, want %v", ok, tt.want != "")
+			}
+			if ok && construction.Name != tt.want {
+				t.Errorf("ConstructionFor() = %q, want %q", construction.Name, tt.want)
+			}
+		})
+	}
+
+	if cases := catalog.ContextCases("time"); len(cases) != 2 {
+		t.Errorf("ContextCases(time) = %v, want accusative and genitive", cases)
+	}
+	if cases := catalog.ContextCases("preposition"); cases != nil {
+		t.Errorf("ContextCases(preposition) = %v, want nil", cases)
+	}
+}
+
+func TestCatalogPreposition(t *testing.T) {
+	catalog := Default()
+
+	se, ok := catalog.Preposition("σε")
+	if !ok {
+		t.Fatal("Preposition(σε) not found")
+	}
+	if !se.Governs("accusative") || se.Governs("genitive") {
+		t.Errorf("σε cases = %v, want accusative only", se.Cases)
+	}
+
+	kata, ok := catalog.Preposition("κατά")
+	if !ok {
+		t.Fatal("Preposition(κατά) not found")
+	}
+	if !kata.Governs("accusative") || !kata.Governs("genitive") {
+		t.Errorf("κατά cases = %v, want accusative and genitive", kata.Cases)
+	}
+
+	if _, ok := catalog.Preposition("foo"); ok {
+		t.Error("Preposition(foo) should not be found")
+	}
+}
+
+func TestCatalogFindVerb(t *testing.T) {
+	catalog := Default()
+
+	tests := []struct {
+		sentence string
+		want     string
+		wantOK   bool
+	}{
+		{"Βλέπω {article} {noun_form}", "βλέπω", true},
+		{"Εμείς ψάχνουμε {article} {noun_form}.", "ψάχνω", true},
+		{"Σκέφτονται {article} {noun_form}", "σκέφτομαι", true},
+		{"Η τσάντα {article} {noun_form}", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sentence, func(t *testing.T) {
+			verb, ok := catalog.FindVerb(tt.sentence)
+			if ok != tt.wantOK {
+				t.Fatalf("FindVerb() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && verb.Lemma != tt.want {
+				t.Errorf("FindVerb() = %q, want %q", verb.Lemma, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalogCheckTemplate(t *testing.T) {
+	catalog := Default()
+	se := "σε"
+	enantion := "εναντίον"
+	unknown := "foo"
+
+	tests := []struct {
+		name        string
+		greek       string
+		caseType    string
+		contextType string
+		prep        *string
+		wantErr     bool
+	}{
+		{"σε with accusative", "Πάω σε {article} {noun_form}", "accusative", "preposition", &se, false},
+		{"σε with genitive", "Πάω σε {article} {noun_form}", "genitive", "preposition", &se, true},
+		{"εναντίον with genitive", "Ψήφισε εναντίον {article} {noun_form}", "genitive", "preposition", &enantion, false},
+		{"unknown preposition", "foo {article} {noun_form}", "accusative", "preposition", &unknown, true},
+		{"verb with accusative", "Βλέπω {article} {noun_form}", "accusative", "direct_object", nil, false},
+		{"verb with genitive", "Βλέπω {article} {noun_form}", "genitive", "direct_object", nil, true},
+		{"possession ignores verbs", "Βλέπω το σπίτι {article} {noun_form}", "genitive", "possession", nil, false},
+		{"possession with nominative", "Το σπίτι {article} {noun_form}", "nominative", "possession", nil, true},
+		{"recipient verb with its object", "Δίνω {article} {noun_form} στη γιαγιά", "accusative", "direct_object", nil, false},
+		{"recipient in the genitive", "Δίνω {article} {noun_form} το βιβλίο", "genitive", "indirect_object", nil, false},
+		{"recipient in the accusative", "Δίνω {article} {noun_form} το βιβλίο", "accusative", "indirect_object", nil, true},
+		{"verb without a recipient", "Βλέπω {article} {noun_form}", "genitive", "indirect_object", nil, true},
+		{"time in the accusative", "{article} {noun_form} πάμε εκδρομή", "accusative", "time", nil, false},
+		{"time in the genitive", "Θα τα πούμε {article} {noun_form}", "genitive", "time", nil, false},
+		{"time in the nominative", "{article} {noun_form} πάμε εκδρομή", "nominative", "time", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := catalog.CheckTemplate(tt.greek, tt.caseType, tt.contextType, tt.prep)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
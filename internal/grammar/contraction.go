@@ -0,0 +1,69 @@
+package grammar
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// contractions maps the definite articles that fuse with σε to the contracted form
+var contractions = map[string]string{
+	"τον":  "στον",
+	"την":  "στην",
+	"τη":   "στη",
+	"το":   "στο",
+	"του":  "στου",
+	"της":  "στης",
+	"τους": "στους",
+	"τις":  "στις",
+	"τα":   "στα",
+	"των":  "στων",
+}
+
+// Contract returns the fused form of a preposition and article (σε + τον → στον).
+// It reports false when the pair does not contract.
+func Contract(preposition, article string) (string, bool) {
+	if preposition != "σε" {
+		return "", false
+	}
+	contracted, ok := contractions[article]
+	return contracted, ok
+}
+
+// ContractionPatterns returns the ways a template may write a contracting preposition
+// in front of the {article} placeholder, including the elided form used by older templates
+func ContractionPatterns(preposition string) []string {
+	if preposition != "σε" {
+		return nil
+	}
+	return []string{"σε {article}", "σ'{article}", "σ{article}"}
+}
+
+// ArticleVariant returns the alternative spelling of a feminine article that may
+// drop its final ν (την/τη, στην/στη) in front of word, or the empty string if there
+// is none. The ν is only dropped before a consonant that does not need it.
+func ArticleVariant(article, word string) string {
+	switch article {
+	case "την", "στην":
+		if !dropsFinalN(word) {
+			return ""
+		}
+		return strings.TrimSuffix(article, "ν")
+	case "τη", "στη":
+		return article + "ν"
+	default:
+		return ""
+	}
+}
+
+// dropsFinalN reports whether a feminine article may lose its final ν before word.
+// The ν stays before vowels and before κ, π, τ, ξ, ψ, μπ, ντ, γκ, τσ and τζ.
+func dropsFinalN(word string) bool {
+	word = strings.ToLower(word)
+	for _, cluster := range []string{"μπ", "ντ", "γκ"} {
+		if strings.HasPrefix(word, cluster) {
+			return false
+		}
+	}
+	first, _ := utf8.DecodeRuneInString(word)
+	return strings.ContainsRune("βγδζθλμνρσφχ", first)
+}
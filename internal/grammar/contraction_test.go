@@ -0,0 +1,54 @@
+package grammar
+
+import "testing"
+
+func TestContract(t *testing.T) {
+	tests := []struct {
+		prep    string
+		article string
+		want    string
+		wantOK  bool
+	}{
+		{"σε", "τον", "στον", true},
+		{"σε", "τη", "στη", true},
+		{"σε", "τους", "στους", true},
+		{"σε", "τα", "στα", true},
+		{"σε", "ο", "", false},
+		{"για", "τον", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prep+" "+tt.article, func(t *testing.T) {
+			got, ok := Contract(tt.prep, tt.article)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Contract(%q, %q) = (%q, %v), want (%q, %v)", tt.prep, tt.article, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestArticleVariant(t *testing.T) {
+	tests := []struct {
+		article string
+		word    string
+		want    string
+	}{
+		{"την", "γυναίκα", "τη"},
+		{"στην", "θάλασσα", "στη"},
+		{"την", "αγορά", ""},
+		{"στην", "Αθήνα", ""},
+		{"την", "πόρτα", ""},
+		{"την", "κουζίνα", ""},
+		{"την", "μπάλα", ""},
+		{"τη", "πόρτα", "την"},
+		{"στη", "γυναίκα", "στην"},
+		{"τον", "δάσκαλο", ""},
+		{"το", "βιβλίο", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ArticleVariant(tt.article, tt.word); got != tt.want {
+			t.Errorf("ArticleVariant(%q, %q) = %q, want %q", tt.article, tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package grammar
+
+import (
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// Endings lists the six case endings of a declension class, without accents
+type Endings struct {
+	NomSg string `yaml:"nom_sg"`
+	GenSg string `yaml:"gen_sg"`
+	AccSg string `yaml:"acc_sg"`
+	NomPl string `yaml:"nom_pl"`
+	GenPl string `yaml:"gen_pl"`
+	AccPl string `yaml:"acc_pl"`
+}
+
+// DeclensionClass is a noun inflection pattern
+type DeclensionClass struct {
+	Name    string  `yaml:"name"`
+	Title   string  `yaml:"title"`
+	Gender  string  `yaml:"gender"`
+	Example string  `yaml:"example"`
+	Endings Endings `yaml:"endings"`
+}
+
+// Ending returns the class ending for a case and number
+func (d *DeclensionClass) Ending(caseType, number string) string {
+	e := d.Endings
+	switch caseType + "/" + number {
+	case "nominative/singular":
+		return e.NomSg
+	case "genitive/singular":
+		return e.GenSg
+	case "accusative/singular":
+		return e.AccSg
+	case "nominative/plural":
+		return e.NomPl
+	case "genitive/plural":
+		return e.GenPl
+	case "accusative/plural":
+		return e.AccPl
+	default:
+		return ""
+	}
+}
+
+// accents maps accented Greek vowels to their plain form
+var accents = strings.NewReplacer(
+	"ά", "α", "έ", "ε", "ή", "η", "ί", "ι", "ό", "ο", "ύ", "υ", "ώ", "ω",
+	"ϊ", "ι", "ϋ", "υ", "ΐ", "ι", "ΰ", "υ",
+	"Ά", "Α", "Έ", "Ε", "Ή", "Η", "Ί", "Ι", "Ό", "Ο", "Ύ", "Υ", "Ώ", "Ω",
+	"Ϊ", "Ι", "Ϋ", "Υ",
+)
+
+// StripAccents removes tonos and diaeresis marks from Greek text
+func StripAccents(s string) string {
+	return accents.Replace(s)
+}
+
+// SplitEnding splits a form into stem and an ending of the given length in letters.
+// The returned ending keeps the form's own accents.
+func SplitEnding(form, ending string) (stem, suffix string) {
+	runes := []rune(form)
+	n := len([]rune(ending))
+	if n > len(runes) {
+		return "", form
+	}
+	return string(runes[:len(runes)-n]), string(runes[len(runes)-n:])
+}
+
+// Classify identifies the declension class of a noun by matching all six forms
+func (c *Catalog) Classify(noun *models.Noun) (*DeclensionClass, bool) {
+	forms := map[string]string{
+		"nominative/singular": noun.NominativeSg,
+		"genitive/singular":   noun.GenitiveSg,
+		"accusative/singular": noun.AccusativeSg,
+		"nominative/plural":   noun.NominativePl,
+		"genitive/plural":     noun.GenitivePl,
+		"accusative/plural":   noun.AccusativePl,
+	}
+
+	for i := range c.Declensions {
+		class := &c.Declensions[i]
+		if class.Gender != noun.Gender {
+			continue
+		}
+
+		matched := true
+		for key, form := range forms {
+			caseType, number, _ := strings.Cut(key, "/")
+			if !strings.HasSuffix(StripAccents(form), class.Ending(caseType, number)) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return class, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsInvariable reports whether all six forms of a noun are identical
+func IsInvariable(noun *models.Noun) bool {
+	for _, form := range []string{noun.GenitiveSg, noun.AccusativeSg, noun.NominativePl, noun.GenitivePl, noun.AccusativePl} {
+		if form != noun.NominativeSg {
+			return false
+		}
+	}
+	return true
+}
+
+// isVowel reports whether an unaccented lower-case rune is a Greek vowel
+func isVowel(r rune) bool {
+	return strings.ContainsRune("αεηιουω", r)
+}
+
+// isDiphthong reports whether two unaccented vowels are pronounced as one syllable
+func isDiphthong(first, second rune) bool {
+	switch string([]rune{first, second}) {
+	case "αι", "ει", "οι", "υι", "ου", "αυ", "ευ":
+		return true
+	default:
+		return false
+	}
+}
+
+// syllables counts the vowel groups of a word and the index of the accented one,
+// or -1 if the word has no tonos
+func syllables(word string) (count, stressed int) {
+	original := []rune(strings.ToLower(word))
+	plain := []rune(StripAccents(strings.ToLower(word)))
+
+	stressed = -1
+	for i := 0; i < len(plain); i++ {
+		if !isVowel(plain[i]) {
+			continue
+		}
+		accented := original[i] != plain[i] && !strings.ContainsRune("ϊϋ", original[i])
+
+		// A diaeresis on the second vowel breaks the diphthong
+		if i+1 < len(plain) && isDiphthong(plain[i], plain[i+1]) && !strings.ContainsRune("ϊϋΐΰ", original[i+1]) {
+			if original[i+1] != plain[i+1] {
+				accented = true
+			}
+			i++
+		}
+
+		if accented {
+			stressed = count
+		}
+		count++
+	}
+
+	return count, stressed
+}
+
+// SyllableCount returns the number of syllables in a Greek word
+func SyllableCount(word string) int {
+	count, _ := syllables(word)
+	return count
+}
+
+// StressPosition returns the syllable carrying the accent, counted from the end of
+// the word (1 = last, 2 = penultimate, 3 = antepenultimate), or 0 if there is none
+func StressPosition(word string) int {
+	count, stressed := syllables(word)
+	if stressed == -1 {
+		return 0
+	}
+	return count - stressed
+}
+
+// Declension looks up a declension class by name
+func (c *Catalog) Declension(name string) (*DeclensionClass, bool) {
+	for i := range c.Declensions {
+		if c.Declensions[i].Name == name {
+			return &c.Declensions[i], true
+		}
+	}
+	return nil, false
+}
+
+// nominativeGenders maps a nominative singular article to its gender
+var nominativeGenders = map[string]string{
+	"ο":  "masculine",
+	"η":  "feminine",
+	"το": "neuter",
+}
+
+// GrammaticalGender returns a noun's gender, reading it from the nominative article
+// for invariable nouns
+func GrammaticalGender(noun *models.Noun) string {
+	if noun.Gender != "invariable" {
+		return noun.Gender
+	}
+	return nominativeGenders[noun.NomSgArticle]
+}
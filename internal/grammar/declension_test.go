@@ -0,0 +1,105 @@
+package grammar
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestStressPosition(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"δάσκαλος", 3},
+		{"δασκάλου", 2},
+		{"μαθητής", 1},
+		{"γυναίκα", 2},
+		{"παιδιού", 1},
+		{"ταΐζω", 2},
+		{"και", 0},
+	}
+
+	for _, tt := range tests {
+		if got := StressPosition(tt.word); got != tt.want {
+			t.Errorf("StressPosition(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSyllableCount(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"δάσκαλος", 3},
+		{"γυναίκα", 3},
+		{"ταΐζω", 3},
+		{"ουρανός", 3},
+	}
+
+	for _, tt := range tests {
+		if got := SyllableCount(tt.word); got != tt.want {
+			t.Errorf("SyllableCount(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		noun *models.Noun
+		want string
+	}{
+		{
+			name: "masculine in -ος",
+			noun: &models.Noun{Gender: "masculine", NominativeSg: "δάσκαλος", GenitiveSg: "δασκάλου", AccusativeSg: "δάσκαλο",
+				NominativePl: "δάσκαλοι", GenitivePl: "δασκάλων", AccusativePl: "δασκάλους"},
+			want: "masculine_os",
+		},
+		{
+			name: "feminine in -η with plural in -εις",
+			noun: &models.Noun{Gender: "feminine", NominativeSg: "πόλη", GenitiveSg: "πόλης", AccusativeSg: "πόλη",
+				NominativePl: "πόλεις", GenitivePl: "πόλεων", AccusativePl: "πόλεις"},
+			want: "feminine_i_eis",
+		},
+		{
+			name: "neuter in -μα",
+			noun: &models.Noun{Gender: "neuter", NominativeSg: "γράμμα", GenitiveSg: "γράμματος", AccusativeSg: "γράμμα",
+				NominativePl: "γράμματα", GenitivePl: "γραμμάτων", AccusativePl: "γράμματα"},
+			want: "neuter_ma",
+		},
+		{
+			name: "invariable loanword",
+			noun: &models.Noun{Gender: "neuter", NominativeSg: "ταξί", GenitiveSg: "ταξί", AccusativeSg: "ταξί",
+				NominativePl: "ταξί", GenitivePl: "ταξί", AccusativePl: "ταξί"},
+			want: "",
+		},
+	}
+
+	catalog := Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, ok := catalog.Classify(tt.noun)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Classify() = %s, want no class", class.Name)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Classify() found no class, want %s", tt.want)
+			}
+			if class.Name != tt.want {
+				t.Errorf("Classify() = %s, want %s", class.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitEnding(t *testing.T) {
+	stem, suffix := SplitEnding("δασκάλου", "ου")
+	if stem != "δασκάλ" || suffix != "ου" {
+		t.Errorf("SplitEnding() = %q, %q, want δασκάλ, ου", stem, suffix)
+	}
+}
@@ -1,8 +1,11 @@
 package importer
 
 import (
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -12,7 +15,29 @@ type CSVRow struct {
 	English string
 	Greek   string
 	Gender  string
-	RowNum  int // For checkpoint tracking
+	RowNum  int // Line in the file, for reporting
+}
+
+// Hash identifies the row by its content, so a row can be recognized after the
+// rows around it are edited, reordered or appended to
+func (r CSVRow) Hash() string {
+	sum := sha256.Sum256([]byte(r.English + "\x1f" + r.Greek + "\x1f" + r.Gender))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashFile returns the SHA-256 of a file's content
+func HashFile(filepath string) (string, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash CSV file: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 // ValidateGender checks if the gender value is valid
@@ -163,3 +163,64 @@ student,μαθητής,masculine`
 		t.Errorf("Expected greek 'μαθητής', got %q", rows[1].Greek)
 	}
 }
+
+func TestCSVRowHash(t *testing.T) {
+	row := CSVRow{English: "teacher", Greek: "δάσκαλος", Gender: "masculine", RowNum: 2}
+
+	// The hash depends on the content, not on where the row sits in the file
+	moved := row
+	moved.RowNum = 7
+	if row.Hash() != moved.Hash() {
+		t.Error("Expected the same row on another line to have the same hash")
+	}
+
+	edited := row
+	edited.English = "instructor"
+	if row.Hash() == edited.Hash() {
+		t.Error("Expected an edited row to have a different hash")
+	}
+
+	// Fields are separated so that text can't shift from one to the next
+	shifted := CSVRow{English: "teacherδ", Greek: "άσκαλος", Gender: "masculine"}
+	if row.Hash() == shifted.Hash() {
+		t.Error("Expected rows with text shifted between fields to have different hashes")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	first := filepath.Join(tmpDir, "a", "nouns.csv")
+	second := filepath.Join(tmpDir, "b", "nouns.csv")
+
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(first, "english,greek,attribute\nteacher,δάσκαλος,masculine\n")
+	write(second, "english,greek,attribute\nbook,βιβλίο,neuter\n")
+
+	firstHash, err := HashFile(first)
+	if err != nil {
+		t.Fatalf("HashFile() error = %v", err)
+	}
+	secondHash, err := HashFile(second)
+	if err != nil {
+		t.Fatalf("HashFile() error = %v", err)
+	}
+	if firstHash == secondHash {
+		t.Error("Expected files with the same name and different content to have different hashes")
+	}
+
+	write(second, "english,greek,attribute\nteacher,δάσκαλος,masculine\n")
+	if secondHash, _ = HashFile(second); firstHash != secondHash {
+		t.Error("Expected files with the same content to have the same hash")
+	}
+
+	if _, err := HashFile(filepath.Join(tmpDir, "missing.csv")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
@@ -2,6 +2,7 @@ package importer
 
 import (
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,12 +14,15 @@ import (
 
 // ImportProcessor orchestrates the CSV import process
 type ImportProcessor struct {
-	repo   storage.Repository
+	repo   *storage.SQLiteRepository
 	client *ai.ClaudeClient
+
+	apiCalls int
+	skipped  int // Rows a resumed import found already imported
 }
 
 // NewImportProcessor creates a new import processor
-func NewImportProcessor(repo storage.Repository, client *ai.ClaudeClient) *ImportProcessor {
+func NewImportProcessor(repo *storage.SQLiteRepository, client *ai.ClaudeClient) *ImportProcessor {
 	return &ImportProcessor{
 		repo:   repo,
 		client: client,
@@ -36,114 +40,327 @@ func (p *ImportProcessor) ProcessImport(csvPath string) error {
 
 	fmt.Printf("Found %d nouns to import\n", len(rows))
 
-	// Check for existing checkpoint
-	filename := filepath.Base(csvPath)
-	checkpoint, err := p.repo.(*storage.SQLiteRepository).GetCheckpointByFilename(filename)
+	// Find the checkpoint by content, then by path for a file edited since
+	fileHash, err := HashFile(csvPath)
+	if err != nil {
+		return err
+	}
+	absPath, err := filepath.Abs(csvPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve CSV path: %w", err)
+	}
+
+	checkpoint, err := p.repo.GetCheckpointByHash(fileHash)
 	if err != nil {
 		return fmt.Errorf("failed to check for checkpoint: %w", err)
 	}
+	if checkpoint == nil {
+		if checkpoint, err = p.repo.GetCheckpointByPath(absPath); err != nil {
+			return fmt.Errorf("failed to check for checkpoint: %w", err)
+		}
+	}
+	sameFile := checkpoint != nil && checkpoint.FileHash == fileHash
 
-	startRow := 0
+	// Rows are skipped on resume by content rather than position, so edits,
+	// reorders and appends since the interruption don't shift what is skipped
+	imported := map[string]bool{}
+	resume := false
 	if checkpoint != nil && checkpoint.Status == "in_progress" {
-		fmt.Printf("\nFound existing import in progress (last processed row: %d)\n", checkpoint.LastProcessedRow)
+		if sameFile {
+			fmt.Printf("\nFound existing import in progress (%d rows processed)\n", checkpoint.LastProcessedRow)
+		} else {
+			fmt.Printf("\nFound an unfinished import of this file, which has changed since (%d rows processed)\n", checkpoint.LastProcessedRow)
+		}
 		fmt.Print("Resume from checkpoint? (y/n): ")
 		var response string
 		fmt.Scanln(&response)
 		if response == "y" || response == "Y" {
-			startRow = checkpoint.LastProcessedRow
-			fmt.Printf("Resuming from row %d\n", startRow+1)
+			if imported, err = p.repo.ListImportedRowHashes(checkpoint.ID); err != nil {
+				return err
+			}
+			resume = true
 		} else {
 			fmt.Println("Starting fresh import")
-			startRow = 0
 		}
 	}
 
+	p.skipped = 0
+	for _, row := range rows {
+		if imported[row.Hash()] {
+			p.skipped++
+		}
+	}
+	if resume {
+		fmt.Printf("Resuming; %d of %d rows were already imported and will be skipped\n", p.skipped, len(rows))
+	}
+
 	// Create or update checkpoint
 	if checkpoint == nil {
 		checkpoint = &storage.ImportCheckpoint{
-			CSVFilename:      filename,
+			CSVFilename:      filepath.Base(csvPath),
+			CSVPath:          absPath,
+			FileHash:         fileHash,
 			LastProcessedRow: 0,
 			Status:           "in_progress",
 		}
-		if err := p.repo.(*storage.SQLiteRepository).CreateCheckpoint(checkpoint); err != nil {
+		if err := p.repo.CreateCheckpoint(checkpoint); err != nil {
 			return fmt.Errorf("failed to create checkpoint: %w", err)
 		}
 	} else {
+		checkpoint.CSVPath = absPath
+		checkpoint.FileHash = fileHash
 		checkpoint.Status = "in_progress"
-		checkpoint.LastProcessedRow = startRow
-		if err := p.repo.(*storage.SQLiteRepository).UpdateCheckpoint(checkpoint); err != nil {
+		checkpoint.LastProcessedRow = 0
+		if err := p.repo.UpdateCheckpoint(checkpoint); err != nil {
 			return fmt.Errorf("failed to update checkpoint: %w", err)
 		}
 	}
 
+	// A resumed import of an unchanged file continues the run that was
+	// interrupted. An edited file's row numbers no longer match that run's, so
+	// the run is closed and the import continues as a new one.
+	run, err := p.repo.GetInProgressImportRun(checkpoint.ID)
+	if err != nil {
+		return err
+	}
+	if run != nil && !(resume && sameFile) {
+		if err := p.repo.FinishImportRun(run.ID); err != nil {
+			return err
+		}
+		run = nil
+	}
+	if run == nil {
+		run = &storage.ImportRun{CSVPath: csvPath, CheckpointID: &checkpoint.ID}
+		if err := p.repo.CreateImportRun(run); err != nil {
+			return err
+		}
+	}
+
+	slog.Info("Import started", "file", csvPath, "hash", fileHash, "run", run.ID, "rows", len(rows), "resumed", resume, "skipping", p.skipped)
+
 	// Track statistics
-	apiCalls := 0
+	p.apiCalls = 0
 	startTime := time.Now()
 
 	// Process each row
-	for i := startRow; i < len(rows); i++ {
-		row := rows[i]
-
-		fmt.Printf("\n[%d/%d] Processing '%s' (%s)...\n", i+1, len(rows), row.English, row.Greek)
-
-		// Generate declensions
-		fmt.Print("  → Generating declensions... ")
-		declensions, err := p.client.GenerateDeclensions(row.Greek, row.English, row.Gender)
-		if err != nil {
-			fmt.Printf("FAILED\n")
-			fmt.Printf("     Error: %v\n", err)
-			fmt.Printf("     Skipping this noun and continuing...\n")
+	for i, row := range rows {
+		if imported[row.Hash()] {
 			continue
 		}
-		apiCalls++
-		fmt.Println("✓")
-
-		// Create noun record
-		noun := &models.Noun{
-			English:      row.English,
-			Gender:       row.Gender,
-			NominativeSg: declensions.NominativeSg,
-			GenitiveSg:   declensions.GenitiveSg,
-			AccusativeSg: declensions.AccusativeSg,
-			NominativePl: declensions.NominativePl,
-			GenitivePl:   declensions.GenitivePl,
-			AccusativePl: declensions.AccusativePl,
-			NomSgArticle: declensions.NomSgArticle,
-			GenSgArticle: declensions.GenSgArticle,
-			AccSgArticle: declensions.AccSgArticle,
-			NomPlArticle: declensions.NomPlArticle,
-			GenPlArticle: declensions.GenPlArticle,
-			AccPlArticle: declensions.AccPlArticle,
-		}
 
-		if err := p.repo.CreateNoun(noun); err != nil {
-			fmt.Printf("     Error storing noun: %v\n", err)
-			fmt.Printf("     Skipping this noun and continuing...\n")
-			continue
-		}
-		fmt.Println("✓")
+		fmt.Printf("\n[%d/%d] Processing '%s' (%s)...\n", i+1, len(rows), row.English, row.Greek)
+		p.processRow(run, row)
 
 		// Update checkpoint after each noun
 		checkpoint.LastProcessedRow = i + 1
-		if err := p.repo.(*storage.SQLiteRepository).UpdateCheckpoint(checkpoint); err != nil {
+		if err := p.repo.UpdateCheckpoint(checkpoint); err != nil {
 			fmt.Printf("     Warning: Failed to update checkpoint: %v\n", err)
+			slog.Warn("Failed to update checkpoint", "file", csvPath, "row", i+1, "error", err)
 		}
 	}
 
-	// Mark checkpoint as completed
+	counts, err := p.finishRun(run)
+	if err != nil {
+		return err
+	}
+
+	// Mark checkpoint as finished; failed rows are retried by run, not by checkpoint
 	checkpoint.Status = "completed"
-	if err := p.repo.(*storage.SQLiteRepository).UpdateCheckpoint(checkpoint); err != nil {
-		fmt.Printf("Warning: Failed to mark checkpoint as completed: %v\n", err)
+	if counts.Failed > 0 {
+		checkpoint.Status = "failed"
+	}
+	if err := p.repo.UpdateCheckpoint(checkpoint); err != nil {
+		fmt.Printf("Warning: Failed to mark checkpoint as %s: %v\n", checkpoint.Status, err)
+		slog.Warn("Failed to mark checkpoint as finished", "file", csvPath, "status", checkpoint.Status, "error", err)
+	}
+
+	return p.printSummary(run, counts, time.Since(startTime))
+}
+
+// RetryFailed reprocesses the rows that failed in an earlier import run as a new run.
+// The rows are taken from the ledger, so the CSV file is not needed.
+func (p *ImportProcessor) RetryFailed(runID int64) error {
+	previous, err := p.repo.GetImportRun(runID)
+	if err != nil {
+		return err
+	}
+	failed, err := p.repo.ListImportRows(previous.ID, storage.ImportRowFailed)
+	if err != nil {
+		return err
+	}
+	if len(failed) == 0 {
+		fmt.Printf("Import run %d has no failed rows to retry\n", previous.ID)
+		return nil
+	}
+
+	fmt.Printf("Retrying %d failed rows from import run %d (%s)\n", len(failed), previous.ID, previous.CSVPath)
+
+	run := &storage.ImportRun{CSVPath: previous.CSVPath, RetryOf: &previous.ID}
+	if err := p.repo.CreateImportRun(run); err != nil {
+		return err
+	}
+	slog.Info("Import retry started", "run", run.ID, "retry_of", previous.ID, "rows", len(failed))
+
+	p.apiCalls = 0
+	p.skipped = 0
+	startTime := time.Now()
+
+	for i, failedRow := range failed {
+		row := CSVRow{English: failedRow.English, Greek: failedRow.Greek, Gender: failedRow.Gender, RowNum: failedRow.RowNum}
+		fmt.Printf("\n[%d/%d] Retrying row %d '%s' (%s)...\n", i+1, len(failed), row.RowNum, row.English, row.Greek)
+		p.processRow(run, row)
+	}
+
+	counts, err := p.finishRun(run)
+	if err != nil {
+		return err
+	}
+	return p.printSummary(run, counts, time.Since(startTime))
+}
+
+// processRow imports one CSV row and records its outcome in the run's ledger.
+// Failures are reported and recorded rather than stopping the import.
+func (p *ImportProcessor) processRow(run *storage.ImportRun, row CSVRow) {
+	entry := &storage.ImportRow{
+		RunID:   run.ID,
+		RowNum:  row.RowNum,
+		RowHash: row.Hash(),
+		English: row.English,
+		Greek:   row.Greek,
+		Gender:  row.Gender,
+		Status:  storage.ImportRowFailed,
+	}
+	defer p.record(entry)
+
+	// Skip nouns that are already in the database before spending an API call
+	existing, err := p.repo.FindNoun(row.Greek, row.Gender)
+	if err != nil {
+		entry.Error = err.Error()
+		fmt.Printf("     Error: %v\n", err)
+		return
+	}
+	if existing != nil {
+		entry.Status = storage.ImportRowDuplicate
+		entry.NounID = &existing.ID
+		fmt.Printf("  → Already imported as noun %d, skipping\n", existing.ID)
+		slog.Info("Skipped duplicate noun", "row", row.RowNum, "noun", row.Greek, "id", existing.ID)
+		return
 	}
 
-	// Print summary
-	duration := time.Since(startTime)
+	// Generate declensions
+	fmt.Print("  → Generating declensions... ")
+	declensions, err := p.client.GenerateDeclensions(row.Greek, row.English, row.Gender)
+	if err != nil {
+		entry.Error = err.Error()
+		fmt.Printf("FAILED\n")
+		fmt.Printf("     Error: %v\n", err)
+		fmt.Printf("     Recorded as failed; continuing...\n")
+		slog.Warn("Noun failed: declensions failed", "row", row.RowNum, "noun", row.Greek, "error", err)
+		return
+	}
+	p.apiCalls++
+	fmt.Println("✓")
+
+	// Create noun record
+	noun := &models.Noun{
+		English:      row.English,
+		Gender:       row.Gender,
+		NominativeSg: declensions.NominativeSg,
+		GenitiveSg:   declensions.GenitiveSg,
+		AccusativeSg: declensions.AccusativeSg,
+		NominativePl: declensions.NominativePl,
+		GenitivePl:   declensions.GenitivePl,
+		AccusativePl: declensions.AccusativePl,
+		NomSgArticle: declensions.NomSgArticle,
+		GenSgArticle: declensions.GenSgArticle,
+		AccSgArticle: declensions.AccSgArticle,
+		NomPlArticle: declensions.NomPlArticle,
+		GenPlArticle: declensions.GenPlArticle,
+		AccPlArticle: declensions.AccPlArticle,
+	}
+
+	if err := p.repo.CreateNoun(noun); err != nil {
+		entry.Error = err.Error()
+		fmt.Printf("     Error storing noun: %v\n", err)
+		fmt.Printf("     Recorded as failed; continuing...\n")
+		slog.Warn("Noun failed: store failed", "row", row.RowNum, "noun", row.Greek, "error", err)
+		return
+	}
+	entry.Status = storage.ImportRowImported
+	entry.NounID = &noun.ID
+	fmt.Println("✓")
+	slog.Info("Imported noun", "row", row.RowNum, "noun", row.Greek, "id", noun.ID)
+
+	// Classify noun with semantic tags (non-fatal)
+	fmt.Print("  → Generating tags... ")
+	tags, err := p.client.GenerateNounTags(row.Greek, row.English)
+	if err != nil {
+		fmt.Printf("FAILED\n")
+		fmt.Printf("     Warning: %v\n", err)
+		slog.Warn("Tag generation failed", "noun", row.Greek, "error", err)
+		return
+	}
+	p.apiCalls++
+	if err := p.repo.SetNounTags(noun.ID, tags); err != nil {
+		fmt.Printf("FAILED\n")
+		fmt.Printf("     Warning: Failed to store tags: %v\n", err)
+		slog.Warn("Failed to store tags", "noun", row.Greek, "error", err)
+		return
+	}
+	fmt.Println("✓")
+}
+
+// record saves a row outcome in the ledger, warning if it cannot
+func (p *ImportProcessor) record(entry *storage.ImportRow) {
+	if err := p.repo.RecordImportRow(entry); err != nil {
+		fmt.Printf("     Warning: %v\n", err)
+		slog.Warn("Failed to record import row", "run", entry.RunID, "row", entry.RowNum, "error", err)
+	}
+}
+
+// finishRun marks a run as completed and returns its row counts
+func (p *ImportProcessor) finishRun(run *storage.ImportRun) (*storage.ImportRunCounts, error) {
+	if err := p.repo.FinishImportRun(run.ID); err != nil {
+		return nil, err
+	}
+	return p.repo.CountImportRows(run.ID)
+}
+
+// printSummary reports the outcome of a run, listing failed rows and how to retry them
+func (p *ImportProcessor) printSummary(run *storage.ImportRun, counts *storage.ImportRunCounts, duration time.Duration) error {
+	slog.Info("Import finished", "file", run.CSVPath, "run", run.ID,
+		"imported", counts.Imported, "failed", counts.Failed, "duplicates", counts.Duplicates, "resume_skipped", p.skipped,
+		"api_calls", p.apiCalls, "duration", duration)
+
 	fmt.Print("\n" + strings.Repeat("=", 50) + "\n")
-	fmt.Println("Import Complete!")
-	fmt.Printf("  Nouns imported: %d\n", len(rows)-startRow)
-	fmt.Printf("  API calls made: %d\n", apiCalls)
+	if counts.Failed == 0 {
+		fmt.Println("Import Complete!")
+	} else {
+		fmt.Println("Import Finished With Errors")
+	}
+	fmt.Printf("  Import run: %d\n", run.ID)
+	fmt.Printf("  Nouns imported: %d\n", counts.Imported)
+	fmt.Printf("  Duplicates skipped: %d\n", counts.Duplicates)
+	if p.skipped > 0 {
+		fmt.Printf("  Already imported before resuming: %d\n", p.skipped)
+	}
+	fmt.Printf("  Failed: %d\n", counts.Failed)
+	fmt.Printf("  API calls made: %d\n", p.apiCalls)
 	fmt.Printf("  Time elapsed: %s\n", duration.Round(time.Second))
 	fmt.Println(strings.Repeat("=", 50))
 
+	if counts.Failed == 0 {
+		return nil
+	}
+
+	failed, err := p.repo.ListImportRows(run.ID, storage.ImportRowFailed)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\nFailed rows:")
+	for _, row := range failed {
+		fmt.Printf("  Row %d '%s' (%s): %s\n", row.RowNum, row.English, row.Greek, row.Error)
+	}
+	fmt.Printf("\nRetry them with 'greekmaster import --retry-failed %d'\n", run.ID)
 	return nil
 }
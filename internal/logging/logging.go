@@ -0,0 +1,173 @@
+// Package logging sets up the application's leveled log/slog logger: records go
+// to a rotated log file and, when they matter to the user, to stderr
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Formats accepted for log records
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Verbosity chooses how much is logged
+type Verbosity int
+
+const (
+	Normal  Verbosity = iota // Info and above to the file, errors to stderr
+	Verbose                  // Everything, including AI requests and responses, to the file and stderr
+	Quiet                    // Only warnings and errors to the file, nothing to stderr
+)
+
+// Options configures Setup
+type Options struct {
+	Path       string    // Log file; empty logs to stderr only
+	Format     string    // FormatText or FormatJSON
+	Verbosity  Verbosity // Amount of detail
+	MaxSize    int64     // Rotate the file once it would grow past this many bytes
+	MaxBackups int       // Rotated files kept beside the log file
+	Stderr     io.Writer // Console output, os.Stderr by default
+}
+
+// Rotation defaults for Options
+const (
+	DefaultMaxSize    = 5 << 20
+	DefaultMaxBackups = 3
+)
+
+// Setup builds the logger described by opts and installs it as the slog default.
+// Close the returned closer to flush and close the log file.
+func Setup(opts Options) (*slog.Logger, io.Closer, error) {
+	if opts.Format == "" {
+		opts.Format = FormatText
+	}
+	if opts.Format != FormatText && opts.Format != FormatJSON {
+		return nil, nil, fmt.Errorf("invalid log format '%s', must be %s or %s", opts.Format, FormatText, FormatJSON)
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+
+	fileLevel, consoleLevel := slog.LevelInfo, slog.LevelError
+	switch opts.Verbosity {
+	case Verbose:
+		fileLevel, consoleLevel = slog.LevelDebug, slog.LevelDebug
+	case Quiet:
+		fileLevel = slog.LevelWarn
+	}
+
+	var handlers []slog.Handler
+	var closer io.Closer = nopCloser{}
+
+	if opts.Path != "" {
+		file, err := OpenRotating(opts.Path, opts.MaxSize, opts.MaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		handlers = append(handlers, newHandler(file, opts.Format, fileLevel))
+		closer = file
+	}
+	if opts.Verbosity != Quiet {
+		handlers = append(handlers, newHandler(opts.Stderr, opts.Format, consoleLevel))
+	}
+
+	logger := slog.New(fanout(handlers))
+	slog.SetDefault(logger)
+	return logger, closer, nil
+}
+
+// newHandler creates a text or JSON handler that redacts secrets
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// fanout sends each record to every handler that accepts its level
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, record.Level) {
+			errs = append(errs, h.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	handlers := make(fanout, len(f))
+	for i, h := range f {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// Redacted replaces secret values in log records
+const Redacted = "[REDACTED]"
+
+// secretKeys are attribute names whose values are never logged
+var secretKeys = []string{"api_key", "apikey", "x-api-key", "authorization", "token", "secret", "password"}
+
+// apiKeyPattern matches Anthropic API keys embedded in text such as error messages
+var apiKeyPattern = regexp.MustCompile(`sk-ant-[A-Za-z0-9_\-]+`)
+
+// RedactString masks API keys found in s
+func RedactString(s string) string {
+	return apiKeyPattern.ReplaceAllString(s, Redacted)
+}
+
+// redactAttr hides the values of secret attributes and API keys inside strings
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if key == secret || strings.HasSuffix(key, "_"+secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s := a.Value.String(); apiKeyPattern.MatchString(s) {
+			return slog.String(a.Key, RedactString(s))
+		}
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
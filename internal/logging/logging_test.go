@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetupLevels(t *testing.T) {
+	tests := []struct {
+		name        string
+		verbosity   Verbosity
+		wantFile    []string
+		wantConsole []string
+	}{
+		{"normal", Normal, []string{"info", "warn"}, nil},
+		{"verbose", Verbose, []string{"debug", "info", "warn"}, []string{"debug", "info", "warn"}},
+		{"quiet", Quiet, []string{"warn"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "greekmaster.log")
+			var console bytes.Buffer
+
+			logger, closer, err := Setup(Options{Path: path, Verbosity: tt.verbosity, Stderr: &console})
+			if err != nil {
+				t.Fatalf("Setup() error = %v", err)
+			}
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			closer.Close()
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkMessages(t, "file", string(data), tt.wantFile)
+			checkMessages(t, "console", console.String(), tt.wantConsole)
+		})
+	}
+}
+
+// checkMessages verifies that exactly the wanted messages were logged
+func checkMessages(t *testing.T, name, output string, want []string) {
+	t.Helper()
+	for _, msg := range []string{"debug", "info", "warn"} {
+		logged := strings.Contains(output, "msg="+msg)
+		wanted := strings.Contains(strings.Join(want, ","), msg)
+		if logged != wanted {
+			t.Errorf("%s logged %s = %v, want %v:\n%s", name, msg, logged, wanted, output)
+		}
+	}
+}
+
+func TestSetupJSON(t *testing.T) {
+	var console bytes.Buffer
+	logger, _, err := Setup(Options{Format: FormatJSON, Stderr: &console})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	logger.Error("request failed", "attempt", 2)
+
+	var record map[string]any
+	if err := json.Unmarshal(console.Bytes(), &record); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, console.String())
+	}
+	if record["msg"] != "request failed" || record["attempt"] != float64(2) {
+		t.Errorf("record = %v", record)
+	}
+
+	if _, _, err := Setup(Options{Format: "xml"}); err == nil {
+		t.Error("Setup() should reject unknown formats")
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	var console bytes.Buffer
+	logger, _, err := Setup(Options{Verbosity: Verbose, Stderr: &console})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	key := "sk-ant-REDACTED"
+	logger.Debug("AI request",
+		"api_key", "anything",
+		"x-api-key", key,
+		"prompt", "please ignore "+key,
+		"error", errors.New("401 for key "+key),
+	)
+
+	output := console.String()
+	if strings.Contains(output, "sk-ant") || strings.Contains(output, "anything") {
+		t.Errorf("secret leaked into log:\n%s", output)
+	}
+	if strings.Count(output, Redacted) != 4 {
+		t.Errorf("want 4 redactions:\n%s", output)
+	}
+	if !strings.Contains(output, "please ignore") {
+		t.Errorf("redaction should keep the rest of the text:\n%s", output)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "greekmaster.log")
+
+	file, err := OpenRotating(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotating() error = %v", err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	file.Close()
+
+	want := map[string]string{
+		path:        "fourth\n",
+		path + ".1": "third\n",
+		path + ".2": "second\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", filepath.Base(name), err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("only 2 backups should be kept")
+	}
+
+	// Reopening appends to the existing file
+	file, err = OpenRotating(path, 100, 2)
+	if err != nil {
+		t.Fatalf("OpenRotating() error = %v", err)
+	}
+	file.Write([]byte("fifth\n"))
+	file.Close()
+	if data, _ := os.ReadFile(path); string(data) != "fourth\nfifth\n" {
+		t.Errorf("reopened log = %q, want appended lines", data)
+	}
+}
+
+func TestSetupDefault(t *testing.T) {
+	var console bytes.Buffer
+	if _, _, err := Setup(Options{Stderr: &console}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	slog.Error("through the default logger")
+	if !strings.Contains(console.String(), "through the default logger") {
+		t.Error("Setup() should install the default logger")
+	}
+}
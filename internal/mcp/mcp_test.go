@@ -0,0 +1,349 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// setupServer creates a server over a database with two nouns and one template
+func setupServer(t *testing.T) *Server {
+	t.Helper()
+
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	nouns := []*models.Noun{
+		{
+			English: "teacher", Gender: "masculine",
+			NominativeSg: "δάσκαλος", GenitiveSg: "δασκάλου", AccusativeSg: "δάσκαλο",
+			NominativePl: "δάσκαλοι", GenitivePl: "δασκάλων", AccusativePl: "δασκάλους",
+			NomSgArticle: "ο", GenSgArticle: "του", AccSgArticle: "τον",
+			NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τους",
+		},
+		{
+			English: "book", Gender: "neuter",
+			NominativeSg: "βιβλίο", GenitiveSg: "βιβλίου", AccusativeSg: "βιβλίο",
+			NominativePl: "βιβλία", GenitivePl: "βιβλίων", AccusativePl: "βιβλία",
+			NomSgArticle: "το", GenSgArticle: "του", AccSgArticle: "το",
+			NomPlArticle: "τα", GenPlArticle: "των", AccPlArticle: "τα",
+		},
+	}
+	for _, noun := range nouns {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I see ___ (the {noun})",
+		GreekTemplate:   "Βλέπω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	return New(repo)
+}
+
+// reply is a decoded JSON-RPC response
+type reply struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// script plays a client session: each message is sent as one line and the
+// responses are returned in order
+func script(t *testing.T, s *Server, messages ...string) []reply {
+	t.Helper()
+
+	var out bytes.Buffer
+	if err := s.Serve(strings.NewReader(strings.Join(messages, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var replies []reply
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r reply
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid response line %q: %v", line, err)
+		}
+		replies = append(replies, r)
+	}
+	return replies
+}
+
+// callTool sends a tools/call request and returns the text result and error flag
+func callTool(t *testing.T, s *Server, name string, args any) (string, bool) {
+	t.Helper()
+
+	params, err := json.Marshal(map[string]any{"name": name, "arguments": args})
+	if err != nil {
+		t.Fatalf("marshal arguments: %v", err)
+	}
+	replies := script(t, s, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+string(params)+`}`)
+	if len(replies) != 1 {
+		t.Fatalf("got %d responses, want 1", len(replies))
+	}
+	if replies[0].Error != nil {
+		t.Fatalf("tools/call %s error = %v", name, replies[0].Error.Message)
+	}
+
+	var result toolResult
+	if err := json.Unmarshal(replies[0].Result, &result); err != nil {
+		t.Fatalf("invalid tool result: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Type != "text" {
+		t.Fatalf("tool result content = %+v, want one text block", result.Content)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func TestHandshake(t *testing.T) {
+	s := setupServer(t)
+
+	replies := script(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":"two","method":"ping"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+	)
+
+	// The notification gets no response
+	if len(replies) != 3 {
+		t.Fatalf("got %d responses, want 3", len(replies))
+	}
+
+	var init initializeResult
+	if err := json.Unmarshal(replies[0].Result, &init); err != nil {
+		t.Fatalf("invalid initialize result: %v", err)
+	}
+	if init.ProtocolVersion != "2025-03-26" {
+		t.Errorf("protocolVersion = %s, want the client's 2025-03-26", init.ProtocolVersion)
+	}
+	if init.ServerInfo.Name != ServerName {
+		t.Errorf("serverInfo.name = %s, want %s", init.ServerInfo.Name, ServerName)
+	}
+	if _, ok := init.Capabilities["tools"]; !ok {
+		t.Error("capabilities should declare tools")
+	}
+
+	if string(replies[1].ID) != `"two"` || replies[1].Error != nil {
+		t.Errorf("ping reply = %+v, want a result echoing id \"two\"", replies[1])
+	}
+
+	var list struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(replies[2].Result, &list); err != nil {
+		t.Fatalf("invalid tools/list result: %v", err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		if tool.InputSchema["type"] != "object" {
+			t.Errorf("%s inputSchema type = %v, want object", tool.Name, tool.InputSchema["type"])
+		}
+	}
+	want := "lookup_noun,get_paradigm,generate_exercise,grade_answer,explain"
+	if strings.Join(names, ",") != want {
+		t.Errorf("tools = %v, want %s", names, want)
+	}
+}
+
+func TestUnsupportedProtocolVersion(t *testing.T) {
+	s := setupServer(t)
+
+	replies := script(t, s, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`)
+
+	var init initializeResult
+	if err := json.Unmarshal(replies[0].Result, &init); err != nil {
+		t.Fatalf("invalid initialize result: %v", err)
+	}
+	if latest := protocolVersions[len(protocolVersions)-1]; init.ProtocolVersion != latest {
+		t.Errorf("protocolVersion = %s, want latest %s", init.ProtocolVersion, latest)
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	s := setupServer(t)
+
+	replies := script(t, s,
+		`not json`,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"conjugate_verb","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":"lookup_noun"}`,
+		`{"id":4,"method":"ping"}`,
+	)
+
+	want := []int{codeParseError, codeMethodNotFound, codeInvalidParams, codeInvalidParams, codeInvalidRequest}
+	if len(replies) != len(want) {
+		t.Fatalf("got %d responses, want %d", len(replies), len(want))
+	}
+	for i, code := range want {
+		if replies[i].Error == nil || replies[i].Error.Code != code {
+			t.Errorf("response %d error = %+v, want code %d", i, replies[i].Error, code)
+		}
+	}
+	if string(replies[0].ID) != "null" {
+		t.Errorf("parse error id = %s, want null", replies[0].ID)
+	}
+}
+
+func TestLookupNoun(t *testing.T) {
+	s := setupServer(t)
+
+	text, isError := callTool(t, s, "lookup_noun", map[string]any{"query": "δασκαλος"})
+	if isError {
+		t.Fatalf("lookup_noun failed: %s", text)
+	}
+	var nouns []*models.Noun
+	if err := json.Unmarshal([]byte(text), &nouns); err != nil {
+		t.Fatalf("lookup_noun result is not a noun list: %v", err)
+	}
+	if len(nouns) != 1 || nouns[0].English != "teacher" || nouns[0].AccSgArticle != "τον" {
+		t.Errorf("lookup_noun = %+v, want teacher with its forms", nouns)
+	}
+
+	text, _ = callTool(t, s, "lookup_noun", map[string]any{"query": "book", "gender": "masculine"})
+	if !strings.Contains(text, "No nouns match") {
+		t.Errorf("gender filter result = %q, want no matches", text)
+	}
+}
+
+func TestGetParadigm(t *testing.T) {
+	s := setupServer(t)
+
+	text, isError := callTool(t, s, "get_paradigm", map[string]any{"query": "teacher"})
+	if isError {
+		t.Fatalf("get_paradigm failed: %s", text)
+	}
+	for _, want := range []string{"ο δάσκαλος (teacher, masculine)", "του δασκάλου", "τους δασκάλους"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("paradigm missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "[") {
+		t.Errorf("paradigm should not highlight a form:\n%s", text)
+	}
+
+	if text, isError = callTool(t, s, "get_paradigm", map[string]any{"noun_id": 99}); !isError {
+		t.Errorf("unknown noun_id should be a tool error, got %q", text)
+	}
+	if text, isError = callTool(t, s, "get_paradigm", map[string]any{}); !isError || !strings.Contains(text, "required") {
+		t.Errorf("missing arguments result = %q, %v, want a tool error", text, isError)
+	}
+}
+
+func TestExerciseGradeAndExplain(t *testing.T) {
+	s := setupServer(t)
+
+	text, isError := callTool(t, s, "generate_exercise", map[string]any{"count": 1, "gender": []string{"masculine"}})
+	if isError {
+		t.Fatalf("generate_exercise failed: %s", text)
+	}
+	var sentences []*models.Sentence
+	if err := json.Unmarshal([]byte(text), &sentences); err != nil {
+		t.Fatalf("generate_exercise result is not a sentence list: %v", err)
+	}
+	if len(sentences) != 1 || sentences[0].CorrectAnswer != "τον δάσκαλο" {
+		t.Fatalf("generate_exercise = %+v, want one sentence answered by τον δάσκαλο", sentences)
+	}
+	sentence := sentences[0]
+
+	// The generated sentence is passed back as the client received it
+	text, isError = callTool(t, s, "grade_answer", map[string]any{"sentence": sentence, "answer": "  τον   δάσκαλο "})
+	if isError {
+		t.Fatalf("grade_answer failed: %s", text)
+	}
+	var grade gradeResult
+	if err := json.Unmarshal([]byte(text), &grade); err != nil {
+		t.Fatalf("grade_answer result is invalid: %v", err)
+	}
+	if !grade.Correct || grade.Score != 1 {
+		t.Errorf("grade_answer = %+v, want correct with full score", grade)
+	}
+
+	text, _ = callTool(t, s, "grade_answer", map[string]any{"sentence": sentence, "answer": "ο δάσκαλος", "hints_used": 1})
+	if err := json.Unmarshal([]byte(text), &grade); err != nil {
+		t.Fatalf("grade_answer result is invalid: %v", err)
+	}
+	if grade.Correct || grade.Score != 0 {
+		t.Errorf("grade_answer = %+v, want incorrect with no score", grade)
+	}
+
+	// The answer is rebuilt from the database, so a rewritten sentence can't change the grade
+	forged := *sentence
+	forged.CorrectAnswer = "ο δάσκαλος"
+	text, _ = callTool(t, s, "grade_answer", map[string]any{"sentence": forged, "answer": "ο δάσκαλος"})
+	if err := json.Unmarshal([]byte(text), &grade); err != nil {
+		t.Fatalf("grade_answer result is invalid: %v", err)
+	}
+	if grade.Correct || grade.CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("grade_answer with a forged answer = %+v, want it graded against τον δάσκαλο", grade)
+	}
+
+	text, isError = callTool(t, s, "explain", map[string]any{"sentence": forged})
+	if isError {
+		t.Fatalf("explain failed: %s", text)
+	}
+	for _, want := range []string{"Syntactic role:", "Morphology:", "[τον δάσκαλο]"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("explanation missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestToolArgumentErrors(t *testing.T) {
+	s := setupServer(t)
+
+	tests := []struct {
+		name string
+		tool string
+		args any
+		want string
+	}{
+		{"bad difficulty", "generate_exercise", map[string]any{"difficulty": "expert"}, "invalid difficulty"},
+		{"count too large", "generate_exercise", map[string]any{"count": 500}, "invalid count"},
+		{"bad case", "generate_exercise", map[string]any{"case": []string{"dative"}}, "invalid case"},
+		{"no matching templates", "generate_exercise", map[string]any{"case": []string{"genitive"}}, "no templates"},
+		{"wrong argument type", "generate_exercise", map[string]any{"count": "five"}, "invalid arguments"},
+		{"missing sentence", "grade_answer", map[string]any{"answer": "τον δάσκαλο"}, "sentence"},
+		{"missing sentence", "explain", nil, "sentence"},
+		{"unknown noun", "grade_answer", map[string]any{"sentence": map[string]any{"noun_id": 99, "case_type": "accusative", "number": "singular"}}, "not found"},
+		{"invalid case", "explain", map[string]any{"sentence": map[string]any{"noun_id": 1, "case_type": "dative", "number": "singular"}}, "invalid sentence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool+" "+tt.name, func(t *testing.T) {
+			text, isError := callTool(t, s, tt.tool, tt.args)
+			if !isError {
+				t.Fatalf("expected a tool error, got %q", text)
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("error = %q, want it to mention %q", text, tt.want)
+			}
+		})
+	}
+}
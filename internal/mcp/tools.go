@@ -0,0 +1,439 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// defaultExerciseCount and maxExerciseCount bound the count argument of generate_exercise
+const (
+	defaultExerciseCount = 5
+	maxExerciseCount     = 50
+)
+
+// difficultyLevels lists the accepted difficulty argument values
+var difficultyLevels = []string{"beginner", "intermediate", "advanced", "all"}
+
+// tool is a callable tool. The handler returns the text shown to the model, or an
+// error that is reported as a failed tool result.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	handler     func(args json.RawMessage) (string, error)
+}
+
+// content is a block of tool output
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// toolResult is the result of tools/call
+type toolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+// callParams is the body of a tools/call request
+type callParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// schema builds a JSON Schema object with the given properties
+func schema(properties map[string]any, required ...string) map[string]any {
+	s := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// stringList describes an array-of-strings argument
+func stringList(description string, values ...string) map[string]any {
+	items := map[string]any{"type": "string"}
+	if len(values) > 0 {
+		items["enum"] = values
+	}
+	return map[string]any{"type": "array", "items": items, "description": description}
+}
+
+// sentenceSchema describes a sentence returned by generate_exercise
+var sentenceSchema = map[string]any{
+	"type":        "object",
+	"description": "A sentence exactly as returned by generate_exercise. Its answer is rebuilt from the stored noun and template, so only noun_id and template_id (or case_type and number) matter.",
+	"properties": map[string]any{
+		"noun_id":        map[string]any{"type": "integer"},
+		"template_id":    map[string]any{"type": "integer"},
+		"english_prompt": map[string]any{"type": "string"},
+		"greek_sentence": map[string]any{"type": "string"},
+		"correct_answer": map[string]any{"type": "string"},
+		"case_type":      map[string]any{"type": "string"},
+		"number":         map[string]any{"type": "string"},
+		"context_type":   map[string]any{"type": "string"},
+		"preposition":    map[string]any{"type": "string"},
+	},
+	"required": []string{"noun_id"},
+}
+
+// registerTools lists the server's tools
+func (s *Server) registerTools() []tool {
+	return []tool{
+		{
+			Name:        "lookup_noun",
+			Description: "Search the noun catalog by English meaning or Greek nominative singular (accents optional). Returns the matching nouns with all six article and noun forms.",
+			InputSchema: schema(map[string]any{
+				"query":  map[string]any{"type": "string", "description": "Text to search for, e.g. \"teacher\" or \"δασκαλος\""},
+				"gender": map[string]any{"type": "string", "enum": models.FilterValues("gender"), "description": "Only return nouns of this gender"},
+			}, "query"),
+			handler: s.lookupNoun,
+		},
+		{
+			Name:        "get_paradigm",
+			Description: "Show the full declension table (nominative, genitive and accusative, singular and plural, with articles) of a noun.",
+			InputSchema: schema(map[string]any{
+				"noun_id": map[string]any{"type": "integer", "description": "ID of the noun, as returned by lookup_noun"},
+				"query":   map[string]any{"type": "string", "description": "English meaning or Greek nominative singular, used when noun_id is not given"},
+			}),
+			handler: s.getParadigm,
+		},
+		{
+			Name:        "generate_exercise",
+			Description: "Generate fill-in-the-blank practice sentences. Each sentence has an English prompt, a Greek sentence with a blank (___) and the article and noun form that fills it. Pass a sentence to grade_answer or explain.",
+			InputSchema: schema(map[string]any{
+				"difficulty":  map[string]any{"type": "string", "enum": difficultyLevels, "description": "Difficulty level (default: all)"},
+				"plural":      map[string]any{"type": "boolean", "description": "Include plural forms"},
+				"count":       map[string]any{"type": "integer", "minimum": 1, "maximum": maxExerciseCount, "description": fmt.Sprintf("Number of sentences (default: %d)", defaultExerciseCount)},
+				"gender":      stringList("Only use nouns of these genders", models.FilterValues("gender")...),
+				"case":        stringList("Only test these cases", models.FilterValues("case")...),
+				"preposition": stringList("Only use templates with these prepositions (\"none\" for no preposition)"),
+				"context":     stringList("Only use these context types", models.FilterValues("context")...),
+				"tag":         stringList("Only use nouns with these tags"),
+				"nouns":       map[string]any{"type": "string", "description": "Noun ID ranges, e.g. \"1-50,80\""},
+			}),
+			handler: s.generateExercise,
+		},
+		{
+			Name:        "grade_answer",
+			Description: "Grade a learner's answer (article and noun form) to a sentence from generate_exercise against the form stored for its noun. Accents must match exactly; extra whitespace is ignored.",
+			InputSchema: schema(map[string]any{
+				"sentence":   sentenceSchema,
+				"answer":     map[string]any{"type": "string", "description": "The learner's answer, e.g. \"τον δάσκαλο\""},
+				"hints_used": map[string]any{"type": "integer", "minimum": 0, "description": "Hints revealed before answering, each lowering the score"},
+			}, "sentence", "answer"),
+			handler: s.gradeAnswer,
+		},
+		{
+			Name:        "explain",
+			Description: "Explain why a sentence from generate_exercise takes its case and form: translation, syntactic role, morphology, the ending pattern and the noun's declension table.",
+			InputSchema: schema(map[string]any{
+				"sentence": sentenceSchema,
+			}, "sentence"),
+			handler: s.explain,
+		},
+	}
+}
+
+// listTools returns the tools/list result
+func (s *Server) listTools() any {
+	return map[string]any{"tools": s.tools}
+}
+
+// callTool runs a tool. Unknown tools and malformed params are protocol errors;
+// failures inside the tool are reported in the result so the model can see them.
+func (s *Server) callTool(params json.RawMessage) (any, error) {
+	var p callParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(s.tools, func(t tool) bool { return t.Name == p.Name })
+	if i < 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+	}
+
+	args := p.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	slog.Debug("MCP tool call", "tool", p.Name, "arguments", string(args))
+	text, err := s.tools[i].handler(args)
+	if err != nil {
+		slog.Info("MCP tool failed", "tool", p.Name, "error", err)
+		return toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toolResult{Content: []content{{Type: "text", Text: text}}}, nil
+}
+
+// decodeArgs unmarshals tool arguments
+func decodeArgs(args json.RawMessage, v any) error {
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// toJSON renders a tool result as indented JSON
+func toJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+type lookupArgs struct {
+	Query  string `json:"query"`
+	Gender string `json:"gender"`
+}
+
+func (s *Server) lookupNoun(args json.RawMessage) (string, error) {
+	var a lookupArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+
+	nouns, err := s.repo.SearchNouns(a.Query)
+	if err != nil {
+		return "", err
+	}
+
+	matches := make([]*models.Noun, 0, len(nouns))
+	for _, noun := range nouns {
+		if a.Gender == "" || noun.Gender == a.Gender {
+			matches = append(matches, noun)
+		}
+	}
+	if len(matches) == 0 {
+		return fmt.Sprintf("No nouns match '%s'", a.Query), nil
+	}
+
+	return toJSON(matches)
+}
+
+type paradigmArgs struct {
+	NounID int64  `json:"noun_id"`
+	Query  string `json:"query"`
+}
+
+func (s *Server) getParadigm(args json.RawMessage) (string, error) {
+	var a paradigmArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+
+	var noun *models.Noun
+	var err error
+	switch {
+	case a.NounID != 0:
+		noun, err = s.repo.GetNoun(a.NounID)
+	case strings.TrimSpace(a.Query) != "":
+		noun, err = s.findNoun(a.Query)
+	default:
+		return "", errors.New("noun_id or query is required")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s (%s, %s)\n\n%s",
+		noun.NomSgArticle, noun.NominativeSg, noun.English, noun.Gender,
+		explanations.FormatParadigm(noun, "", "")), nil
+}
+
+// findNoun resolves a search term to one noun, preferring an exact match
+func (s *Server) findNoun(query string) (*models.Noun, error) {
+	nouns, err := s.repo.SearchNouns(query)
+	if err != nil {
+		return nil, err
+	}
+
+	key := grammar.StripAccents(strings.ToLower(strings.TrimSpace(query)))
+	for _, noun := range nouns {
+		if strings.EqualFold(noun.English, strings.TrimSpace(query)) ||
+			grammar.StripAccents(strings.ToLower(noun.NominativeSg)) == key {
+			return noun, nil
+		}
+	}
+
+	switch len(nouns) {
+	case 0:
+		return nil, fmt.Errorf("no nouns match '%s'", query)
+	case 1:
+		return nouns[0], nil
+	}
+
+	candidates := make([]string, 0, len(nouns))
+	for _, noun := range nouns {
+		candidates = append(candidates, fmt.Sprintf("%d %s (%s)", noun.ID, noun.NominativeSg, noun.English))
+	}
+	return nil, fmt.Errorf("'%s' matches %d nouns, pass noun_id to choose one: %s",
+		query, len(nouns), strings.Join(candidates, ", "))
+}
+
+type exerciseArgs struct {
+	Difficulty   string   `json:"difficulty"`
+	Plural       bool     `json:"plural"`
+	Count        int      `json:"count"`
+	Genders      []string `json:"gender"`
+	Cases        []string `json:"case"`
+	Prepositions []string `json:"preposition"`
+	ContextTypes []string `json:"context"`
+	Tags         []string `json:"tag"`
+	Nouns        string   `json:"nouns"`
+}
+
+// sessionConfig validates the arguments and converts them to a session configuration
+func (a exerciseArgs) sessionConfig() (models.SessionConfig, error) {
+	config := models.SessionConfig{
+		QuestionCount: defaultExerciseCount,
+		IncludePlural: a.Plural,
+		Mode:          "sentence",
+	}
+
+	if a.Difficulty != "" {
+		if !slices.Contains(difficultyLevels, a.Difficulty) {
+			return config, fmt.Errorf("invalid difficulty '%s', must be one of: %s", a.Difficulty, strings.Join(difficultyLevels, ", "))
+		}
+		if a.Difficulty != "all" {
+			config.DifficultyLevel = a.Difficulty
+		}
+	}
+
+	if a.Count != 0 {
+		if a.Count < 1 || a.Count > maxExerciseCount {
+			return config, fmt.Errorf("invalid count %d, must be between 1 and %d", a.Count, maxExerciseCount)
+		}
+		config.QuestionCount = a.Count
+	}
+
+	filter := &config.Filter
+	filter.Genders = a.Genders
+	filter.Cases = a.Cases
+	filter.Prepositions = a.Prepositions
+	filter.ContextTypes = a.ContextTypes
+	filter.Tags = models.NormalizeTags(a.Tags)
+	if a.Nouns != "" {
+		ranges, err := models.ParseIDRanges(a.Nouns)
+		if err != nil {
+			return config, fmt.Errorf("invalid nouns: %w", err)
+		}
+		filter.NounRanges = ranges
+	}
+
+	return config, filter.Validate()
+}
+
+func (s *Server) generateExercise(args json.RawMessage) (string, error) {
+	var a exerciseArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	config, err := a.sessionConfig()
+	if err != nil {
+		return "", err
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sentences, err := storage.GenerateSessionSentences(s.repo, config, rng)
+	if err != nil {
+		return "", err
+	}
+
+	return toJSON(sentences)
+}
+
+// sentenceArgs holds a sentence passed back from generate_exercise
+type sentenceArgs struct {
+	Sentence *models.Sentence `json:"sentence"`
+}
+
+// expected rebuilds the sentence from the database, so that grading and
+// explanations never depend on what the model passes back
+func (a sentenceArgs) expected(repo storage.Repository) (*models.Sentence, *models.Noun, error) {
+	if a.Sentence == nil {
+		return nil, nil, errors.New("sentence is required")
+	}
+	return storage.ExpectedSentence(repo, a.Sentence)
+}
+
+type gradeArgs struct {
+	sentenceArgs
+	Answer    string `json:"answer"`
+	HintsUsed int    `json:"hints_used"`
+}
+
+// gradeResult is the output of grade_answer
+type gradeResult struct {
+	Correct         bool     `json:"correct"`
+	CorrectAnswer   string   `json:"correct_answer"`
+	AcceptedAnswers []string `json:"accepted_answers"`
+	Score           float64  `json:"score"`
+}
+
+func (s *Server) gradeAnswer(args json.RawMessage) (string, error) {
+	var a gradeArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if a.HintsUsed < 0 {
+		return "", errors.New("hints_used cannot be negative")
+	}
+	sentence, _, err := a.expected(s.repo)
+	if err != nil {
+		return "", err
+	}
+
+	correct := grading.Grade(a.Answer, sentence)
+	return toJSON(gradeResult{
+		Correct:         correct,
+		CorrectAnswer:   sentence.CorrectAnswer,
+		AcceptedAnswers: grading.AcceptedAnswers(sentence),
+		Score:           grading.Score(correct, a.HintsUsed),
+	})
+}
+
+func (s *Server) explain(args json.RawMessage) (string, error) {
+	var a sentenceArgs
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	sentence, noun, err := a.expected(s.repo)
+	if err != nil {
+		return "", err
+	}
+	explanation, err := explanations.Generate(sentence, noun)
+	if err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Translation: %s\n", explanation.Translation)
+	fmt.Fprintf(&text, "Syntactic role: %s\n", explanation.SyntacticRole)
+	fmt.Fprintf(&text, "Morphology: %s\n", explanation.Morphology)
+	if explanation.Usage != "" {
+		fmt.Fprintf(&text, "Usage: %s\n", explanation.Usage)
+	}
+	if explanation.Pattern != "" {
+		fmt.Fprintf(&text, "Pattern: %s\n", explanation.Pattern)
+	}
+	if explanation.Paradigm != "" {
+		fmt.Fprintf(&text, "\n%s\n", explanation.Paradigm)
+	}
+
+	return strings.TrimRight(text.String(), "\n"), nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Attempt records one answered practice item
+type Attempt struct {
+	ID            int64     `db:"id"`
+	ProfileID     int64     `db:"profile_id"`
+	NounID        int64     `db:"noun_id"`
+	TemplateID    *int64    `db:"template_id"`
+	Mode          string    `db:"mode"`
+	CaseType      string    `db:"case_type"`
+	Number        string    `db:"number"`
+	ContextType   string    `db:"context_type"`
+	UserAnswer    string    `db:"user_answer"`
+	CorrectAnswer string    `db:"correct_answer"`
+	Correct       bool      `db:"correct"`
+	HintsUsed     int       `db:"hints_used"`
+	Score         float64   `db:"score"`      // 1 for a correct unaided answer, reduced by hints
+	LatencyMs     int64     `db:"latency_ms"` // Time from showing the question to the answer
+	Slow          bool      `db:"slow"`       // Correct, but slow enough in a timed session to review again
+	CreatedAt     time.Time `db:"created_at"`
+}
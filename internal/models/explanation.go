@@ -2,9 +2,12 @@ package models
 
 // Explanation represents grammar analysis for a sentence
 type Explanation struct {
-	ID            int64  `db:"id"`
-	SentenceID    int64  `db:"sentence_id"`
-	Translation   string `db:"translation"`
-	SyntacticRole string `db:"syntactic_role"`
-	Morphology    string `db:"morphology"`
+	ID            int64  `db:"id" json:"-"`
+	SentenceID    int64  `db:"sentence_id" json:"-"`
+	Translation   string `db:"translation" json:"translation"`
+	SyntacticRole string `db:"syntactic_role" json:"syntactic_role"`
+	Morphology    string `db:"morphology" json:"morphology"`
+	Usage         string `db:"-" json:"usage,omitempty"`
+	Pattern       string `db:"-" json:"pattern,omitempty"`
+	Paradigm      string `db:"-" json:"paradigm,omitempty"`
 }
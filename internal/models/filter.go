@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IDRange is an inclusive range of database IDs
+type IDRange struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// Contains reports whether id lies within the range
+func (r IDRange) Contains(id int64) bool {
+	return id >= r.Min && id <= r.Max
+}
+
+// ParseIDRanges parses a comma-separated list of IDs and ranges such as "1-50,60,70-80"
+func ParseIDRanges(s string) ([]IDRange, error) {
+	var ranges []IDRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id '%s' in range '%s'", first, part)
+		}
+		end := start
+		if isRange {
+			end, err = strconv.ParseInt(strings.TrimSpace(last), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id '%s' in range '%s'", last, part)
+			}
+		}
+		if start > end {
+			return nil, fmt.Errorf("invalid range '%s': start is after end", part)
+		}
+
+		ranges = append(ranges, IDRange{Min: start, Max: end})
+	}
+	return ranges, nil
+}
+
+// SessionFilter narrows the nouns and templates a practice session draws from.
+// Empty fields don't filter; within a field any listed value matches.
+type SessionFilter struct {
+	Genders      []string  `json:"genders,omitempty"`
+	Cases        []string  `json:"cases,omitempty"`
+	Prepositions []string  `json:"prepositions,omitempty"`
+	ContextTypes []string  `json:"context_types,omitempty"`
+	NounRanges   []IDRange `json:"noun_ranges,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+}
+
+// validFilterValues lists the accepted values of the enumerated filter fields
+var validFilterValues = map[string][]string{
+	"gender":  {"masculine", "feminine", "neuter", "invariable"},
+	"case":    {"nominative", "genitive", "accusative"},
+	"context": {"direct_object", "indirect_object", "possession", "preposition", "time"},
+}
+
+// FilterValues returns the accepted values of an enumerated filter field
+// ("gender", "case" or "context")
+func FilterValues(field string) []string {
+	return slices.Clone(validFilterValues[field])
+}
+
+// IsEmpty reports whether the filter lets everything through
+func (f SessionFilter) IsEmpty() bool {
+	return len(f.Genders) == 0 && len(f.Cases) == 0 && len(f.Prepositions) == 0 &&
+		len(f.ContextTypes) == 0 && len(f.NounRanges) == 0 && len(f.Tags) == 0
+}
+
+// Validate checks the enumerated filter fields for unknown values
+func (f SessionFilter) Validate() error {
+	fields := map[string][]string{
+		"gender":  f.Genders,
+		"case":    f.Cases,
+		"context": f.ContextTypes,
+	}
+	for _, name := range []string{"gender", "case", "context"} {
+		for _, value := range fields[name] {
+			if !slices.Contains(validFilterValues[name], value) {
+				return fmt.Errorf("invalid %s '%s', must be one of: %s", name, value, strings.Join(validFilterValues[name], ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// MatchNoun reports whether a noun with the given semantic tags passes the filter
+func (f SessionFilter) MatchNoun(noun *Noun, tags []string) bool {
+	if len(f.Genders) > 0 && !slices.Contains(f.Genders, noun.Gender) {
+		return false
+	}
+
+	if len(f.NounRanges) > 0 && !slices.ContainsFunc(f.NounRanges, func(r IDRange) bool {
+		return r.Contains(noun.ID)
+	}) {
+		return false
+	}
+
+	if len(f.Tags) > 0 && !slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.Contains(f.Tags, tag)
+	}) {
+		return false
+	}
+
+	return true
+}
+
+// MatchTemplate reports whether a template passes the filter
+func (f SessionFilter) MatchTemplate(template *SentenceTemplate) bool {
+	if len(f.Cases) > 0 && !slices.Contains(f.Cases, template.CaseType) {
+		return false
+	}
+
+	if len(f.ContextTypes) > 0 && !slices.Contains(f.ContextTypes, template.ContextType) {
+		return false
+	}
+
+	if len(f.Prepositions) > 0 && (template.Preposition == nil || !slices.Contains(f.Prepositions, *template.Preposition)) {
+		return false
+	}
+
+	return true
+}
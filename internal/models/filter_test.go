@@ -0,0 +1,85 @@
+package models
+
+import "testing"
+
+func TestParseIDRanges(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []IDRange
+		wantErr bool
+	}{
+		{"1-50", []IDRange{{1, 50}}, false},
+		{"1-50, 60,70-80", []IDRange{{1, 50}, {60, 60}, {70, 80}}, false},
+		{"", nil, false},
+		{"50-1", nil, true},
+		{"a-5", nil, true},
+		{"1-", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseIDRanges(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseIDRanges(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseIDRanges(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseIDRanges(%q)[%d] = %v, want %v", tt.input, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSessionFilter(t *testing.T) {
+	se := "σε"
+	filter := SessionFilter{
+		Genders:      []string{"feminine"},
+		Cases:        []string{"accusative"},
+		Prepositions: []string{"σε"},
+		NounRanges:   []IDRange{{1, 50}},
+		Tags:         []string{"place", "food"},
+	}
+
+	nouns := []struct {
+		name string
+		noun *Noun
+		tags []string
+		want bool
+	}{
+		{"matching", &Noun{ID: 10, Gender: "feminine"}, []string{"place", "count"}, true},
+		{"wrong gender", &Noun{ID: 10, Gender: "masculine"}, []string{"place"}, false},
+		{"outside range", &Noun{ID: 51, Gender: "feminine"}, []string{"place"}, false},
+		{"no matching tag", &Noun{ID: 10, Gender: "feminine"}, []string{"person"}, false},
+	}
+	for _, tt := range nouns {
+		if got := filter.MatchNoun(tt.noun, tt.tags); got != tt.want {
+			t.Errorf("MatchNoun(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	templates := []struct {
+		name     string
+		template *SentenceTemplate
+		want     bool
+	}{
+		{"matching", &SentenceTemplate{CaseType: "accusative", Preposition: &se}, true},
+		{"wrong case", &SentenceTemplate{CaseType: "genitive", Preposition: &se}, false},
+		{"no preposition", &SentenceTemplate{CaseType: "accusative"}, false},
+	}
+	for _, tt := range templates {
+		if got := filter.MatchTemplate(tt.template); got != tt.want {
+			t.Errorf("MatchTemplate(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !(SessionFilter{}).IsEmpty() || filter.IsEmpty() {
+		t.Error("IsEmpty() misreports filter emptiness")
+	}
+	if err := (SessionFilter{Cases: []string{"dative"}}).Validate(); err == nil {
+		t.Error("Validate() accepted an unknown case")
+	}
+}
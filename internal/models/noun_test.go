@@ -108,3 +108,66 @@ func TestSessionConfigStruct(t *testing.T) {
 		t.Errorf("Expected QuestionCount 0 for endless mode, got %d", endlessConfig.QuestionCount)
 	}
 }
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Person", " count ", "person", "vehicle", ""})
+	want := []string{"person", "count"}
+
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeTags() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("NormalizeTags()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTagsConfirmed(t *testing.T) {
+	tests := []struct {
+		name     string
+		nounTags []string
+		required []string
+		want     bool
+	}{
+		{"no requirements", nil, nil, true},
+		{"untagged noun", nil, []string{"person", "animal"}, false},
+		{"class matches", []string{"animal", "count"}, []string{"person", "animal"}, true},
+		{"class mismatch", []string{"time", "count"}, []string{"person", "animal"}, false},
+		{"countability unknown", []string{"food"}, []string{"food", "count"}, false},
+		{"unconstrained group unknown", []string{"food"}, []string{"food"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TagsConfirmed(tt.nounTags, tt.required); got != tt.want {
+				t.Errorf("TagsConfirmed(%v, %v) = %v, want %v", tt.nounTags, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagsCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		nounTags []string
+		required []string
+		want     bool
+	}{
+		{"no requirements", []string{"time", "count"}, nil, true},
+		{"untagged noun", nil, []string{"person", "animal"}, true},
+		{"class matches one alternative", []string{"animal", "count"}, []string{"person", "animal"}, true},
+		{"class mismatch", []string{"time", "count"}, []string{"person", "animal"}, false},
+		{"countability mismatch", []string{"food", "mass"}, []string{"food", "count"}, false},
+		{"countability unknown", []string{"food"}, []string{"food", "count"}, true},
+		{"both groups match", []string{"food", "count"}, []string{"food", "count"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TagsCompatible(tt.nounTags, tt.required); got != tt.want {
+				t.Errorf("TagsCompatible(%v, %v) = %v, want %v", tt.nounTags, tt.required, got, tt.want)
+			}
+		})
+	}
+}
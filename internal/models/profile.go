@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Profile is a learner sharing the installation; progress is kept per profile
+type Profile struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Active    bool      `db:"active"` // Used when no profile is chosen on the command line
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -4,15 +4,17 @@ import "time"
 
 // Sentence represents a practice sentence
 type Sentence struct {
-	ID              int64     `db:"id"`
-	NounID          int64     `db:"noun_id"`
-	EnglishPrompt   string    `db:"english_prompt"`
-	GreekSentence   string    `db:"greek_sentence"`
-	CorrectAnswer   string    `db:"correct_answer"`
-	CaseType        string    `db:"case_type"`
-	Number          string    `db:"number"`
-	DifficultyPhase int       `db:"difficulty_phase"`
-	ContextType     string    `db:"context_type"`
-	Preposition     *string   `db:"preposition"`
-	CreatedAt       time.Time `db:"created_at"`
+	ID              int64     `db:"id" json:"id"`
+	NounID          int64     `db:"noun_id" json:"noun_id"`
+	EnglishPrompt   string    `db:"english_prompt" json:"english_prompt"`
+	GreekSentence   string    `db:"greek_sentence" json:"greek_sentence"`
+	CorrectAnswer   string    `db:"correct_answer" json:"correct_answer"`
+	CaseType        string    `db:"case_type" json:"case_type"`
+	Number          string    `db:"number" json:"number"`
+	DifficultyPhase int       `db:"difficulty_phase" json:"difficulty_phase"`
+	ContextType     string    `db:"context_type" json:"context_type"`
+	Preposition     *string   `db:"preposition" json:"preposition,omitempty"`
+	Contracted      bool      `db:"-" json:"contracted,omitempty"`  // CorrectAnswer fuses the preposition with the article (στον)
+	TemplateID      int64     `db:"-" json:"template_id,omitempty"` // Template the sentence was generated from, 0 for stored sentences
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
 }
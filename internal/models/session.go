@@ -1,8 +1,71 @@
 package models
 
+import "time"
+
 // SessionConfig holds practice session configuration
 type SessionConfig struct {
-	DifficultyLevel string // "beginner", "intermediate", "advanced"
-	IncludePlural   bool
-	QuestionCount   int // 0 for endless mode
+	DifficultyLevel string        `json:"difficulty_level,omitempty"` // "beginner", "intermediate", "advanced", or "" for all phases
+	IncludePlural   bool          `json:"include_plural"`
+	QuestionCount   int           `json:"question_count"` // 0 for endless mode
+	Mode            string        `json:"mode,omitempty"` // "sentence" (default), "choice", "reverse" or "table"
+	Filter          SessionFilter `json:"filter"`
+	TimeLimit       int           `json:"time_limit,omitempty"`     // Seconds per question, 0 for no countdown
+	SprintSeconds   int           `json:"sprint_seconds,omitempty"` // Length of a timed sprint, 0 for none
+}
+
+// difficultyPhases maps difficulty levels to template difficulty phases
+var difficultyPhases = map[string]int{
+	"beginner":     1,
+	"intermediate": 2,
+	"advanced":     3,
+}
+
+// Phase returns the template difficulty phase of the session, 0 for every phase
+func (c SessionConfig) Phase() int {
+	return difficultyPhases[c.DifficultyLevel]
+}
+
+// NumberFilter returns the grammatical number to practise, "" for both
+func (c SessionConfig) NumberFilter() string {
+	if c.IncludePlural {
+		return ""
+	}
+	return "singular"
+}
+
+// IsTimed reports whether the session runs a question countdown or a sprint clock
+func (c SessionConfig) IsTimed() bool {
+	return c.TimeLimit > 0 || c.SprintSeconds > 0
+}
+
+// Preset is a named session configuration stored for reuse
+type Preset struct {
+	ID        int64         `db:"id"`
+	ProfileID int64         `db:"profile_id"`
+	Name      string        `db:"name"`
+	Config    SessionConfig `db:"-"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
+// SavedSession is the state of an interrupted practice session, kept so it can be resumed
+type SavedSession struct {
+	Config         SessionConfig `json:"config"`
+	Sentences      []*Sentence   `json:"sentences"`
+	CurrentIndex   int           `json:"current_index"` // Next question to ask
+	CorrectCount   int           `json:"correct_count"`
+	IncorrectCount int           `json:"incorrect_count"`
+	Score          float64       `json:"score"`
+	TotalHints     int           `json:"total_hints"`
+	Mistakes       []*Sentence   `json:"mistakes,omitempty"`
+	SlowCount      int           `json:"slow_count,omitempty"`
+	ReviewRound    int           `json:"review_round,omitempty"`
+	MainSentences  []*Sentence   `json:"main_sentences,omitempty"`
+	AnswerTime     time.Duration `json:"answer_time"`
+	SprintLeft     time.Duration `json:"sprint_left,omitempty"` // Time left in a running sprint
+	UpdatedAt      time.Time     `json:"-"`
+}
+
+// Answered returns how many main session questions have been answered
+func (s *SavedSession) Answered() int {
+	return s.CorrectCount + s.IncorrectCount
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TableDrillResult is the outcome of one cell in a declension table drill
+type TableDrillResult struct {
+	ID              int64     `db:"id"`
+	ProfileID       int64     `db:"profile_id"`
+	NounID          int64     `db:"noun_id"`
+	Gender          string    `db:"gender" json:"gender"`
+	DeclensionClass string    `db:"declension_class" json:"declension_class"`
+	CaseType        string    `db:"case_type" json:"case_type"`
+	Number          string    `db:"number" json:"number"`
+	Correct         bool      `db:"correct"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+// TableDrillStat aggregates table drill results for one cell of a declension class
+type TableDrillStat struct {
+	Gender          string `db:"gender" json:"gender"`
+	DeclensionClass string `db:"declension_class" json:"declension_class"`
+	CaseType        string `db:"case_type" json:"case_type"`
+	Number          string `db:"number" json:"number"`
+	Attempts        int    `db:"attempts" json:"attempts"`
+	Misses          int    `db:"misses" json:"misses"`
+}
+
+// MissRate returns the fraction of attempts that were wrong
+func (s *TableDrillStat) MissRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Misses) / float64(s.Attempts)
+}
@@ -0,0 +1,95 @@
+package models
+
+import "strings"
+
+// Tag groups used to match nouns against template requirements
+const (
+	TagGroupClass        = "class"
+	TagGroupCountability = "countability"
+)
+
+// SemanticTags maps every known noun tag to its group
+var SemanticTags = map[string]string{
+	"person":   TagGroupClass,
+	"animal":   TagGroupClass,
+	"place":    TagGroupClass,
+	"food":     TagGroupClass,
+	"object":   TagGroupClass,
+	"abstract": TagGroupClass,
+	"time":     TagGroupClass,
+	"mass":     TagGroupCountability,
+	"count":    TagGroupCountability,
+}
+
+// NormalizeTags lowercases tags and drops unknown or duplicate entries
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if _, ok := SemanticTags[tag]; !ok || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// TagsCompatible reports whether a noun's tags satisfy a template's required tags.
+// Within a group the noun needs at least one of the required tags. Groups the
+// template doesn't mention, or the noun has no tags for, are not constrained.
+func TagsCompatible(nounTags, requiredTags []string) bool {
+	required := make(map[string][]string)
+	for _, tag := range requiredTags {
+		group := SemanticTags[tag]
+		required[group] = append(required[group], tag)
+	}
+
+	has := make(map[string]map[string]bool)
+	for _, tag := range nounTags {
+		group := SemanticTags[tag]
+		if has[group] == nil {
+			has[group] = make(map[string]bool)
+		}
+		has[group][tag] = true
+	}
+
+	for group, tags := range required {
+		if len(has[group]) == 0 {
+			continue // Unknown for this noun
+		}
+		matched := false
+		for _, tag := range tags {
+			if has[group][tag] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TagsConfirmed reports whether a noun's tags satisfy a template's required tags
+// in every group the template constrains. Unlike TagsCompatible, a group the noun
+// has no tags for counts as unmet.
+func TagsConfirmed(nounTags, requiredTags []string) bool {
+	if !TagsCompatible(nounTags, requiredTags) {
+		return false
+	}
+
+	groups := make(map[string]bool)
+	for _, tag := range nounTags {
+		groups[SemanticTags[tag]] = true
+	}
+	for _, tag := range requiredTags {
+		if !groups[SemanticTags[tag]] {
+			return false
+		}
+	}
+	return true
+}
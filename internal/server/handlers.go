@@ -0,0 +1,399 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// defaultSentenceCount and maxSentenceCount bound the count parameter of /api/sentences
+const (
+	defaultSentenceCount = 25
+	maxSentenceCount     = 200
+)
+
+// difficultyLevels lists the accepted difficulty parameter values
+var difficultyLevels = []string{"beginner", "intermediate", "advanced", "all"}
+
+// handleListNouns lists nouns, optionally narrowed by a search term and gender
+func (s *Server) handleListNouns(w http.ResponseWriter, r *http.Request) {
+	nouns, err := s.repo.SearchNouns(r.URL.Query().Get("q"))
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	gender := r.URL.Query().Get("gender")
+	matches := make([]*models.Noun, 0, len(nouns))
+	for _, noun := range nouns {
+		if gender == "" || noun.Gender == gender {
+			matches = append(matches, noun)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, matches)
+}
+
+func (s *Server) handleGetNoun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid noun id '%s'", r.PathValue("id")))
+		return
+	}
+
+	noun, err := s.repo.GetNoun(id)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, noun)
+}
+
+// handleSentences generates practice sentences for the session described by the query
+func (s *Server) handleSentences(w http.ResponseWriter, r *http.Request) {
+	config, err := parseSessionConfig(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sentences, err := storage.GenerateSessionSentences(s.repo, config, rng)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	if sentences == nil {
+		sentences = []*models.Sentence{}
+	}
+
+	writeJSON(w, http.StatusOK, sentences)
+}
+
+// parseSessionConfig reads a session configuration from query parameters. List
+// parameters may be repeated or comma-separated.
+func parseSessionConfig(query url.Values) (models.SessionConfig, error) {
+	config := models.SessionConfig{
+		QuestionCount: defaultSentenceCount,
+		Mode:          "sentence",
+	}
+
+	if difficulty := query.Get("difficulty"); difficulty != "" {
+		if !slices.Contains(difficultyLevels, difficulty) {
+			return config, fmt.Errorf("invalid difficulty '%s', must be one of: %s", difficulty, strings.Join(difficultyLevels, ", "))
+		}
+		if difficulty != "all" {
+			config.DifficultyLevel = difficulty
+		}
+	}
+
+	if plural := query.Get("plural"); plural != "" {
+		include, err := strconv.ParseBool(plural)
+		if err != nil {
+			return config, fmt.Errorf("invalid plural '%s', must be true or false", plural)
+		}
+		config.IncludePlural = include
+	}
+
+	if count := query.Get("count"); count != "" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 || n > maxSentenceCount {
+			return config, fmt.Errorf("invalid count '%s', must be between 1 and %d", count, maxSentenceCount)
+		}
+		config.QuestionCount = n
+	}
+
+	filter := &config.Filter
+	filter.Genders = listParam(query, "gender")
+	filter.Cases = listParam(query, "case")
+	filter.Prepositions = listParam(query, "preposition")
+	filter.ContextTypes = listParam(query, "context")
+	filter.Tags = models.NormalizeTags(listParam(query, "tag"))
+	if nouns := query.Get("nouns"); nouns != "" {
+		ranges, err := models.ParseIDRanges(nouns)
+		if err != nil {
+			return config, fmt.Errorf("invalid nouns: %w", err)
+		}
+		filter.NounRanges = ranges
+	}
+
+	return config, filter.Validate()
+}
+
+// listParam collects the values of a repeatable, comma-separated parameter
+func listParam(query url.Values, name string) []string {
+	var values []string
+	for _, value := range query[name] {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// sentenceRequest is the body of requests about one generated sentence
+type sentenceRequest struct {
+	Sentence *models.Sentence `json:"sentence"`
+}
+
+// decodeSentenceRequest reads a JSON body holding a sentence into req and replaces
+// the sentence with the one rebuilt from the database, so the client's copy is
+// never trusted for the answer. It writes an error response and returns the
+// sentence's noun, or nil if the body or its sentence is invalid.
+func (s *Server) decodeSentenceRequest(w http.ResponseWriter, r *http.Request, req any, sentence **models.Sentence) *models.Noun {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err := decoder.Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return nil
+	}
+	if *sentence == nil {
+		writeError(w, http.StatusBadRequest, "sentence is required")
+		return nil
+	}
+
+	expected, noun, err := storage.ExpectedSentence(s.repo, *sentence)
+	if err != nil {
+		writeStorageError(w, err)
+		return nil
+	}
+	*sentence = expected
+	return noun
+}
+
+// hintsResponse is the body of POST /api/hints
+type hintsResponse struct {
+	Hints   []string `json:"hints"`
+	Penalty float64  `json:"penalty"` // Score lost per hint revealed
+}
+
+// handleHints returns the progressive hints for a sentence, to be revealed one at a time
+func (s *Server) handleHints(w http.ResponseWriter, r *http.Request) {
+	var req sentenceRequest
+	noun := s.decodeSentenceRequest(w, r, &req, &req.Sentence)
+	if noun == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hintsResponse{
+		Hints:   explanations.Hints(req.Sentence, noun),
+		Penalty: grading.HintPenalty,
+	})
+}
+
+// alternativeRequest is the body of POST /api/alternatives
+type alternativeRequest struct {
+	Sentence *models.Sentence     `json:"sentence"`
+	Config   models.SessionConfig `json:"config"` // Session settings the alternative must stay within
+}
+
+// handleAlternative returns a sentence testing the same noun, case and number with a
+// different template within the session's settings, or the sentence itself when no
+// other template fits
+func (s *Server) handleAlternative(w http.ResponseWriter, r *http.Request) {
+	var req alternativeRequest
+	if s.decodeSentenceRequest(w, r, &req, &req.Sentence) == nil {
+		return
+	}
+	if err := validateSessionConfig(&req.Config); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	alternative, err := s.repo.GenerateAlternativeSentence(req.Sentence, req.Config)
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	if alternative == nil {
+		alternative = req.Sentence
+	}
+
+	writeJSON(w, http.StatusOK, alternative)
+}
+
+// answerRequest is the body of POST /api/answers
+type answerRequest struct {
+	Sentence  *models.Sentence `json:"sentence"`
+	Answer    string           `json:"answer"`
+	Mode      string           `json:"mode"`
+	HintsUsed int              `json:"hints_used"`
+	LatencyMs int64            `json:"latency_ms"`
+}
+
+// answerResponse is the graded answer returned by POST /api/answers
+type answerResponse struct {
+	Correct         bool                `json:"correct"`
+	CorrectAnswer   string              `json:"correct_answer"`
+	AcceptedAnswers []string            `json:"accepted_answers"`
+	Score           float64             `json:"score"`
+	Explanation     *models.Explanation `json:"explanation,omitempty"`
+}
+
+// handleAnswer grades an answer to a generated sentence against the form stored
+// for its noun, records the attempt and explains the correct form
+func (s *Server) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	var req answerRequest
+	noun := s.decodeSentenceRequest(w, r, &req, &req.Sentence)
+	if noun == nil {
+		return
+	}
+	sentence := req.Sentence
+	if req.HintsUsed < 0 || req.LatencyMs < 0 {
+		writeError(w, http.StatusBadRequest, "hints_used and latency_ms cannot be negative")
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = "sentence"
+	}
+
+	correct := grading.Grade(req.Answer, sentence)
+	score := grading.Score(correct, req.HintsUsed)
+
+	attempt := &models.Attempt{
+		NounID:        sentence.NounID,
+		Mode:          req.Mode,
+		CaseType:      sentence.CaseType,
+		Number:        sentence.Number,
+		ContextType:   sentence.ContextType,
+		UserAnswer:    req.Answer,
+		CorrectAnswer: sentence.CorrectAnswer,
+		Correct:       correct,
+		HintsUsed:     req.HintsUsed,
+		Score:         score,
+		LatencyMs:     req.LatencyMs,
+	}
+	if sentence.TemplateID != 0 {
+		templateID := sentence.TemplateID
+		attempt.TemplateID = &templateID
+	}
+	if err := s.repo.RecordAttempt(attempt); err != nil {
+		writeStorageError(w, err)
+		return
+	}
+
+	explanation, err := explanations.Generate(sentence, noun)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, answerResponse{
+		Correct:         correct,
+		CorrectAnswer:   sentence.CorrectAnswer,
+		AcceptedAnswers: grading.AcceptedAnswers(sentence),
+		Score:           score,
+		Explanation:     explanation,
+	})
+}
+
+// caseStats summarizes the attempts for one case and number
+type caseStats struct {
+	CaseType string  `json:"case_type"`
+	Number   string  `json:"number"`
+	Attempts int     `json:"attempts"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// statsResponse is the body of GET /api/stats
+type statsResponse struct {
+	Profile          string                   `json:"profile"`
+	Attempts         int                      `json:"attempts"`
+	Correct          int                      `json:"correct"`
+	Accuracy         float64                  `json:"accuracy"`
+	AverageLatencyMs int64                    `json:"average_latency_ms"`
+	ByCase           []caseStats              `json:"by_case"`
+	TableDrill       []*models.TableDrillStat `json:"table_drill"`
+}
+
+// handleStats summarizes the current profile's progress
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	profile, err := s.repo.CurrentProfile()
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	attempts, err := s.repo.ListAttempts()
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	tableDrill, err := s.repo.ListTableDrillStats()
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	if tableDrill == nil {
+		tableDrill = []*models.TableDrillStat{}
+	}
+
+	stats := summarizeAttempts(attempts)
+	stats.Profile = profile.Name
+	stats.TableDrill = tableDrill
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// summarizeAttempts totals attempts overall and per case and number
+func summarizeAttempts(attempts []*models.Attempt) statsResponse {
+	stats := statsResponse{ByCase: []caseStats{}}
+	var totalLatency, timed int64
+
+	for _, attempt := range attempts {
+		stats.Attempts++
+		if attempt.LatencyMs > 0 {
+			totalLatency += attempt.LatencyMs
+			timed++
+		}
+
+		i := slices.IndexFunc(stats.ByCase, func(c caseStats) bool {
+			return c.CaseType == attempt.CaseType && c.Number == attempt.Number
+		})
+		if i < 0 {
+			stats.ByCase = append(stats.ByCase, caseStats{CaseType: attempt.CaseType, Number: attempt.Number})
+			i = len(stats.ByCase) - 1
+		}
+		stats.ByCase[i].Attempts++
+
+		if attempt.Correct {
+			stats.Correct++
+			stats.ByCase[i].Correct++
+		}
+	}
+
+	slices.SortFunc(stats.ByCase, func(a, b caseStats) int {
+		return strings.Compare(a.CaseType+"/"+a.Number, b.CaseType+"/"+b.Number)
+	})
+
+	stats.Accuracy = ratio(stats.Correct, stats.Attempts)
+	for i := range stats.ByCase {
+		stats.ByCase[i].Accuracy = ratio(stats.ByCase[i].Correct, stats.ByCase[i].Attempts)
+	}
+	if timed > 0 {
+		stats.AverageLatencyMs = totalLatency / timed
+	}
+
+	return stats
+}
+
+// ratio returns part/total, or 0 when total is 0
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
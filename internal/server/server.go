@@ -0,0 +1,119 @@
+// Package server exposes the repository, grading and explanations over HTTP/JSON
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// maxBodyBytes limits the size of request bodies
+const maxBodyBytes = 1 << 20
+
+// Server serves the JSON API
+type Server struct {
+	repo     storage.Repository
+	mux      *http.ServeMux
+	mu       sync.Mutex // Guards sessions and the sessions' state
+	sessions map[string]*liveSession
+}
+
+// New creates a server backed by a repository
+func New(repo storage.Repository) *Server {
+	s := &Server{
+		repo:     repo,
+		mux:      http.NewServeMux(),
+		sessions: make(map[string]*liveSession),
+	}
+
+	s.mux.HandleFunc("GET /api/nouns", s.handleListNouns)
+	s.mux.HandleFunc("GET /api/nouns/{id}", s.handleGetNoun)
+	s.mux.HandleFunc("GET /api/sentences", s.handleSentences)
+	s.mux.HandleFunc("POST /api/answers", s.handleAnswer)
+	s.mux.HandleFunc("POST /api/hints", s.handleHints)
+	s.mux.HandleFunc("POST /api/alternatives", s.handleAlternative)
+	s.mux.HandleFunc("POST /api/sessions", s.handleCreateSession)
+	s.mux.HandleFunc("GET /api/sessions/saved", s.handleSavedSession)
+	s.mux.HandleFunc("GET /api/sessions/{id}", s.handleGetSession)
+	s.mux.HandleFunc("POST /api/sessions/{id}/hints", s.handleSessionHint)
+	s.mux.HandleFunc("POST /api/sessions/{id}/answers", s.handleSessionAnswer)
+	s.mux.HandleFunc("POST /api/sessions/{id}/next", s.handleSessionNext)
+	s.mux.HandleFunc("POST /api/sessions/{id}/review", s.handleSessionReview)
+	s.mux.HandleFunc("POST /api/sessions/{id}/restart", s.handleSessionRestart)
+	s.mux.HandleFunc("POST /api/sessions/{id}/finish", s.handleSessionFinish)
+	s.mux.HandleFunc("GET /api/stats", s.handleStats)
+	s.mux.HandleFunc("GET /api/openapi.yaml", s.handleOpenAPI)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler. POST requests must carry a JSON body and
+// come from the API's own origin, so that web pages elsewhere cannot record
+// attempts through a simple cross-origin form or text/plain request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+			writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !sameOrigin(origin, r) {
+			writeError(w, http.StatusForbidden, "cross-origin requests are not allowed")
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// sameOrigin reports whether an Origin header names the host the request was sent to
+func sameOrigin(origin string, r *http.Request) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return u.Host == r.Host
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// writeStorageError reports a repository error, using 404 for missing records and
+// 400 for sentences that don't match the database
+func writeStorageError(w http.ResponseWriter, err error) {
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, storage.ErrInvalidSentence) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPISpec)
+}
@@ -0,0 +1,397 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// setupServer creates a server over a database with two nouns and one template
+func setupServer(t *testing.T) (*Server, *storage.SQLiteRepository) {
+	t.Helper()
+
+	repo, err := storage.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	nouns := []*models.Noun{
+		{
+			English: "teacher", Gender: "masculine",
+			NominativeSg: "δάσκαλος", GenitiveSg: "δασκάλου", AccusativeSg: "δάσκαλο",
+			NominativePl: "δάσκαλοι", GenitivePl: "δασκάλων", AccusativePl: "δασκάλους",
+			NomSgArticle: "ο", GenSgArticle: "του", AccSgArticle: "τον",
+			NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τους",
+		},
+		{
+			English: "book", Gender: "neuter",
+			NominativeSg: "βιβλίο", GenitiveSg: "βιβλίου", AccusativeSg: "βιβλίο",
+			NominativePl: "βιβλία", GenitivePl: "βιβλίων", AccusativePl: "βιβλία",
+			NomSgArticle: "το", GenSgArticle: "του", AccSgArticle: "το",
+			NomPlArticle: "τα", GenPlArticle: "των", AccPlArticle: "τα",
+		},
+	}
+	for _, noun := range nouns {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I see ___ (the {noun})",
+		GreekTemplate:   "Βλέπω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	return New(repo), repo
+}
+
+// do sends a request to the server and decodes a JSON response into out
+func do(t *testing.T, s *Server, method, target string, body any, out any) int {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decode response %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestListNouns(t *testing.T) {
+	s, _ := setupServer(t)
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/api/nouns", []string{"teacher", "book"}},
+		{"/api/nouns?q=δασκαλος", []string{"teacher"}},
+		{"/api/nouns?q=BOO", []string{"book"}},
+		{"/api/nouns?gender=neuter", []string{"book"}},
+		{"/api/nouns?q=cat", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var nouns []*models.Noun
+			if code := do(t, s, "GET", tt.target, nil, &nouns); code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", code)
+			}
+			got := make([]string, len(nouns))
+			for i, noun := range nouns {
+				got[i] = noun.English
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("nouns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNoun(t *testing.T) {
+	s, _ := setupServer(t)
+
+	var noun models.Noun
+	if code := do(t, s, "GET", "/api/nouns/1", nil, &noun); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if noun.NominativeSg != "δάσκαλος" || noun.GenPlArticle != "των" {
+		t.Errorf("noun = %+v", noun)
+	}
+
+	var apiErr errorResponse
+	if code := do(t, s, "GET", "/api/nouns/99", nil, &apiErr); code != http.StatusNotFound {
+		t.Errorf("missing noun status = %d, want 404", code)
+	}
+	if !strings.Contains(apiErr.Error, "not found") {
+		t.Errorf("error = %q, want a not found message", apiErr.Error)
+	}
+	if code := do(t, s, "GET", "/api/nouns/abc", nil, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("invalid id status = %d, want 400", code)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	s, _ := setupServer(t)
+
+	var sentences []*models.Sentence
+	if code := do(t, s, "GET", "/api/sentences?gender=masculine&case=accusative", nil, &sentences); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(sentences) != 1 {
+		t.Fatalf("Expected 1 sentence for the masculine noun, got %d", len(sentences))
+	}
+	if sentences[0].CorrectAnswer != "τον δάσκαλο" || sentences[0].TemplateID == 0 {
+		t.Errorf("sentence = %+v", sentences[0])
+	}
+
+	// Filters that leave nothing to practise are reported as not found
+	var apiErr errorResponse
+	if code := do(t, s, "GET", "/api/sentences?case=genitive", nil, &apiErr); code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", code)
+	}
+	if !strings.Contains(apiErr.Error, "no templates match") {
+		t.Errorf("error = %q, want the unmatched filters explained", apiErr.Error)
+	}
+
+	for _, target := range []string{
+		"/api/sentences?case=dative",
+		"/api/sentences?count=0",
+		"/api/sentences?difficulty=expert",
+		"/api/sentences?nouns=5-1",
+	} {
+		var apiErr errorResponse
+		if code := do(t, s, "GET", target, nil, &apiErr); code != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", target, code)
+		}
+	}
+}
+
+func TestAnswerAndStats(t *testing.T) {
+	s, repo := setupServer(t)
+
+	var sentences []*models.Sentence
+	do(t, s, "GET", "/api/sentences?gender=masculine", nil, &sentences)
+	if len(sentences) != 1 {
+		t.Fatalf("Expected 1 sentence, got %d", len(sentences))
+	}
+
+	var result answerResponse
+	body := answerRequest{Sentence: sentences[0], Answer: " τον  δάσκαλο", LatencyMs: 3000}
+	if code := do(t, s, "POST", "/api/answers", body, &result); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if !result.Correct || result.Score != 1 || result.CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.Explanation == nil || result.Explanation.Morphology == "" {
+		t.Errorf("Expected an explanation, got %+v", result.Explanation)
+	}
+
+	body = answerRequest{Sentence: sentences[0], Answer: "ο δάσκαλος", HintsUsed: 1, LatencyMs: 5000}
+	if code := do(t, s, "POST", "/api/answers", body, &result); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if result.Correct || result.Score != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[1].HintsUsed != 1 || attempts[1].Mode != "sentence" {
+		t.Errorf("Expected both answers to be recorded, got %+v", attempts)
+	}
+
+	var stats statsResponse
+	if code := do(t, s, "GET", "/api/stats", nil, &stats); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if stats.Profile != "default" || stats.Attempts != 2 || stats.Correct != 1 || stats.Accuracy != 0.5 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.AverageLatencyMs != 4000 {
+		t.Errorf("average latency = %d, want 4000", stats.AverageLatencyMs)
+	}
+	if len(stats.ByCase) != 1 || stats.ByCase[0].CaseType != "accusative" || stats.ByCase[0].Attempts != 2 {
+		t.Errorf("by case = %+v", stats.ByCase)
+	}
+}
+
+func TestHintsAndAlternatives(t *testing.T) {
+	s, _ := setupServer(t)
+
+	var sentences []*models.Sentence
+	do(t, s, "GET", "/api/sentences?gender=masculine", nil, &sentences)
+	if len(sentences) != 1 {
+		t.Fatalf("Expected 1 sentence, got %d", len(sentences))
+	}
+
+	var hints hintsResponse
+	if code := do(t, s, "POST", "/api/hints", sentenceRequest{Sentence: sentences[0]}, &hints); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if len(hints.Hints) == 0 || hints.Penalty != 0.25 {
+		t.Errorf("hints = %+v", hints)
+	}
+	if hints.Hints[0] != "Gender: masculine" {
+		t.Errorf("Expected the first hint to give the gender, got %q", hints.Hints[0])
+	}
+
+	// With a single template the sentence itself is the only alternative
+	var alternative models.Sentence
+	if code := do(t, s, "POST", "/api/alternatives", sentenceRequest{Sentence: sentences[0]}, &alternative); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if alternative.CorrectAnswer != "τον δάσκαλο" || alternative.TemplateID != sentences[0].TemplateID {
+		t.Errorf("alternative = %+v", alternative)
+	}
+
+	var apiErr errorResponse
+	body := alternativeRequest{Sentence: sentences[0], Config: models.SessionConfig{DifficultyLevel: "expert"}}
+	if code := do(t, s, "POST", "/api/alternatives", body, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("invalid config status = %d, want 400", code)
+	}
+	if code := do(t, s, "POST", "/api/hints", sentenceRequest{}, &apiErr); code != http.StatusBadRequest {
+		t.Errorf("missing sentence status = %d, want 400", code)
+	}
+}
+
+func TestAnswerValidation(t *testing.T) {
+	s, _ := setupServer(t)
+
+	tests := []struct {
+		name string
+		body any
+		want int
+	}{
+		{"missing sentence", map[string]string{"answer": "τον δάσκαλο"}, http.StatusBadRequest},
+		{"negative hints", answerRequest{Sentence: &models.Sentence{NounID: 1, CaseType: "accusative", Number: "singular"}, HintsUsed: -1}, http.StatusBadRequest},
+		{"unknown noun", answerRequest{Sentence: &models.Sentence{NounID: 99, CaseType: "accusative", Number: "singular"}}, http.StatusNotFound},
+		{"unknown template", answerRequest{Sentence: &models.Sentence{NounID: 1, TemplateID: 99}}, http.StatusNotFound},
+		{"invalid case", answerRequest{Sentence: &models.Sentence{NounID: 1, CaseType: "dative", Number: "singular"}}, http.StatusBadRequest},
+		{"not JSON", "τον δάσκαλο", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr errorResponse
+			if code := do(t, s, "POST", "/api/answers", tt.body, &apiErr); code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", code, tt.want, apiErr.Error)
+			}
+		})
+	}
+}
+
+func TestAnswerIgnoresClientCorrectAnswer(t *testing.T) {
+	s, repo := setupServer(t)
+
+	var sentences []*models.Sentence
+	do(t, s, "GET", "/api/sentences?gender=masculine", nil, &sentences)
+	if len(sentences) != 1 {
+		t.Fatalf("Expected 1 sentence, got %d", len(sentences))
+	}
+
+	// A forged correct answer is replaced by the form rebuilt from the template
+	forged := *sentences[0]
+	forged.CorrectAnswer = "ο δάσκαλος"
+	var result answerResponse
+	body := answerRequest{Sentence: &forged, Answer: "ο δάσκαλος"}
+	if code := do(t, s, "POST", "/api/answers", body, &result); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if result.Correct || result.CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("result = %+v, want the forged answer graded wrong", result)
+	}
+
+	// Without a template the answer comes from the noun's form for the case and number
+	body = answerRequest{
+		Sentence: &models.Sentence{NounID: 2, CaseType: "genitive", Number: "plural", CorrectAnswer: "το βιβλίο"},
+		Answer:   "των βιβλίων",
+	}
+	if code := do(t, s, "POST", "/api/answers", body, &result); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if !result.Correct || result.CorrectAnswer != "των βιβλίων" {
+		t.Errorf("result = %+v, want the stored genitive plural", result)
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].Correct || attempts[0].CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("Expected the stored answers to be recorded, got %+v", attempts)
+	}
+}
+
+func TestPostRequiresJSONFromSameOrigin(t *testing.T) {
+	s, _ := setupServer(t)
+
+	body := `{"sentence": {"noun_id": 1, "case_type": "accusative", "number": "singular"}, "answer": "τον δάσκαλο"}`
+	tests := []struct {
+		name        string
+		contentType string
+		origin      string
+		want        int
+	}{
+		{"JSON without origin", "application/json", "", http.StatusOK},
+		{"JSON with charset", "application/json; charset=utf-8", "", http.StatusOK},
+		{"same origin", "application/json", "http://example.com", http.StatusOK},
+		{"text/plain", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"form", "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"missing content type", "", "", http.StatusUnsupportedMediaType},
+		{"foreign origin", "application/json", "http://evil.test", http.StatusForbidden},
+		{"null origin", "application/json", "null", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/answers", strings.NewReader(body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	s, _ := setupServer(t)
+
+	req := httptest.NewRequest("GET", "/api/openapi.yaml", nil)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	for _, path := range []string{"/api/nouns:", "/api/sentences:", "/api/answers:", "/api/sessions:", "/api/stats:"} {
+		if !strings.Contains(rec.Body.String(), path) {
+			t.Errorf("Expected the spec to document %s", path)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/session"
+)
+
+// browserModes lists the session modes the browser UI runs; reverse sessions and
+// table drills are only available in the terminal
+var browserModes = []string{"sentence", "choice"}
+
+// liveSession is a practice session run by the browser
+type liveSession struct {
+	*session.Session
+	lastUsed time.Time
+}
+
+// sessionRequest is the body of POST /api/sessions
+type sessionRequest struct {
+	Config *models.SessionConfig `json:"config"`
+	Resume bool                  `json:"resume"` // Continue the profile's interrupted session instead
+}
+
+// questionResponse describes the question being asked. It leaves out the answer.
+type questionResponse struct {
+	Number      int      `json:"number"`
+	Total       int      `json:"total"`
+	Endless     bool     `json:"endless"`
+	ReviewRound int      `json:"review_round"`
+	Prompt      string   `json:"prompt"`
+	Choices     []string `json:"choices,omitempty"`
+	Hints       []string `json:"hints"` // Hints revealed so far
+	HintsLeft   int      `json:"hints_left"`
+	HintPenalty float64  `json:"hint_penalty"`
+	TimeLeftMs  int64    `json:"time_left_ms,omitempty"` // Countdown left, when the session has one
+}
+
+// resultResponse is the graded answer shown on the feedback screen
+type resultResponse struct {
+	Answer          string              `json:"answer"`
+	TimedOut        bool                `json:"timed_out"`
+	Correct         bool                `json:"correct"`
+	CorrectAnswer   string              `json:"correct_answer"`
+	AcceptedAnswers []string            `json:"accepted_answers"`
+	HintsUsed       int                 `json:"hints_used"`
+	Score           float64             `json:"score"`
+	LatencyMs       int64               `json:"latency_ms"`
+	Slow            bool                `json:"slow"`
+	Explanation     *models.Explanation `json:"explanation,omitempty"`
+}
+
+// summaryResponse is the outcome shown when the session is complete
+type summaryResponse struct {
+	Answered         int     `json:"answered"`
+	Length           int     `json:"length"`
+	Correct          int     `json:"correct"`
+	Accuracy         int     `json:"accuracy"`
+	Score            float64 `json:"score"`
+	TotalHints       int     `json:"total_hints"`
+	AnswersPerMinute float64 `json:"answers_per_minute"`
+	SecondsPerAnswer float64 `json:"seconds_per_answer"`
+	ReviewRounds     int     `json:"review_rounds"`
+	Missed           int     `json:"missed"` // Mistakes queued for review
+	Slow             int     `json:"slow"`   // Slow answers queued for review
+}
+
+// sessionResponse is the state of a browser session, which the UI renders
+type sessionResponse struct {
+	ID           string               `json:"id"`
+	State        string               `json:"state"`
+	Config       models.SessionConfig `json:"config"`
+	Question     *questionResponse    `json:"question,omitempty"`
+	Result       *resultResponse      `json:"result,omitempty"`
+	Summary      *summaryResponse     `json:"summary,omitempty"`
+	SprintLeftMs int64                `json:"sprint_left_ms,omitempty"`
+}
+
+// savedSessionResponse is the body of GET /api/sessions/saved
+type savedSessionResponse struct {
+	Config    models.SessionConfig `json:"config"`
+	Answered  int                  `json:"answered"`
+	Correct   int                  `json:"correct"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// handleCreateSession starts a browser session, or resumes the profile's interrupted one
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req sessionRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	var sess *session.Session
+	var err error
+	if req.Resume {
+		saved, loadErr := s.resumableSession()
+		if loadErr != nil {
+			writeStorageError(w, loadErr)
+			return
+		}
+		if saved == nil {
+			writeError(w, http.StatusNotFound, "no session to resume")
+			return
+		}
+		sess, err = session.Resume(s.repo, saved)
+	} else {
+		if req.Config == nil {
+			writeError(w, http.StatusBadRequest, "config is required")
+			return
+		}
+		if err := validateSessionConfig(req.Config); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sess, err = session.New(s.repo, *req.Config)
+	}
+	if sess == nil {
+		if errors.Is(err, session.ErrNoSentences) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeStorageError(w, err)
+		return
+	}
+	if err != nil {
+		slog.Warn("Session question could not be prepared", "error", err)
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, live := range s.sessions {
+		if time.Since(live.lastUsed) > session.Expiry {
+			delete(s.sessions, key)
+		}
+	}
+	s.sessions[id] = &liveSession{Session: sess, lastUsed: time.Now()}
+
+	writeJSON(w, http.StatusCreated, sessionView(id, sess))
+}
+
+// validateSessionConfig checks a browser session's settings, defaulting the mode
+func validateSessionConfig(config *models.SessionConfig) error {
+	if config.Mode == "" {
+		config.Mode = "sentence"
+	}
+	if !slices.Contains(browserModes, config.Mode) {
+		return fmt.Errorf("mode '%s' is not available in the browser, must be one of: %s", config.Mode, strings.Join(browserModes, ", "))
+	}
+	if config.DifficultyLevel != "" && !slices.Contains(difficultyLevels, config.DifficultyLevel) {
+		return fmt.Errorf("invalid difficulty_level '%s', must be one of: %s", config.DifficultyLevel, strings.Join(difficultyLevels, ", "))
+	}
+	if config.DifficultyLevel == "all" {
+		config.DifficultyLevel = ""
+	}
+	if config.QuestionCount < 0 || config.QuestionCount > maxSentenceCount {
+		return fmt.Errorf("invalid question_count %d, must be between 0 (endless) and %d", config.QuestionCount, maxSentenceCount)
+	}
+	if config.TimeLimit < 0 || config.SprintSeconds < 0 {
+		return fmt.Errorf("time_limit and sprint_seconds cannot be negative")
+	}
+	config.Filter.Tags = models.NormalizeTags(config.Filter.Tags)
+	return config.Filter.Validate()
+}
+
+// resumableSession returns the profile's interrupted session if the browser can run it
+func (s *Server) resumableSession() (*models.SavedSession, error) {
+	saved, err := session.LoadSaved(s.repo)
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	if saved.Config.Mode == "" {
+		saved.Config.Mode = "sentence"
+	}
+	if !slices.Contains(browserModes, saved.Config.Mode) {
+		return nil, nil
+	}
+	return saved, nil
+}
+
+// handleSavedSession describes the interrupted session the browser can resume
+func (s *Server) handleSavedSession(w http.ResponseWriter, r *http.Request) {
+	saved, err := s.resumableSession()
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	if saved == nil {
+		writeError(w, http.StatusNotFound, "no session to resume")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, savedSessionResponse{
+		Config:    saved.Config,
+		Answered:  saved.Answered(),
+		Correct:   saved.CorrectCount,
+		UpdatedAt: saved.UpdatedAt,
+	})
+}
+
+// handleGetSession returns a session's current state
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(sess *session.Session) error { return nil })
+}
+
+// handleSessionHint reveals the next hint for the question being asked
+func (s *Server) handleSessionHint(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(sess *session.Session) error {
+		if !sess.RevealHint() {
+			return session.ErrInvalidState
+		}
+		return nil
+	})
+}
+
+// sessionAnswerRequest is the body of POST /api/sessions/{id}/answers
+type sessionAnswerRequest struct {
+	Answer string `json:"answer"`
+}
+
+// handleSessionAnswer grades an answer to the question being asked
+func (s *Server) handleSessionAnswer(w http.ResponseWriter, r *http.Request) {
+	var req sessionAnswerRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	s.withSession(w, r, func(sess *session.Session) error {
+		result, err := sess.Submit(req.Answer)
+		if result == nil {
+			return err
+		}
+		if err != nil {
+			// The answer was graded; only recording or saving it failed
+			slog.Warn("Session answer not fully recorded", "error", err)
+		}
+		return nil
+	})
+}
+
+// handleSessionNext moves from an answer's feedback to the next question
+func (s *Server) handleSessionNext(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, (*session.Session).Next)
+}
+
+// handleSessionReview starts a review round of the finished session's mistakes
+func (s *Server) handleSessionReview(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, (*session.Session).Review)
+}
+
+// handleSessionRestart starts the session over with a fresh score
+func (s *Server) handleSessionRestart(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, (*session.Session).Restart)
+}
+
+// handleSessionFinish ends the session early and shows its summary
+func (s *Server) handleSessionFinish(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, (*session.Session).Finish)
+}
+
+// withSession applies an action to the session named in the path and writes the
+// session's new state. A sprint whose time is up is ended first, and actions not
+// allowed in the session's state are rejected.
+func (s *Server) withSession(w http.ResponseWriter, r *http.Request, action func(*session.Session) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	live, ok := s.sessions[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("session '%s' not found", id))
+		return
+	}
+	live.lastUsed = time.Now()
+	sess := live.Session
+
+	if sess.SprintOver() {
+		if err := sess.Finish(); err != nil {
+			slog.Warn("Failed to discard finished session", "error", err)
+		}
+	}
+
+	if err := action(sess); err != nil {
+		if errors.Is(err, session.ErrInvalidState) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		// The session has moved on; a skipped question or failed save is only logged
+		slog.Warn("Session action not fully completed", "error", err)
+	}
+
+	writeJSON(w, http.StatusOK, sessionView(id, sess))
+}
+
+// sessionView describes a session's state for the browser to render
+func sessionView(id string, sess *session.Session) sessionResponse {
+	view := sessionResponse{
+		ID:     id,
+		State:  sess.State(),
+		Config: sess.Config(),
+	}
+	if sprintEnd := sess.SprintEnd(); !sprintEnd.IsZero() {
+		view.SprintLeftMs = max(time.Until(sprintEnd).Milliseconds(), 0)
+	}
+
+	switch sess.State() {
+	case session.StateQuestion:
+		progress := sess.Progress()
+		view.Question = &questionResponse{
+			Number:      progress.Question,
+			Total:       progress.Total,
+			Endless:     progress.Endless,
+			ReviewRound: progress.ReviewRound,
+			Prompt:      sess.Sentence().EnglishPrompt,
+			Choices:     sess.Choices(),
+			Hints:       append([]string{}, sess.Hints()...),
+			HintsLeft:   sess.HintsLeft(),
+			HintPenalty: grading.HintPenalty,
+		}
+		if deadline := sess.QuestionDeadline(); !deadline.IsZero() {
+			view.Question.TimeLeftMs = max(time.Until(deadline).Milliseconds(), 0)
+		}
+
+	case session.StateFeedback:
+		result := sess.Result()
+		view.Result = &resultResponse{
+			Answer:          result.Answer,
+			TimedOut:        result.TimedOut,
+			Correct:         result.Correct,
+			CorrectAnswer:   result.Sentence.CorrectAnswer,
+			AcceptedAnswers: grading.AcceptedAnswers(result.Sentence),
+			HintsUsed:       result.HintsUsed,
+			Score:           result.Score,
+			LatencyMs:       result.Latency.Milliseconds(),
+			Slow:            result.Slow,
+			Explanation:     result.Explanation,
+		}
+
+	case session.StateComplete:
+		summary := sess.Summary()
+		view.Summary = &summaryResponse{
+			Answered:         summary.Answered,
+			Length:           summary.Length,
+			Correct:          summary.Correct,
+			Accuracy:         summary.Accuracy,
+			Score:            summary.Score,
+			TotalHints:       summary.TotalHints,
+			AnswersPerMinute: grading.AnswersPerMinute(summary.Answered, summary.AnswerTime),
+			ReviewRounds:     summary.ReviewRounds,
+			Missed:           summary.Missed,
+			Slow:             summary.Slow,
+		}
+		if summary.Answered > 0 {
+			view.Summary.SecondsPerAnswer = summary.AnswerTime.Seconds() / float64(summary.Answered)
+		}
+	}
+
+	return view
+}
+
+// newSessionID returns a random identifier for a browser session
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create session id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// empty is the body of session actions that take no parameters
+var empty = struct{}{}
+
+func TestSessionFlow(t *testing.T) {
+	s, repo := setupServer(t)
+
+	var view sessionResponse
+	body := sessionRequest{Config: &models.SessionConfig{QuestionCount: 2}}
+	if code := do(t, s, "POST", "/api/sessions", body, &view); code != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", code)
+	}
+	if view.State != "question" || view.Question == nil || view.Config.Mode != "sentence" {
+		t.Fatalf("view = %+v, want the first question of a sentence session", view)
+	}
+	if view.Question.Number != 1 || view.Question.Total != 2 || !strings.Contains(view.Question.Prompt, "I see") {
+		t.Errorf("question = %+v, want question 1 of 2", view.Question)
+	}
+	base := "/api/sessions/" + view.ID
+
+	// Hints are revealed one at a time
+	if code := do(t, s, "POST", base+"/hints", empty, &view); code != http.StatusOK {
+		t.Fatalf("hint status = %d, want 200", code)
+	}
+	if len(view.Question.Hints) != 1 || view.Question.HintPenalty == 0 {
+		t.Errorf("question = %+v, want one revealed hint", view.Question)
+	}
+
+	// Answering before moving on is rejected
+	if code := do(t, s, "POST", base+"/next", empty, nil); code != http.StatusConflict {
+		t.Errorf("next before answering status = %d, want 409", code)
+	}
+
+	if code := do(t, s, "POST", base+"/answers", sessionAnswerRequest{Answer: "λάθος"}, &view); code != http.StatusOK {
+		t.Fatalf("answer status = %d, want 200", code)
+	}
+	if view.State != "feedback" || view.Result == nil || view.Result.Correct || view.Result.Explanation == nil {
+		t.Fatalf("view = %+v, want an explained mistake", view)
+	}
+	if view.Result.HintsUsed != 1 || view.Result.CorrectAnswer == "" {
+		t.Errorf("result = %+v, want 1 hint used and the correct answer", view.Result)
+	}
+
+	do(t, s, "POST", base+"/next", empty, &view)
+	correct := "τον δάσκαλο"
+	if strings.Contains(view.Question.Prompt, "book") {
+		correct = "το βιβλίο"
+	}
+	do(t, s, "POST", base+"/answers", sessionAnswerRequest{Answer: correct}, &view)
+	if !view.Result.Correct {
+		t.Fatalf("result = %+v, want %q graded correct", view.Result, correct)
+	}
+	do(t, s, "POST", base+"/next", empty, &view)
+
+	if view.State != "complete" || view.Summary == nil {
+		t.Fatalf("view = %+v, want the completed session", view)
+	}
+	if got := view.Summary; got.Answered != 2 || got.Correct != 1 || got.Accuracy != 50 || got.Missed != 1 {
+		t.Errorf("summary = %+v, want 1 of 2 correct with 1 mistake", got)
+	}
+
+	// Reviewing repeats the mistake
+	if code := do(t, s, "POST", base+"/review", empty, &view); code != http.StatusOK {
+		t.Fatalf("review status = %d, want 200", code)
+	}
+	if view.State != "question" || view.Question.ReviewRound != 1 || view.Question.Total != 1 {
+		t.Errorf("view = %+v, want review round 1 with one question", view)
+	}
+
+	// Every answer is recorded
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].HintsUsed != 1 {
+		t.Errorf("Expected 2 recorded attempts, the first with a hint, got %d", len(attempts))
+	}
+
+	if code := do(t, s, "GET", "/api/sessions/unknown", nil, nil); code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", code)
+	}
+}
+
+func TestChoiceSessionHidesAnswer(t *testing.T) {
+	s, _ := setupServer(t)
+
+	var view sessionResponse
+	body := sessionRequest{Config: &models.SessionConfig{Mode: "choice", QuestionCount: 1, TimeLimit: 10}}
+	if code := do(t, s, "POST", "/api/sessions", body, &view); code != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201", code)
+	}
+	if len(view.Question.Choices) < 2 {
+		t.Errorf("choices = %v, want several options", view.Question.Choices)
+	}
+	if view.Question.TimeLeftMs <= 0 || view.Question.TimeLeftMs > 10000 {
+		t.Errorf("time_left_ms = %d, want the countdown", view.Question.TimeLeftMs)
+	}
+	if view.Result != nil {
+		t.Error("Expected no result before answering")
+	}
+}
+
+func TestSessionValidation(t *testing.T) {
+	s, _ := setupServer(t)
+
+	tests := []struct {
+		name string
+		body sessionRequest
+		want int
+	}{
+		{"no config", sessionRequest{}, http.StatusBadRequest},
+		{"reverse mode", sessionRequest{Config: &models.SessionConfig{Mode: "reverse"}}, http.StatusBadRequest},
+		{"table mode", sessionRequest{Config: &models.SessionConfig{Mode: "table"}}, http.StatusBadRequest},
+		{"bad difficulty", sessionRequest{Config: &models.SessionConfig{DifficultyLevel: "expert"}}, http.StatusBadRequest},
+		{"negative count", sessionRequest{Config: &models.SessionConfig{QuestionCount: -1}}, http.StatusBadRequest},
+		{"bad filter", sessionRequest{Config: &models.SessionConfig{Filter: models.SessionFilter{Cases: []string{"dative"}}}}, http.StatusBadRequest},
+		{"no matches", sessionRequest{Config: &models.SessionConfig{Filter: models.SessionFilter{Cases: []string{"genitive"}}}}, http.StatusNotFound},
+		{"nothing to resume", sessionRequest{Resume: true}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			if code := do(t, s, "POST", "/api/sessions", tt.body, &resp); code != tt.want {
+				t.Errorf("status = %d (%s), want %d", code, resp.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeSession(t *testing.T) {
+	s, _ := setupServer(t)
+
+	if code := do(t, s, "GET", "/api/sessions/saved", nil, nil); code != http.StatusNotFound {
+		t.Fatalf("saved status = %d, want 404 before any answer", code)
+	}
+
+	var view sessionResponse
+	do(t, s, "POST", "/api/sessions", sessionRequest{Config: &models.SessionConfig{QuestionCount: 2}}, &view)
+	do(t, s, "POST", "/api/sessions/"+view.ID+"/answers", sessionAnswerRequest{Answer: "λάθος"}, &view)
+
+	var saved savedSessionResponse
+	if code := do(t, s, "GET", "/api/sessions/saved", nil, &saved); code != http.StatusOK {
+		t.Fatalf("saved status = %d, want 200", code)
+	}
+	if saved.Answered != 1 || saved.Config.QuestionCount != 2 {
+		t.Errorf("saved = %+v, want 1 of 2 answered", saved)
+	}
+
+	var resumed sessionResponse
+	if code := do(t, s, "POST", "/api/sessions", sessionRequest{Resume: true}, &resumed); code != http.StatusCreated {
+		t.Fatalf("resume status = %d, want 201", code)
+	}
+	if resumed.ID == view.ID || resumed.State != "question" || resumed.Question.Number != 2 {
+		t.Errorf("resumed = %+v, want a new session at question 2", resumed)
+	}
+}
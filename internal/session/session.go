@@ -0,0 +1,563 @@
+// Package session runs practice sessions for both the terminal and browser UIs
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// States of a session
+const (
+	StateQuestion = "question"
+	StateFeedback = "feedback"
+	StateComplete = "complete"
+)
+
+// Expiry is how long an interrupted session can be resumed
+const Expiry = 24 * time.Hour
+
+var (
+	// ErrNoSentences is returned when no sentences match a session's settings
+	ErrNoSentences = errors.New("no sentences found for these session settings. Please run 'greekmaster import' first or widen the filters")
+
+	// ErrInvalidState is returned for actions the session can't take in its current state
+	ErrInvalidState = errors.New("not possible at this point of the session")
+)
+
+// Session asks a session's sentences in turn, grades and records the answers,
+// runs review rounds of the mistakes and keeps the score. The UIs render its
+// state and pass the learner's input on.
+type Session struct {
+	repo           storage.Repository
+	config         models.SessionConfig
+	sentences      []*models.Sentence
+	index          int
+	state          string
+	correctCount   int
+	incorrectCount int
+	score          float64 // Sum of item scores, reduced by hints
+	totalHints     int
+	mistakes       []*models.Sentence // Sentences answered incorrectly in the current round
+	slowCount      int                // Slow but correct answers queued for review with the mistakes
+	reviewRound    int                // 0 for the main session, then 1, 2, ... for review rounds
+	mainSentences  []*models.Sentence // Main session sentences, kept while reviewing
+	answerTime     time.Duration      // Time spent answering main session questions
+	sprintEnd      time.Time          // When the sprint ends; zero when no sprint is running
+	sentence       *models.Sentence   // The current question
+	noun           *models.Noun
+	choices        []string // Options offered in choice mode
+	hints          []string // Progressive hints for the current sentence
+	hintsUsed      int      // Hints revealed for the current sentence
+	questionStart  time.Time
+	result         *Result // Grade of the last answer
+	rng            *rand.Rand
+	now            func() time.Time
+}
+
+// Result is a graded answer
+type Result struct {
+	Answer      string
+	TimedOut    bool // The question's countdown ran out
+	Correct     bool
+	HintsUsed   int
+	Score       float64
+	Latency     time.Duration
+	Slow        bool // Correct but slow, so queued for review
+	Sentence    *models.Sentence
+	Explanation *models.Explanation
+
+	// Identification grades each part of a reverse mode answer
+	Identification grading.IdentificationResult
+}
+
+// Progress locates the current question in the session
+type Progress struct {
+	Question    int // 1-based position in the round
+	Total       int // Questions in the round
+	Endless     bool
+	ReviewRound int
+}
+
+// Summary is the outcome of the main session shown on completion
+type Summary struct {
+	Answered     int
+	Length       int // Questions in the main session, or those answered in an endless one
+	Correct      int
+	Accuracy     int // Percentage of answers that were correct
+	Score        float64
+	TotalHints   int
+	AnswerTime   time.Duration
+	ReviewRounds int
+	Missed       int // Mistakes queued for the next review round
+	Slow         int // Slow answers queued for the next review round
+}
+
+// LoadSentences generates the shuffled sentences for a session and the random
+// number generator used to reshuffle them
+func LoadSentences(repo storage.Repository, config models.SessionConfig) ([]*models.Sentence, *rand.Rand, error) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	sentences, err := storage.GenerateSessionSentences(repo, config, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sentences) == 0 {
+		return nil, nil, ErrNoSentences
+	}
+
+	return sentences, rng, nil
+}
+
+// New starts a session with freshly generated sentences
+func New(repo storage.Repository, config models.SessionConfig) (*Session, error) {
+	sentences, rng, err := LoadSentences(repo, config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		repo:      repo,
+		config:    config,
+		sentences: sentences,
+		rng:       rng,
+		now:       time.Now,
+	}
+	if config.SprintSeconds > 0 {
+		s.sprintEnd = s.now().Add(time.Duration(config.SprintSeconds) * time.Second)
+	}
+
+	return s, s.ask()
+}
+
+// Resume continues a session saved by an earlier run
+func Resume(repo storage.Repository, saved *models.SavedSession) (*Session, error) {
+	if len(saved.Sentences) == 0 {
+		return nil, fmt.Errorf("saved session has no questions")
+	}
+
+	s := &Session{
+		repo:           repo,
+		config:         saved.Config,
+		sentences:      saved.Sentences,
+		index:          saved.CurrentIndex,
+		correctCount:   saved.CorrectCount,
+		incorrectCount: saved.IncorrectCount,
+		score:          saved.Score,
+		totalHints:     saved.TotalHints,
+		mistakes:       saved.Mistakes,
+		slowCount:      saved.SlowCount,
+		reviewRound:    saved.ReviewRound,
+		mainSentences:  saved.MainSentences,
+		answerTime:     saved.AnswerTime,
+		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:            time.Now,
+	}
+	if saved.SprintLeft > 0 {
+		s.sprintEnd = s.now().Add(saved.SprintLeft)
+	}
+
+	// The session may have been saved on the feedback screen of its last question
+	return s, s.advance()
+}
+
+// LoadSaved returns the current profile's interrupted session, or nil if there
+// is none. An expired session is discarded.
+func LoadSaved(repo storage.Repository) (*models.SavedSession, error) {
+	saved, err := repo.GetSavedSession()
+	if err != nil || saved == nil {
+		return nil, err
+	}
+	if time.Since(saved.UpdatedAt) > Expiry {
+		return nil, repo.DeleteSavedSession()
+	}
+	return saved, nil
+}
+
+// Config returns the session's settings
+func (s *Session) Config() models.SessionConfig {
+	return s.config
+}
+
+// State returns StateQuestion, StateFeedback or StateComplete
+func (s *Session) State() string {
+	return s.state
+}
+
+// Sentence returns the current question
+func (s *Session) Sentence() *models.Sentence {
+	return s.sentence
+}
+
+// Noun returns the current question's noun, or nil if it couldn't be loaded
+func (s *Session) Noun() *models.Noun {
+	return s.noun
+}
+
+// Choices returns the options offered for the current question in choice mode
+func (s *Session) Choices() []string {
+	return s.choices
+}
+
+// Hints returns the hints revealed for the current question
+func (s *Session) Hints() []string {
+	return s.hints[:s.hintsUsed]
+}
+
+// HintsLeft returns how many hints can still be revealed
+func (s *Session) HintsLeft() int {
+	return len(s.hints) - s.hintsUsed
+}
+
+// Result returns the grade of the last answer
+func (s *Session) Result() *Result {
+	return s.result
+}
+
+// Progress locates the current question in the session
+func (s *Session) Progress() Progress {
+	return Progress{
+		Question:    s.index + 1,
+		Total:       len(s.sentences),
+		Endless:     s.config.QuestionCount == 0,
+		ReviewRound: s.reviewRound,
+	}
+}
+
+// QuestionDeadline returns when the current question's countdown runs out, or
+// the zero time when there is no countdown
+func (s *Session) QuestionDeadline() time.Time {
+	if s.config.TimeLimit <= 0 {
+		return time.Time{}
+	}
+	return s.questionStart.Add(s.timeLimit())
+}
+
+// SprintEnd returns when the running sprint ends, or the zero time when no sprint is running
+func (s *Session) SprintEnd() time.Time {
+	return s.sprintEnd
+}
+
+// QuestionTimedOut reports whether the current question's countdown has run out
+func (s *Session) QuestionTimedOut() bool {
+	deadline := s.QuestionDeadline()
+	return s.state == StateQuestion && !deadline.IsZero() && !s.now().Before(deadline)
+}
+
+// SprintOver reports whether the running sprint's time is up
+func (s *Session) SprintOver() bool {
+	return !s.sprintEnd.IsZero() && !s.now().Before(s.sprintEnd) && s.state != StateComplete
+}
+
+// RevealHint reveals the next hint for the current question, reporting whether there was one
+func (s *Session) RevealHint() bool {
+	if s.state != StateQuestion || s.HintsLeft() == 0 {
+		return false
+	}
+	s.hintsUsed++
+	return true
+}
+
+// Submit grades an answer to the current question, records the attempt and saves
+// the session for resuming. A failed write doesn't interrupt practice: the result
+// is returned along with the error.
+func (s *Session) Submit(answer string) (*Result, error) {
+	if s.state != StateQuestion {
+		return nil, ErrInvalidState
+	}
+	return s.record(answer, s.sentence.CorrectAnswer, grading.Grade(answer, s.sentence))
+}
+
+// SubmitIdentification grades a reverse mode identification of the current
+// question's noun phrase, recording and saving it like Submit
+func (s *Session) SubmitIdentification(id grading.Identification) (*Result, error) {
+	if s.state != StateQuestion || s.noun == nil {
+		return nil, ErrInvalidState
+	}
+
+	grade := grading.GradeIdentification(id, s.sentence, s.noun)
+	expected := grading.ExpectedIdentification(s.sentence, s.noun)
+	result, err := s.record(id.String(), expected.String(), grade.Correct())
+	result.Identification = grade
+	return result, err
+}
+
+// record scores a graded answer to the current question, stores the attempt and
+// moves to the feedback. The result is returned even when a write fails.
+func (s *Session) record(answer, correctAnswer string, correct bool) (*Result, error) {
+	latency := s.now().Sub(s.questionStart)
+	result := &Result{
+		Answer:    answer,
+		TimedOut:  s.config.TimeLimit > 0 && latency >= s.timeLimit(),
+		Correct:   correct,
+		HintsUsed: s.hintsUsed,
+		Score:     grading.Score(correct, s.hintsUsed),
+		Latency:   latency,
+		Sentence:  s.sentence,
+	}
+
+	// In timed sessions, correct answers that took too long are reviewed too
+	result.Slow = correct && s.reviewRound == 0 && s.config.IsTimed() &&
+		grading.IsSlow(latency, s.timeLimit())
+	if !correct || result.Slow {
+		s.mistakes = append(s.mistakes, s.sentence)
+	}
+	if result.Slow {
+		s.slowCount++
+	}
+
+	// Review rounds don't change the session's score
+	if s.reviewRound == 0 {
+		if correct {
+			s.correctCount++
+		} else {
+			s.incorrectCount++
+		}
+		s.score += result.Score
+		s.totalHints += s.hintsUsed
+		s.answerTime += latency
+	}
+
+	var errs []error
+	attempt := &models.Attempt{
+		NounID:        s.sentence.NounID,
+		Mode:          s.config.Mode,
+		CaseType:      s.sentence.CaseType,
+		Number:        s.sentence.Number,
+		ContextType:   s.sentence.ContextType,
+		UserAnswer:    answer,
+		CorrectAnswer: correctAnswer,
+		Correct:       correct,
+		HintsUsed:     s.hintsUsed,
+		Score:         result.Score,
+		LatencyMs:     latency.Milliseconds(),
+		Slow:          result.Slow,
+	}
+	if s.sentence.TemplateID != 0 {
+		templateID := s.sentence.TemplateID
+		attempt.TemplateID = &templateID
+	}
+	if err := s.repo.RecordAttempt(attempt); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s.noun != nil {
+		explanation, err := explanations.Generate(s.sentence, s.noun)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.Explanation = explanation
+	}
+
+	s.result = result
+	s.state = StateFeedback
+	if err := s.Save(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// Next moves from an answer's feedback to the next question, or ends the round
+func (s *Session) Next() error {
+	if s.state != StateFeedback {
+		return ErrInvalidState
+	}
+	s.index++
+	return s.advance()
+}
+
+// CanReview reports whether the finished session has mistakes to review
+func (s *Session) CanReview() bool {
+	return s.state == StateComplete && len(s.mistakes) > 0
+}
+
+// Review starts a review round of the finished session's mistakes
+func (s *Session) Review() error {
+	if !s.CanReview() {
+		return ErrInvalidState
+	}
+	// Review rounds keep the countdown but not the sprint clock
+	s.sprintEnd = time.Time{}
+	return s.startReview()
+}
+
+// Restart asks the main session's sentences again in a new order with a fresh score
+func (s *Session) Restart() error {
+	if s.reviewRound > 0 {
+		s.sentences = s.mainSentences
+		s.reviewRound = 0
+	}
+	s.mistakes = nil
+	s.slowCount = 0
+	s.answerTime = 0
+	s.index = 0
+	s.correctCount = 0
+	s.incorrectCount = 0
+	s.score = 0
+	s.totalHints = 0
+	s.rng.Shuffle(len(s.sentences), func(i, j int) {
+		s.sentences[i], s.sentences[j] = s.sentences[j], s.sentences[i]
+	})
+	if s.config.SprintSeconds > 0 {
+		s.sprintEnd = s.now().Add(time.Duration(s.config.SprintSeconds) * time.Second)
+	}
+	return s.ask()
+}
+
+// Finish ends the session, which no longer needs to be resumed
+func (s *Session) Finish() error {
+	s.state = StateComplete
+	s.sprintEnd = time.Time{}
+	return s.repo.DeleteSavedSession()
+}
+
+// Save stores the session so a later run can resume it
+func (s *Session) Save() error {
+	return s.repo.SaveSession(s.snapshot())
+}
+
+// Summary returns the outcome of the main session
+func (s *Session) Summary() Summary {
+	summary := Summary{
+		Answered:     s.correctCount + s.incorrectCount,
+		Length:       len(s.sentences),
+		Correct:      s.correctCount,
+		Score:        s.score,
+		TotalHints:   s.totalHints,
+		AnswerTime:   s.answerTime,
+		ReviewRounds: s.reviewRound,
+		Missed:       len(s.mistakes) - s.slowCount,
+		Slow:         s.slowCount,
+	}
+	if s.reviewRound > 0 {
+		summary.Length = len(s.mainSentences)
+	}
+	if s.config.QuestionCount == 0 {
+		// Endless sessions are as long as the learner keeps going
+		summary.Length = summary.Answered
+	}
+	if summary.Answered > 0 {
+		summary.Accuracy = (summary.Correct * 100) / summary.Answered
+	}
+	return summary
+}
+
+// snapshot captures the session so it can be resumed later
+func (s *Session) snapshot() *models.SavedSession {
+	index := s.index
+	if s.state == StateFeedback {
+		// The question on screen has been answered
+		index++
+	}
+
+	saved := &models.SavedSession{
+		Config:         s.config,
+		Sentences:      s.sentences,
+		CurrentIndex:   index,
+		CorrectCount:   s.correctCount,
+		IncorrectCount: s.incorrectCount,
+		Score:          s.score,
+		TotalHints:     s.totalHints,
+		Mistakes:       s.mistakes,
+		SlowCount:      s.slowCount,
+		ReviewRound:    s.reviewRound,
+		MainSentences:  s.mainSentences,
+		AnswerTime:     s.answerTime,
+	}
+	if !s.sprintEnd.IsZero() {
+		saved.SprintLeft = max(s.sprintEnd.Sub(s.now()), time.Second)
+	}
+	return saved
+}
+
+// advance asks the question at index, or ends the round when every question
+// has been asked
+func (s *Session) advance() error {
+	// Review rounds repeat until every mistake is corrected
+	if s.reviewRound > 0 && s.index >= len(s.sentences) {
+		if len(s.mistakes) > 0 {
+			return s.startReview()
+		}
+		return s.Finish()
+	}
+	if s.config.QuestionCount > 0 && s.index >= len(s.sentences) {
+		return s.Finish()
+	}
+	if s.index >= len(s.sentences) {
+		// Endless mode - reshuffle and continue
+		s.rng.Shuffle(len(s.sentences), func(i, j int) {
+			s.sentences[i], s.sentences[j] = s.sentences[j], s.sentences[i]
+		})
+		s.index = 0
+	}
+	return s.ask()
+}
+
+// startReview re-asks the sentences answered incorrectly in the last round, each
+// with a different template for the same noun, case and number where one within
+// the session's settings exists
+func (s *Session) startReview() error {
+	if s.reviewRound == 0 {
+		s.mainSentences = s.sentences
+	}
+	s.reviewRound++
+
+	review := make([]*models.Sentence, 0, len(s.mistakes))
+	for _, missed := range s.mistakes {
+		alternative, err := s.repo.GenerateAlternativeSentence(missed, s.config)
+		if err != nil || alternative == nil {
+			alternative = missed
+		}
+		review = append(review, alternative)
+	}
+
+	s.mistakes = nil
+	s.slowCount = 0
+	s.sentences = review
+	s.index = 0
+	return s.ask()
+}
+
+// ask shows the sentence at index, preparing its hints and choices. A sentence
+// whose noun can't be loaded is skipped.
+func (s *Session) ask() error {
+	s.sentence = s.sentences[s.index]
+	s.state = StateQuestion
+	s.result = nil
+	s.choices = nil
+	s.hints = nil
+	s.hintsUsed = 0
+	s.questionStart = s.now()
+
+	noun, err := s.repo.GetNoun(s.sentence.NounID)
+	if err != nil {
+		// Without its noun a question can't be identified or explained, so it is
+		// dropped from the round rather than leaving the learner stuck on it
+		s.noun = nil
+		s.sentences = append(s.sentences[:s.index:s.index], s.sentences[s.index+1:]...)
+		skipped := fmt.Errorf("skipped a question: %w", err)
+		if len(s.sentences) == 0 {
+			return errors.Join(skipped, s.Finish())
+		}
+		return errors.Join(skipped, s.advance())
+	}
+	s.noun = noun
+	s.hints = explanations.Hints(s.sentence, noun)
+
+	if s.config.Mode == "choice" {
+		s.choices = grading.Choices(s.sentence, noun, s.rng)
+	}
+	return nil
+}
+
+// timeLimit returns the per-question countdown, or 0 when there is none
+func (s *Session) timeLimit() time.Duration {
+	return time.Duration(s.config.TimeLimit) * time.Second
+}
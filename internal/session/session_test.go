@@ -0,0 +1,334 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// setupRepo creates a database with two nouns and one template, giving two sentences
+func setupRepo(t *testing.T) *storage.SQLiteRepository {
+	t.Helper()
+
+	repo, err := storage.NewSQLiteRepository(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	nouns := []*models.Noun{
+		{
+			English: "teacher", Gender: "masculine",
+			NominativeSg: "δάσκαλος", GenitiveSg: "δασκάλου", AccusativeSg: "δάσκαλο",
+			NominativePl: "δάσκαλοι", GenitivePl: "δασκάλων", AccusativePl: "δασκάλους",
+			NomSgArticle: "ο", GenSgArticle: "του", AccSgArticle: "τον",
+			NomPlArticle: "οι", GenPlArticle: "των", AccPlArticle: "τους",
+		},
+		{
+			English: "book", Gender: "neuter",
+			NominativeSg: "βιβλίο", GenitiveSg: "βιβλίου", AccusativeSg: "βιβλίο",
+			NominativePl: "βιβλία", GenitivePl: "βιβλίων", AccusativePl: "βιβλία",
+			NomSgArticle: "το", GenSgArticle: "του", AccSgArticle: "το",
+			NomPlArticle: "τα", GenPlArticle: "των", AccPlArticle: "τα",
+		},
+	}
+	for _, noun := range nouns {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I see ___ (the {noun})",
+		GreekTemplate:   "Βλέπω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	return repo
+}
+
+// clock is a fake time source for timed sessions
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+// newSession starts a session whose time is controlled by the returned clock
+func newSession(t *testing.T, repo storage.Repository, config models.SessionConfig) (*Session, *clock) {
+	t.Helper()
+
+	c := &clock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s, err := New(repo, config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.now = c.Now
+	s.questionStart = c.now
+	if config.SprintSeconds > 0 {
+		s.sprintEnd = c.now.Add(time.Duration(config.SprintSeconds) * time.Second)
+	}
+	return s, c
+}
+
+// answer submits the correct answer, or a wrong one
+func answer(t *testing.T, s *Session, correct bool) *Result {
+	t.Helper()
+
+	input := "λάθος"
+	if correct {
+		input = s.Sentence().CorrectAnswer
+	}
+	result, err := s.Submit(input)
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	return result
+}
+
+func TestSessionWithReviewRound(t *testing.T) {
+	repo := setupRepo(t)
+	s, _ := newSession(t, repo, models.SessionConfig{Mode: "sentence", QuestionCount: 2})
+
+	if s.State() != StateQuestion {
+		t.Fatalf("State() = %s, want question", s.State())
+	}
+	if got := s.Progress(); got.Question != 1 || got.Total != 2 || got.Endless {
+		t.Errorf("Progress() = %+v, want question 1 of 2", got)
+	}
+
+	if !s.RevealHint() || len(s.Hints()) != 1 {
+		t.Fatalf("Expected one hint to be revealed, got %v", s.Hints())
+	}
+	result := answer(t, s, false)
+	if result.Correct || result.HintsUsed != 1 || result.Explanation == nil {
+		t.Errorf("Result = %+v, want an explained mistake with 1 hint", result)
+	}
+	if s.State() != StateFeedback {
+		t.Fatalf("State() = %s, want feedback", s.State())
+	}
+	if _, err := s.Submit("again"); err != ErrInvalidState {
+		t.Errorf("Submit() on feedback error = %v, want ErrInvalidState", err)
+	}
+
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	answer(t, s, true)
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	if s.State() != StateComplete || !s.CanReview() {
+		t.Fatalf("State() = %s, CanReview() = %v; want complete with a review", s.State(), s.CanReview())
+	}
+	summary := s.Summary()
+	if summary.Answered != 2 || summary.Correct != 1 || summary.Accuracy != 50 || summary.Missed != 1 || summary.TotalHints != 1 {
+		t.Errorf("Summary() = %+v, want 1 of 2 correct with 1 mistake and 1 hint", summary)
+	}
+
+	// The review round repeats the mistake without changing the score
+	if err := s.Review(); err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if got := s.Progress(); got.ReviewRound != 1 || got.Total != 1 {
+		t.Errorf("Progress() = %+v, want review round 1 with 1 question", got)
+	}
+	answer(t, s, true)
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	summary = s.Summary()
+	if s.State() != StateComplete || s.CanReview() {
+		t.Errorf("Expected the session to be complete with nothing left to review")
+	}
+	if summary.Answered != 2 || summary.Length != 2 || summary.ReviewRounds != 1 {
+		t.Errorf("Summary() = %+v, want 2 answered of 2 after 1 review round", summary)
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 3 {
+		t.Errorf("Expected 3 recorded attempts, got %d", len(attempts))
+	}
+	if saved, _ := repo.GetSavedSession(); saved != nil {
+		t.Error("Expected the finished session's saved state to be discarded")
+	}
+}
+
+func TestSessionEndlessReshuffles(t *testing.T) {
+	repo := setupRepo(t)
+	s, _ := newSession(t, repo, models.SessionConfig{Mode: "sentence"})
+
+	for i := 0; i < 3; i++ {
+		answer(t, s, true)
+		if err := s.Next(); err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+	}
+
+	if s.State() != StateQuestion {
+		t.Fatalf("State() = %s, want an endless session to keep asking", s.State())
+	}
+	if got := s.Progress(); got.Question != 2 || !got.Endless {
+		t.Errorf("Progress() = %+v, want question 2 of the reshuffled round", got)
+	}
+	if got := s.Summary(); got.Answered != 3 || got.Length != 3 {
+		t.Errorf("Summary() = %+v, want 3 of 3 answered", got)
+	}
+}
+
+func TestSessionTiming(t *testing.T) {
+	repo := setupRepo(t)
+	s, c := newSession(t, repo, models.SessionConfig{Mode: "choice", QuestionCount: 2, TimeLimit: 10})
+
+	if len(s.Choices()) < 2 {
+		t.Errorf("Choices() = %v, want options in choice mode", s.Choices())
+	}
+
+	// Correct after 80% of the countdown counts as slow and is reviewed
+	c.now = c.now.Add(8 * time.Second)
+	if s.QuestionTimedOut() {
+		t.Error("QuestionTimedOut() = true before the countdown ran out")
+	}
+	result := answer(t, s, true)
+	if !result.Slow || result.TimedOut || result.Latency != 8*time.Second {
+		t.Errorf("Result = %+v, want a slow answer after 8s", result)
+	}
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	c.now = c.now.Add(10 * time.Second)
+	if !s.QuestionTimedOut() {
+		t.Error("QuestionTimedOut() = false after the countdown ran out")
+	}
+	if result := answer(t, s, false); !result.TimedOut {
+		t.Errorf("Result = %+v, want a timed out answer", result)
+	}
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	if summary := s.Summary(); summary.Missed != 1 || summary.Slow != 1 {
+		t.Errorf("Summary() = %+v, want 1 mistake and 1 slow answer to review", summary)
+	}
+}
+
+func TestSessionSprint(t *testing.T) {
+	repo := setupRepo(t)
+	s, c := newSession(t, repo, models.SessionConfig{Mode: "sentence", SprintSeconds: 60})
+
+	if s.SprintOver() {
+		t.Fatal("SprintOver() = true at the start of the sprint")
+	}
+	c.now = c.now.Add(time.Minute)
+	if !s.SprintOver() {
+		t.Fatal("SprintOver() = false when the time is up")
+	}
+	if err := s.Finish(); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if s.State() != StateComplete || s.SprintOver() {
+		t.Errorf("Expected the sprint to end the session")
+	}
+}
+
+func TestResumeSavedSession(t *testing.T) {
+	repo := setupRepo(t)
+	s, _ := newSession(t, repo, models.SessionConfig{Mode: "sentence", QuestionCount: 2})
+	answer(t, s, false)
+
+	saved, err := LoadSaved(repo)
+	if err != nil || saved == nil {
+		t.Fatalf("LoadSaved() = %v, %v; want the saved session", saved, err)
+	}
+
+	resumed, err := Resume(repo, saved)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.State() != StateQuestion || resumed.Progress().Question != 2 {
+		t.Errorf("Resumed at %s, question %d; want question 2", resumed.State(), resumed.Progress().Question)
+	}
+	if summary := resumed.Summary(); summary.Answered != 1 || summary.Missed != 1 {
+		t.Errorf("Summary() = %+v, want the answered mistake kept", summary)
+	}
+}
+
+func TestSubmitIdentification(t *testing.T) {
+	repo := setupRepo(t)
+	s, _ := newSession(t, repo, models.SessionConfig{Mode: "reverse", QuestionCount: 2})
+
+	expected := grading.ExpectedIdentification(s.Sentence(), s.Noun())
+	wrong := expected
+	wrong.CaseType = "genitive"
+	result, err := s.SubmitIdentification(wrong)
+	if err != nil {
+		t.Fatalf("SubmitIdentification() error = %v", err)
+	}
+	if result.Correct || result.Identification.Case || !result.Identification.Gender {
+		t.Errorf("Result = %+v, want only the case marked wrong", result)
+	}
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	expected = grading.ExpectedIdentification(s.Sentence(), s.Noun())
+	if result, _ := s.SubmitIdentification(expected); !result.Correct {
+		t.Errorf("Result = %+v, want the expected identification graded correct", result)
+	}
+	if err := s.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if summary := s.Summary(); !s.CanReview() || summary.Missed != 1 || summary.Correct != 1 {
+		t.Errorf("Summary() = %+v, want 1 correct and 1 mistake to review", summary)
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].Mode != "reverse" || attempts[0].UserAnswer != wrong.String() {
+		t.Errorf("Expected 2 recorded reverse attempts, the first %q, got %+v", wrong.String(), attempts)
+	}
+}
+
+func TestSessionSkipsMissingNoun(t *testing.T) {
+	repo := setupRepo(t)
+	s, _ := newSession(t, repo, models.SessionConfig{Mode: "reverse", QuestionCount: 2})
+
+	expected := grading.ExpectedIdentification(s.Sentence(), s.Noun())
+	if _, err := s.SubmitIdentification(expected); err != nil {
+		t.Fatalf("SubmitIdentification() error = %v", err)
+	}
+
+	// The other question's noun can't be found
+	s.sentences[1].NounID = 999
+
+	if err := s.Next(); err == nil {
+		t.Error("Next() error = nil, want the skipped question reported")
+	}
+	if s.State() != StateComplete {
+		t.Errorf("State() = %s, want the session to end after skipping its last question", s.State())
+	}
+	if summary := s.Summary(); summary.Answered != 1 || summary.Length != 1 {
+		t.Errorf("Summary() = %+v, want 1 of 1 answered", summary)
+	}
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// RecordAttempt inserts an answered practice item into the attempt history
+func (r *SQLiteRepository) RecordAttempt(attempt *models.Attempt) error {
+	attempt.ProfileID = r.profileID
+	query := `
+		INSERT INTO attempts (
+			profile_id, noun_id, template_id, mode, case_type, number, context_type,
+			user_answer, correct_answer, correct, hints_used, score, latency_ms, slow
+		) VALUES (
+			:profile_id, :noun_id, :template_id, :mode, :case_type, :number, :context_type,
+			:user_answer, :correct_answer, :correct, :hints_used, :score, :latency_ms, :slow
+		)
+	`
+	result, err := r.db.NamedExec(query, attempt)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	attempt.ID = id
+	return nil
+}
+
+// ListAttempts retrieves the current profile's attempt history, oldest first
+func (r *SQLiteRepository) ListAttempts() ([]*models.Attempt, error) {
+	var attempts []*models.Attempt
+	query := "SELECT * FROM attempts WHERE profile_id = ? ORDER BY id"
+	if err := r.db.Select(&attempts, query, r.profileID); err != nil {
+		return nil, fmt.Errorf("failed to list attempts: %w", err)
+	}
+	return attempts, nil
+}
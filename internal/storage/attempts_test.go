@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestRecordAndListAttempts(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{
+		English:      "teacher",
+		Gender:       "masculine",
+		NominativeSg: "δάσκαλος",
+		GenitiveSg:   "δασκάλου",
+		AccusativeSg: "δάσκαλο",
+		NominativePl: "δάσκαλοι",
+		GenitivePl:   "δασκάλων",
+		AccusativePl: "δασκάλους",
+		NomSgArticle: "ο",
+		GenSgArticle: "του",
+		AccSgArticle: "τον",
+		NomPlArticle: "οι",
+		GenPlArticle: "των",
+		AccPlArticle: "τους",
+	}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I see ___ (the {noun})",
+		GreekTemplate:   "Βλέπω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	attempt := &models.Attempt{
+		NounID:        noun.ID,
+		TemplateID:    &template.ID,
+		Mode:          "sentence",
+		CaseType:      "accusative",
+		Number:        "singular",
+		ContextType:   "direct_object",
+		UserAnswer:    "τον δάσκαλο",
+		CorrectAnswer: "τον δάσκαλο",
+		Correct:       true,
+		HintsUsed:     2,
+		Score:         0.5,
+		LatencyMs:     4200,
+		Slow:          true,
+	}
+	if err := repo.RecordAttempt(attempt); err != nil {
+		t.Fatalf("RecordAttempt() error = %v", err)
+	}
+	if attempt.ID == 0 {
+		t.Error("Expected attempt ID to be set after recording")
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", len(attempts))
+	}
+
+	got := attempts[0]
+	if !got.Correct || got.HintsUsed != 2 || got.Score != 0.5 {
+		t.Errorf("Attempt = correct %v, hints %d, score %v; want true, 2, 0.5", got.Correct, got.HintsUsed, got.Score)
+	}
+	if got.LatencyMs != 4200 || !got.Slow {
+		t.Errorf("Attempt timing = %dms, slow %v; want 4200ms, true", got.LatencyMs, got.Slow)
+	}
+	if got.TemplateID == nil || *got.TemplateID != template.ID {
+		t.Errorf("Expected TemplateID %d, got %v", template.ID, got.TemplateID)
+	}
+}
+
+func TestMoveLegacyAttemptTimings(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{English: "house", Gender: "neuter", NominativeSg: "σπίτι"}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+	attempt := &models.Attempt{NounID: noun.ID, Mode: "table", CaseType: "nominative", Number: "singular"}
+	if err := repo.RecordAttempt(attempt); err != nil {
+		t.Fatalf("RecordAttempt() error = %v", err)
+	}
+
+	// Earlier versions kept timings in a side table
+	if _, err := repo.db.Exec("CREATE TABLE attempt_timings (attempt_id INTEGER PRIMARY KEY, latency_ms INTEGER NOT NULL, slow INTEGER NOT NULL)"); err != nil {
+		t.Fatalf("create attempt_timings: %v", err)
+	}
+	if _, err := repo.db.Exec("INSERT INTO attempt_timings (attempt_id, latency_ms, slow) VALUES (?, 3100, 1)", attempt.ID); err != nil {
+		t.Fatalf("insert timing: %v", err)
+	}
+
+	if err := RunMigrations(repo.db); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+
+	attempts, err := repo.ListAttempts()
+	if err != nil {
+		t.Fatalf("ListAttempts() error = %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].LatencyMs != 3100 || !attempts[0].Slow {
+		t.Fatalf("Attempts = %+v, want the legacy timing of 3100ms, slow", attempts)
+	}
+
+	var tables int
+	if err := repo.db.Get(&tables, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'attempt_timings'"); err != nil {
+		t.Fatalf("inspect tables: %v", err)
+	}
+	if tables != 0 {
+		t.Error("Expected the attempt_timings table to be dropped")
+	}
+}
@@ -0,0 +1,267 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// backupPrefix and backupTimeLayout name snapshot files, e.g.
+// greekmaster-20261018-210123-pre-import.db
+const (
+	backupPrefix     = "greekmaster-"
+	backupTimeLayout = "20060102-150405"
+)
+
+// Backup describes a database snapshot file
+type Backup struct {
+	Name      string    // File name
+	Path      string    // Full path
+	Reason    string    // Why the snapshot was taken, e.g. "manual" or "pre-import"
+	CreatedAt time.Time // When the snapshot was taken
+	Size      int64     // File size in bytes
+}
+
+// BackupDir returns the directory holding snapshots of the database at dbPath
+func BackupDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// Path returns the database file the repository was opened with
+func (r *SQLiteRepository) Path() string {
+	return r.path
+}
+
+// BackupTo writes a consistent copy of the database to path, which must not exist
+func (r *SQLiteRepository) BackupTo(path string) error {
+	return vacuumInto(r.db, path)
+}
+
+// vacuumInto copies a live database to a new file with VACUUM INTO
+func vacuumInto(db *sqlx.DB, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup file %s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if _, err := db.Exec("VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// Snapshot saves a timestamped copy of the database in its backup directory
+func (r *SQLiteRepository) Snapshot(reason string) (*Backup, error) {
+	return snapshot(r.db, r.path, reason)
+}
+
+// snapshot saves a timestamped copy of the database at dbPath
+func snapshot(db *sqlx.DB, dbPath, reason string) (*Backup, error) {
+	if dbPath == "" || dbPath == ":memory:" {
+		return nil, errors.New("cannot snapshot an in-memory database")
+	}
+
+	dir := BackupDir(dbPath)
+	now := time.Now()
+	base := backupPrefix + now.Format(backupTimeLayout) + "-" + reason
+	path := filepath.Join(dir, base+".db")
+	for n := 2; fileExists(path); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.db", base, n))
+	}
+
+	if err := vacuumInto(db, path); err != nil {
+		return nil, err
+	}
+
+	backup, err := readBackup(path)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Saved database snapshot", "path", path, "reason", reason)
+	return backup, nil
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// readBackup describes a snapshot file from its name and size
+func readBackup(path string) (*Backup, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	name := filepath.Base(path)
+	backup := &Backup{Name: name, Path: path, CreatedAt: info.ModTime(), Size: info.Size()}
+
+	// greekmaster-<date>-<time>-<reason>.db
+	rest, ok := strings.CutPrefix(strings.TrimSuffix(name, ".db"), backupPrefix)
+	if ok && len(rest) >= len(backupTimeLayout) {
+		if created, err := time.ParseInLocation(backupTimeLayout, rest[:len(backupTimeLayout)], time.Local); err == nil {
+			backup.CreatedAt = created
+			backup.Reason = strings.TrimPrefix(rest[len(backupTimeLayout):], "-")
+		}
+	}
+
+	return backup, nil
+}
+
+// ListBackups lists the snapshots in dir, newest first. A missing directory has none.
+func ListBackups(dir string) ([]*Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), backupPrefix) || filepath.Ext(entry.Name()) != ".db" {
+			continue
+		}
+		backup, err := readBackup(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, backup)
+	}
+
+	slices.SortFunc(backups, func(a, b *Backup) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.Name, a.Name)
+	})
+	return backups, nil
+}
+
+// PruneBackups deletes all but the newest keep snapshots in dir and returns the
+// deleted ones
+func PruneBackups(dir string, keep int) ([]*Backup, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+	if keep < 0 || len(backups) <= keep {
+		return nil, nil
+	}
+
+	removed := backups[keep:]
+	for _, backup := range removed {
+		if err := os.Remove(backup.Path); err != nil {
+			return nil, fmt.Errorf("failed to delete old backup: %w", err)
+		}
+		slog.Info("Deleted old database snapshot", "path", backup.Path)
+	}
+	return removed, nil
+}
+
+// RestoreBackup replaces the database at dbPath with a backup after checking that
+// the backup is an intact greekmaster database. The database must be closed.
+func RestoreBackup(backupPath, dbPath string) error {
+	if err := checkBackup(backupPath); err != nil {
+		return err
+	}
+
+	src, err := os.Open(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer src.Close()
+
+	// Copy next to the database, then swap it in so a failed copy leaves it untouched
+	tmp, err := os.CreateTemp(filepath.Dir(dbPath), ".restore-*.db")
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		os.Remove(dbPath + suffix)
+	}
+	if err := os.Rename(tmp.Name(), dbPath); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	slog.Info("Restored database from backup", "backup", backupPath, "database", dbPath)
+	return nil
+}
+
+// checkBackup verifies that a file is an intact SQLite database with a noun catalog
+func checkBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup %s not found", path)
+	}
+
+	db, err := sqlx.Connect("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.Get(&result, "PRAGMA integrity_check"); err != nil {
+		return fmt.Errorf("%s is not a valid database: %w", path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%s is corrupt: %s", path, result)
+	}
+
+	var tables int
+	if err := db.Get(&tables, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'nouns'"); err != nil {
+		return fmt.Errorf("failed to inspect backup: %w", err)
+	}
+	if tables == 0 {
+		return fmt.Errorf("%s is not a greekmaster database", path)
+	}
+
+	return nil
+}
+
+// needsDestructiveMigration reports whether the pending migrations drop or rebuild
+// tables holding data: the explanations and attempt_timings tables from earlier
+// versions, and presets from before profiles
+func needsDestructiveMigration(db *sqlx.DB) (bool, error) {
+	for _, table := range []string{"explanations", "attempt_timings", "session_presets"} {
+		var exists int
+		if err := db.Get(&exists, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table); err != nil {
+			return false, fmt.Errorf("failed to inspect database: %w", err)
+		}
+		if exists == 0 {
+			continue
+		}
+		if table != "session_presets" {
+			return true, nil
+		}
+		scoped, err := hasColumn(db, table, "profile_id")
+		if err != nil {
+			return false, err
+		}
+		if !scoped {
+			return true, nil
+		}
+	}
+	return false, nil
+}
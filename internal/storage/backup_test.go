@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// setupFileDB creates a repository backed by a file, with one noun
+func setupFileDB(t *testing.T) *SQLiteRepository {
+	t.Helper()
+
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "greekmaster.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	if err := repo.CreateNoun(&models.Noun{English: "teacher", Gender: "masculine", NominativeSg: "δάσκαλος"}); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+	return repo
+}
+
+func TestSnapshotAndList(t *testing.T) {
+	repo := setupFileDB(t)
+
+	first, err := repo.Snapshot("manual")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	second, err := repo.Snapshot("pre-import")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	if filepath.Dir(first.Path) != BackupDir(repo.Path()) {
+		t.Errorf("snapshot saved in %s, want %s", filepath.Dir(first.Path), BackupDir(repo.Path()))
+	}
+	if first.Reason != "manual" || !strings.HasPrefix(first.Name, "greekmaster-") {
+		t.Errorf("snapshot = %+v, want a timestamped manual snapshot", first)
+	}
+	if time.Since(first.CreatedAt) > time.Minute {
+		t.Errorf("CreatedAt = %v, want the time in the name", first.CreatedAt)
+	}
+
+	backups, err := ListBackups(BackupDir(repo.Path()))
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("ListBackups() returned %d backups, want 2", len(backups))
+	}
+	reasons := backups[0].Reason + "," + backups[1].Reason
+	if reasons != "pre-import,manual" && reasons != "manual,pre-import" {
+		t.Errorf("backup reasons = %s, want manual and %s", reasons, second.Reason)
+	}
+	if backups[0].Size == 0 {
+		t.Error("backup size should be recorded")
+	}
+
+	// Snapshots taken in the same second with the same reason get distinct names
+	third, err := repo.Snapshot("manual")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if third.Path == first.Path {
+		t.Error("a second snapshot should not overwrite the first")
+	}
+}
+
+func TestListBackupsMissingDir(t *testing.T) {
+	backups, err := ListBackups(filepath.Join(t.TempDir(), "backups"))
+	if err != nil || len(backups) != 0 {
+		t.Errorf("ListBackups() = %v, %v, want no backups", backups, err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"greekmaster-20260101-090000-manual.db",
+		"greekmaster-20260102-090000-pre-import.db",
+		"greekmaster-20260103-090000-manual.db",
+		"notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := PruneBackups(dir, 2)
+	if err != nil {
+		t.Fatalf("PruneBackups() error = %v", err)
+	}
+	if len(removed) != 1 || removed[0].Name != names[0] {
+		t.Errorf("PruneBackups() removed %v, want only the oldest", removed)
+	}
+
+	backups, _ := ListBackups(dir)
+	if len(backups) != 2 || backups[0].Name != names[2] || backups[1].Reason != "pre-import" {
+		t.Errorf("remaining backups = %v, want the two newest, newest first", backups)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("PruneBackups() should leave other files alone")
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	repo := setupFileDB(t)
+	dbPath := repo.Path()
+
+	backup, err := repo.Snapshot("manual")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if err := repo.CreateNoun(&models.Noun{English: "book", Gender: "neuter", NominativeSg: "βιβλίο"}); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+	repo.Close()
+
+	if err := RestoreBackup(backup.Path, dbPath); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	restored, err := NewSQLiteRepository(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	defer restored.Close()
+
+	nouns, err := restored.ListNouns()
+	if err != nil {
+		t.Fatalf("ListNouns() error = %v", err)
+	}
+	if len(nouns) != 1 || nouns[0].English != "teacher" {
+		t.Errorf("restored nouns = %v, want only the noun from before the backup", nouns)
+	}
+}
+
+func TestRestoreBackupRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "greekmaster.db")
+	if err := os.WriteFile(dbPath, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	garbage := filepath.Join(dir, "garbage.db")
+	if err := os.WriteFile(garbage, []byte("not a database at all, just some text"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	other := filepath.Join(dir, "other.db")
+	db, err := sqlx.Connect("sqlite3", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.MustExec("CREATE TABLE things (id INTEGER)")
+	db.Close()
+
+	for _, path := range []string{filepath.Join(dir, "missing.db"), garbage, other} {
+		if err := RestoreBackup(path, dbPath); err == nil {
+			t.Errorf("RestoreBackup(%s) should fail", filepath.Base(path))
+		}
+	}
+
+	if data, _ := os.ReadFile(dbPath); string(data) != "original" {
+		t.Error("a failed restore should leave the database untouched")
+	}
+}
+
+func TestSnapshotBeforeDestructiveMigration(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Presets from before profiles are rebuilt by migration 010
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := db.Exec(createPresets); err != nil {
+		t.Fatalf("create presets: %v", err)
+	}
+	db.Close()
+
+	repo, err := NewSQLiteRepository(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	repo.Close()
+
+	backups, err := ListBackups(BackupDir(dbPath))
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 1 || backups[0].Reason != "pre-migration" {
+		t.Fatalf("backups = %v, want one pre-migration snapshot", backups)
+	}
+
+	// Once migrated, opening the database takes no further snapshots
+	repo, err = NewSQLiteRepository(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	repo.Close()
+	if backups, _ := ListBackups(BackupDir(dbPath)); len(backups) != 1 {
+		t.Errorf("got %d backups after reopening, want 1", len(backups))
+	}
+}
+
+func TestSnapshotInMemory(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	if _, err := repo.Snapshot("manual"); err == nil {
+		t.Error("Snapshot() of an in-memory database should fail")
+	}
+}
@@ -10,7 +10,9 @@ import (
 type ImportCheckpoint struct {
 	ID               int64     `db:"id"`
 	CSVFilename      string    `db:"csv_filename"`
-	LastProcessedRow int       `db:"last_processed_row"`
+	CSVPath          string    `db:"csv_path"`           // Absolute path of the file when last imported
+	FileHash         string    `db:"file_hash"`          // SHA-256 of the file's content when last imported
+	LastProcessedRow int       `db:"last_processed_row"` // Rows processed so far, not a position in the file
 	Status           string    `db:"status"`
 	UpdatedAt        time.Time `db:"updated_at"`
 }
@@ -19,9 +21,9 @@ type ImportCheckpoint struct {
 func (r *SQLiteRepository) CreateCheckpoint(checkpoint *ImportCheckpoint) error {
 	query := `
 		INSERT INTO import_checkpoints (
-			csv_filename, last_processed_row, status
+			csv_filename, csv_path, file_hash, last_processed_row, status
 		) VALUES (
-			:csv_filename, :last_processed_row, :status
+			:csv_filename, :csv_path, :file_hash, :last_processed_row, :status
 		)
 	`
 	result, err := r.db.NamedExec(query, checkpoint)
@@ -41,7 +43,9 @@ func (r *SQLiteRepository) CreateCheckpoint(checkpoint *ImportCheckpoint) error
 func (r *SQLiteRepository) UpdateCheckpoint(checkpoint *ImportCheckpoint) error {
 	query := `
 		UPDATE import_checkpoints
-		SET last_processed_row = :last_processed_row,
+		SET csv_path = :csv_path,
+		    file_hash = :file_hash,
+		    last_processed_row = :last_processed_row,
 		    status = :status,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = :id
@@ -53,16 +57,31 @@ func (r *SQLiteRepository) UpdateCheckpoint(checkpoint *ImportCheckpoint) error
 	return nil
 }
 
-// GetCheckpointByFilename retrieves the most recent checkpoint for a CSV file
-func (r *SQLiteRepository) GetCheckpointByFilename(filename string) (*ImportCheckpoint, error) {
+// GetCheckpointByHash retrieves the most recent checkpoint for a file's content
+func (r *SQLiteRepository) GetCheckpointByHash(fileHash string) (*ImportCheckpoint, error) {
+	return r.getCheckpoint("file_hash", fileHash)
+}
+
+// GetCheckpointByPath retrieves the most recent checkpoint for a file's absolute
+// path, which finds the checkpoint of a file that has been edited since
+func (r *SQLiteRepository) GetCheckpointByPath(csvPath string) (*ImportCheckpoint, error) {
+	return r.getCheckpoint("csv_path", csvPath)
+}
+
+// getCheckpoint retrieves the most recent checkpoint whose column has a value
+func (r *SQLiteRepository) getCheckpoint(column, value string) (*ImportCheckpoint, error) {
+	if value == "" {
+		return nil, nil // Checkpoints made before hashing match nothing
+	}
+
 	var checkpoint ImportCheckpoint
-	query := `
+	query := fmt.Sprintf(`
 		SELECT * FROM import_checkpoints
-		WHERE csv_filename = ?
+		WHERE %s = ?
 		ORDER BY id DESC
 		LIMIT 1
-	`
-	err := r.db.Get(&checkpoint, query, filename)
+	`, column)
+	err := r.db.Get(&checkpoint, query, value)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No checkpoint found, not an error
@@ -71,3 +90,29 @@ func (r *SQLiteRepository) GetCheckpointByFilename(filename string) (*ImportChec
 	}
 	return &checkpoint, nil
 }
+
+// ListImportedRowHashes retrieves the hashes of the rows that a checkpoint's runs,
+// and the retries of those runs, have imported or found already imported
+func (r *SQLiteRepository) ListImportedRowHashes(checkpointID int64) (map[string]bool, error) {
+	var hashes []string
+	query := `
+		WITH RECURSIVE runs(id) AS (
+			SELECT id FROM import_runs WHERE checkpoint_id = ?
+			UNION
+			SELECT r.id FROM import_runs r JOIN runs ON r.retry_of = runs.id
+		)
+		SELECT DISTINCT row_hash FROM import_rows
+		WHERE run_id IN (SELECT id FROM runs)
+		  AND status IN ('imported', 'duplicate')
+		  AND row_hash != ''
+	`
+	if err := r.db.Select(&hashes, query, checkpointID); err != nil {
+		return nil, fmt.Errorf("failed to list imported rows: %w", err)
+	}
+
+	imported := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		imported[hash] = true
+	}
+	return imported, nil
+}
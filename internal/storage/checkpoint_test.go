@@ -0,0 +1,98 @@
+package storage
+
+import "testing"
+
+func TestGetCheckpointByHashAndPath(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	// Two files with the same name in different folders keep separate checkpoints
+	first := &ImportCheckpoint{CSVFilename: "nouns.csv", CSVPath: "/a/nouns.csv", FileHash: "aaa", Status: "in_progress"}
+	second := &ImportCheckpoint{CSVFilename: "nouns.csv", CSVPath: "/b/nouns.csv", FileHash: "bbb", Status: "in_progress"}
+	for _, checkpoint := range []*ImportCheckpoint{first, second} {
+		if err := repo.CreateCheckpoint(checkpoint); err != nil {
+			t.Fatalf("CreateCheckpoint() error = %v", err)
+		}
+	}
+
+	found, err := repo.GetCheckpointByHash("aaa")
+	if err != nil || found == nil || found.ID != first.ID {
+		t.Errorf("GetCheckpointByHash(aaa) = %v, %v, want checkpoint %d", found, err, first.ID)
+	}
+	found, err = repo.GetCheckpointByPath("/b/nouns.csv")
+	if err != nil || found == nil || found.ID != second.ID {
+		t.Errorf("GetCheckpointByPath(/b/nouns.csv) = %v, %v, want checkpoint %d", found, err, second.ID)
+	}
+	if found, err := repo.GetCheckpointByHash("ccc"); err != nil || found != nil {
+		t.Errorf("GetCheckpointByHash(ccc) = %v, %v, want nil", found, err)
+	}
+
+	// An edited file keeps its checkpoint under the new hash
+	first.FileHash = "aab"
+	if err := repo.UpdateCheckpoint(first); err != nil {
+		t.Fatalf("UpdateCheckpoint() error = %v", err)
+	}
+	if found, _ := repo.GetCheckpointByHash("aab"); found == nil || found.ID != first.ID {
+		t.Errorf("GetCheckpointByHash(aab) = %v, want checkpoint %d", found, first.ID)
+	}
+
+	// Checkpoints made before hashing have no hash and match nothing
+	legacy := &ImportCheckpoint{CSVFilename: "old.csv", Status: "in_progress"}
+	if err := repo.CreateCheckpoint(legacy); err != nil {
+		t.Fatalf("CreateCheckpoint() error = %v", err)
+	}
+	if found, err := repo.GetCheckpointByHash(""); err != nil || found != nil {
+		t.Errorf("GetCheckpointByHash(\"\") = %v, %v, want nil", found, err)
+	}
+}
+
+func TestListImportedRowHashes(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	checkpoint := &ImportCheckpoint{CSVFilename: "nouns.csv", CSVPath: "/a/nouns.csv", FileHash: "aaa", Status: "in_progress"}
+	if err := repo.CreateCheckpoint(checkpoint); err != nil {
+		t.Fatalf("CreateCheckpoint() error = %v", err)
+	}
+	run := &ImportRun{CSVPath: "nouns.csv", CheckpointID: &checkpoint.ID}
+	if err := repo.CreateImportRun(run); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+	retry := &ImportRun{CSVPath: "nouns.csv", RetryOf: &run.ID}
+	if err := repo.CreateImportRun(retry); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+	other := &ImportRun{CSVPath: "other.csv"}
+	if err := repo.CreateImportRun(other); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+
+	rows := []*ImportRow{
+		{RunID: run.ID, RowNum: 2, RowHash: "teacher", Status: ImportRowImported},
+		{RunID: run.ID, RowNum: 3, RowHash: "book", Status: ImportRowFailed, Error: "timeout"},
+		{RunID: run.ID, RowNum: 4, RowHash: "house", Status: ImportRowDuplicate},
+		{RunID: run.ID, RowNum: 5, RowHash: "sea", Status: ImportRowFailed, Error: "timeout"},
+		{RunID: retry.ID, RowNum: 3, RowHash: "book", Status: ImportRowImported},
+		{RunID: other.ID, RowNum: 2, RowHash: "dog", Status: ImportRowImported},
+	}
+	for _, row := range rows {
+		row.English, row.Greek, row.Gender = row.RowHash, row.RowHash, "neuter"
+		if err := repo.RecordImportRow(row); err != nil {
+			t.Fatalf("RecordImportRow() error = %v", err)
+		}
+	}
+
+	hashes, err := repo.ListImportedRowHashes(checkpoint.ID)
+	if err != nil {
+		t.Fatalf("ListImportedRowHashes() error = %v", err)
+	}
+	want := map[string]bool{"teacher": true, "book": true, "house": true}
+	if len(hashes) != len(want) {
+		t.Errorf("ListImportedRowHashes() = %v, want %v", hashes, want)
+	}
+	for hash := range want {
+		if !hashes[hash] {
+			t.Errorf("ListImportedRowHashes() is missing %q", hash)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// Outcomes of an imported CSV row
+const (
+	ImportRowImported  = "imported"
+	ImportRowFailed    = "failed"
+	ImportRowDuplicate = "duplicate"
+)
+
+// ImportRun is one run of the import command over a CSV file, or over the failed
+// rows of an earlier run
+type ImportRun struct {
+	ID           int64      `db:"id"`
+	CSVPath      string     `db:"csv_path"`
+	CheckpointID *int64     `db:"checkpoint_id"` // Checkpoint the run resumes from, if any
+	RetryOf      *int64     `db:"retry_of"`      // Run whose failures this run retries
+	Status       string     `db:"status"`        // in_progress or completed
+	StartedAt    time.Time  `db:"started_at"`
+	FinishedAt   *time.Time `db:"finished_at"`
+}
+
+// ImportRow records what happened to one CSV row in an import run
+type ImportRow struct {
+	RunID       int64     `db:"run_id"`
+	RowNum      int       `db:"row_num"`  // Line of the row in the CSV file
+	RowHash     string    `db:"row_hash"` // Hash of the row's content, which survives edits around it
+	English     string    `db:"english"`
+	Greek       string    `db:"greek"`
+	Gender      string    `db:"gender"`
+	Status      string    `db:"status"` // ImportRowImported, ImportRowFailed or ImportRowDuplicate
+	Error       string    `db:"error"`  // Why the row failed
+	NounID      *int64    `db:"noun_id"`
+	ProcessedAt time.Time `db:"processed_at"`
+}
+
+// ImportRunCounts tallies the row outcomes of an import run
+type ImportRunCounts struct {
+	Imported   int `db:"imported"`
+	Failed     int `db:"failed"`
+	Duplicates int `db:"duplicates"`
+}
+
+// ImportRunSummary is an import run with its row counts
+type ImportRunSummary struct {
+	ImportRun
+	ImportRunCounts
+}
+
+// CreateImportRun starts a new import run
+func (r *SQLiteRepository) CreateImportRun(run *ImportRun) error {
+	run.Status = "in_progress"
+	query := `
+		INSERT INTO import_runs (csv_path, checkpoint_id, retry_of, status)
+		VALUES (:csv_path, :checkpoint_id, :retry_of, :status)
+	`
+	result, err := r.db.NamedExec(query, run)
+	if err != nil {
+		return fmt.Errorf("failed to create import run: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	run.ID = id
+	return nil
+}
+
+// FinishImportRun marks an import run as completed
+func (r *SQLiteRepository) FinishImportRun(id int64) error {
+	query := "UPDATE import_runs SET status = 'completed', finished_at = CURRENT_TIMESTAMP WHERE id = ?"
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to finish import run: %w", err)
+	}
+	return nil
+}
+
+// GetImportRun retrieves an import run by ID
+func (r *SQLiteRepository) GetImportRun(id int64) (*ImportRun, error) {
+	var run ImportRun
+	err := r.db.Get(&run, "SELECT * FROM import_runs WHERE id = ?", id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("import run %w with id %d", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get import run: %w", err)
+	}
+	return &run, nil
+}
+
+// GetInProgressImportRun retrieves the unfinished run of a checkpoint, or nil if there is none
+func (r *SQLiteRepository) GetInProgressImportRun(checkpointID int64) (*ImportRun, error) {
+	var run ImportRun
+	query := `
+		SELECT * FROM import_runs
+		WHERE checkpoint_id = ? AND status = 'in_progress'
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	err := r.db.Get(&run, query, checkpointID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No unfinished run, not an error
+		}
+		return nil, fmt.Errorf("failed to get import run: %w", err)
+	}
+	return &run, nil
+}
+
+// ListImportRuns retrieves the most recent import runs with their counts, newest first
+func (r *SQLiteRepository) ListImportRuns(limit int) ([]*ImportRunSummary, error) {
+	var runs []*ImportRunSummary
+	query := `
+		SELECT r.*,
+			COUNT(CASE WHEN w.status = 'imported' THEN 1 END) AS imported,
+			COUNT(CASE WHEN w.status = 'failed' THEN 1 END) AS failed,
+			COUNT(CASE WHEN w.status = 'duplicate' THEN 1 END) AS duplicates
+		FROM import_runs r
+		LEFT JOIN import_rows w ON w.run_id = r.id
+		GROUP BY r.id
+		ORDER BY r.id DESC
+		LIMIT ?
+	`
+	if err := r.db.Select(&runs, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to list import runs: %w", err)
+	}
+	return runs, nil
+}
+
+// RecordImportRow saves the outcome of a row, replacing an earlier outcome of the
+// same row in the run
+func (r *SQLiteRepository) RecordImportRow(row *ImportRow) error {
+	query := `
+		INSERT OR REPLACE INTO import_rows (
+			run_id, row_num, row_hash, english, greek, gender, status, error, noun_id
+		) VALUES (
+			:run_id, :row_num, :row_hash, :english, :greek, :gender, :status, :error, :noun_id
+		)
+	`
+	if _, err := r.db.NamedExec(query, row); err != nil {
+		return fmt.Errorf("failed to record import row: %w", err)
+	}
+	return nil
+}
+
+// ListImportRows retrieves the rows of a run in CSV order, optionally only those
+// with the given status
+func (r *SQLiteRepository) ListImportRows(runID int64, status string) ([]*ImportRow, error) {
+	var rows []*ImportRow
+	query := "SELECT * FROM import_rows WHERE run_id = ? AND (? = '' OR status = ?) ORDER BY row_num"
+	if err := r.db.Select(&rows, query, runID, status, status); err != nil {
+		return nil, fmt.Errorf("failed to list import rows: %w", err)
+	}
+	return rows, nil
+}
+
+// CountImportRows tallies the row outcomes of a run
+func (r *SQLiteRepository) CountImportRows(runID int64) (*ImportRunCounts, error) {
+	var counts ImportRunCounts
+	query := `
+		SELECT
+			COUNT(CASE WHEN status = 'imported' THEN 1 END) AS imported,
+			COUNT(CASE WHEN status = 'failed' THEN 1 END) AS failed,
+			COUNT(CASE WHEN status = 'duplicate' THEN 1 END) AS duplicates
+		FROM import_rows
+		WHERE run_id = ?
+	`
+	if err := r.db.Get(&counts, query, runID); err != nil {
+		return nil, fmt.Errorf("failed to count import rows: %w", err)
+	}
+	return &counts, nil
+}
+
+// FindNoun retrieves a noun by its nominative singular and gender, or nil if there is none
+func (r *SQLiteRepository) FindNoun(nominativeSg, gender string) (*models.Noun, error) {
+	var noun models.Noun
+	query := "SELECT * FROM nouns WHERE nominative_sg = ? AND gender = ? ORDER BY id LIMIT 1"
+	err := r.db.Get(&noun, query, nominativeSg, gender)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find noun: %w", err)
+	}
+	return &noun, nil
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestImportRunLedger(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	checkpoint := &ImportCheckpoint{CSVFilename: "nouns.csv", Status: "in_progress"}
+	if err := repo.CreateCheckpoint(checkpoint); err != nil {
+		t.Fatalf("CreateCheckpoint() error = %v", err)
+	}
+
+	run := &ImportRun{CSVPath: "/data/nouns.csv", CheckpointID: &checkpoint.ID}
+	if err := repo.CreateImportRun(run); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+
+	noun := &models.Noun{English: "teacher", Gender: "masculine", NominativeSg: "δάσκαλος"}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	rows := []*ImportRow{
+		{RunID: run.ID, RowNum: 2, English: "teacher", Greek: "δάσκαλος", Gender: "masculine", Status: ImportRowImported, NounID: &noun.ID},
+		{RunID: run.ID, RowNum: 3, English: "book", Greek: "βιβλίο", Gender: "neuter", Status: ImportRowFailed, Error: "rate limited"},
+		{RunID: run.ID, RowNum: 4, English: "teacher", Greek: "δάσκαλος", Gender: "masculine", Status: ImportRowDuplicate, NounID: &noun.ID},
+	}
+	for _, row := range rows {
+		if err := repo.RecordImportRow(row); err != nil {
+			t.Fatalf("RecordImportRow() error = %v", err)
+		}
+	}
+
+	// A resumed run finds its unfinished run through the checkpoint
+	inProgress, err := repo.GetInProgressImportRun(checkpoint.ID)
+	if err != nil || inProgress == nil || inProgress.ID != run.ID {
+		t.Fatalf("GetInProgressImportRun() = %v, %v, want run %d", inProgress, err, run.ID)
+	}
+
+	counts, err := repo.CountImportRows(run.ID)
+	if err != nil {
+		t.Fatalf("CountImportRows() error = %v", err)
+	}
+	if *counts != (ImportRunCounts{Imported: 1, Failed: 1, Duplicates: 1}) {
+		t.Errorf("CountImportRows() = %+v", counts)
+	}
+
+	failed, err := repo.ListImportRows(run.ID, ImportRowFailed)
+	if err != nil {
+		t.Fatalf("ListImportRows() error = %v", err)
+	}
+	if len(failed) != 1 || failed[0].Greek != "βιβλίο" || failed[0].Error != "rate limited" {
+		t.Errorf("failed rows = %+v, want the book row with its error", failed)
+	}
+	if all, _ := repo.ListImportRows(run.ID, ""); len(all) != 3 || all[0].RowNum != 2 {
+		t.Errorf("ListImportRows() = %d rows, want all 3 in CSV order", len(all))
+	}
+
+	// Recording a row again replaces its outcome
+	rows[1].Status, rows[1].Error = ImportRowImported, ""
+	if err := repo.RecordImportRow(rows[1]); err != nil {
+		t.Fatalf("RecordImportRow() error = %v", err)
+	}
+	if counts, _ := repo.CountImportRows(run.ID); counts.Failed != 0 || counts.Imported != 2 {
+		t.Errorf("counts after re-recording = %+v", counts)
+	}
+
+	if err := repo.FinishImportRun(run.ID); err != nil {
+		t.Fatalf("FinishImportRun() error = %v", err)
+	}
+	if inProgress, _ := repo.GetInProgressImportRun(checkpoint.ID); inProgress != nil {
+		t.Error("a finished run should not be in progress")
+	}
+
+	finished, err := repo.GetImportRun(run.ID)
+	if err != nil {
+		t.Fatalf("GetImportRun() error = %v", err)
+	}
+	if finished.Status != "completed" || finished.FinishedAt == nil {
+		t.Errorf("finished run = %+v", finished)
+	}
+	if _, err := repo.GetImportRun(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetImportRun(999) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListImportRuns(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	first := &ImportRun{CSVPath: "nouns.csv"}
+	if err := repo.CreateImportRun(first); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+	repo.RecordImportRow(&ImportRow{RunID: first.ID, RowNum: 2, English: "book", Greek: "βιβλίο", Gender: "neuter", Status: ImportRowFailed, Error: "timeout"})
+
+	retry := &ImportRun{CSVPath: "nouns.csv", RetryOf: &first.ID}
+	if err := repo.CreateImportRun(retry); err != nil {
+		t.Fatalf("CreateImportRun() error = %v", err)
+	}
+
+	runs, err := repo.ListImportRuns(10)
+	if err != nil {
+		t.Fatalf("ListImportRuns() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].ID != retry.ID {
+		t.Fatalf("ListImportRuns() = %d runs, want 2, newest first", len(runs))
+	}
+	if runs[0].RetryOf == nil || *runs[0].RetryOf != first.ID || runs[0].Status != "in_progress" {
+		t.Errorf("retry run = %+v", runs[0])
+	}
+	if runs[1].Failed != 1 || runs[1].Imported != 0 {
+		t.Errorf("first run counts = %+v", runs[1].ImportRunCounts)
+	}
+}
+
+func TestFindNoun(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{English: "teacher", Gender: "masculine", NominativeSg: "δάσκαλος"}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	found, err := repo.FindNoun("δάσκαλος", "masculine")
+	if err != nil || found == nil || found.ID != noun.ID {
+		t.Errorf("FindNoun() = %v, %v, want noun %d", found, err, noun.ID)
+	}
+	if found, err := repo.FindNoun("δάσκαλος", "feminine"); err != nil || found != nil {
+		t.Errorf("FindNoun() with another gender = %v, %v, want nil", found, err)
+	}
+}
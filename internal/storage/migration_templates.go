@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/gataky/greekmaster/internal/models"
@@ -82,22 +83,25 @@ func createEnglishTemplate(englishPrompt string, englishNoun string) string {
 	return template
 }
 
-// analyzeSentencePatterns extracts unique templates from existing sentences
-func analyzeSentencePatterns(sentences []*models.Sentence, nouns map[int64]*models.Noun) ([]*models.SentenceTemplate, error) {
+// analyzeSentencePatterns extracts unique templates from existing sentences,
+// returning them with the number of sentences that could not be parsed
+func analyzeSentencePatterns(sentences []*models.Sentence, nouns map[int64]*models.Noun) ([]*models.SentenceTemplate, int, error) {
 	patternMap := make(map[string]*models.SentenceTemplate)
+	skipped := 0
 
 	for _, sentence := range sentences {
 		// Get the noun used in this sentence
 		noun, ok := nouns[sentence.NounID]
 		if !ok {
-			return nil, fmt.Errorf("noun not found for sentence ID %d", sentence.ID)
+			return nil, 0, fmt.Errorf("noun not found for sentence ID %d", sentence.ID)
 		}
 
 		// Detect which fields were used
 		articleField, nounFormField, err := detectFields(sentence, noun)
 		if err != nil {
 			// Skip sentences that can't be parsed
-			fmt.Printf("Warning: skipping sentence ID %d: %v\n", sentence.ID, err)
+			slog.Warn("Skipped sentence during migration", "sentence", sentence.ID, "error", err)
+			skipped++
 			continue
 		}
 
@@ -138,7 +142,7 @@ func analyzeSentencePatterns(sentences []*models.Sentence, nouns map[int64]*mode
 		templates = append(templates, template)
 	}
 
-	return templates, nil
+	return templates, skipped, nil
 }
 
 // validateMigration verifies all sentences can be regenerated from templates
@@ -170,39 +174,42 @@ func validateMigration(templates []*models.SentenceTemplate, originalSentences [
 		}
 	}
 
-	fmt.Printf("Validation passed: %d unique templates cover all %d sentence combinations\n",
-		len(templates), len(originalCombos))
+	slog.Info("Validated migration", "templates", len(templates), "combinations", len(originalCombos))
 
 	return nil
 }
 
+// TemplateMigration summarises a completed migration to templates
+type TemplateMigration struct {
+	Sentences int // sentences converted and deleted
+	Nouns     int // nouns the sentences were matched against
+	Templates int // unique templates created
+	Skipped   int // sentences that could not be parsed into a template
+}
+
 // MigrateToTemplates performs the one-time migration from sentences to templates
-func MigrateToTemplates(repo *SQLiteRepository) error {
-	fmt.Println("Starting migration to template-based system...")
-	fmt.Println()
+func MigrateToTemplates(repo *SQLiteRepository) (*TemplateMigration, error) {
+	slog.Info("Starting migration to templates")
 
 	// Start transaction for rollback capability
 	tx, err := repo.db.Beginx()
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
 	}
 	defer tx.Rollback() // Will be no-op if we commit
 
 	// 1. Load all existing sentences
-	fmt.Println("Loading existing sentences...")
 	var sentences []*models.Sentence
 	err = repo.db.Select(&sentences, "SELECT * FROM sentences ORDER BY id")
 	if err != nil {
-		return fmt.Errorf("failed to load sentences: %w", err)
+		return nil, fmt.Errorf("failed to load sentences: %w", err)
 	}
-	fmt.Printf("Loaded %d sentences\n", len(sentences))
-	fmt.Println()
+	slog.Debug("Loaded sentences", "count", len(sentences))
 
 	// 2. Load all nouns
-	fmt.Println("Loading nouns...")
 	nouns, err := repo.ListNouns()
 	if err != nil {
-		return fmt.Errorf("failed to load nouns: %w", err)
+		return nil, fmt.Errorf("failed to load nouns: %w", err)
 	}
 
 	// Create noun lookup map
@@ -210,20 +217,16 @@ func MigrateToTemplates(repo *SQLiteRepository) error {
 	for _, noun := range nouns {
 		nounMap[noun.ID] = noun
 	}
-	fmt.Printf("Loaded %d nouns\n", len(nouns))
-	fmt.Println()
+	slog.Debug("Loaded nouns", "count", len(nouns))
 
 	// 3. Analyze patterns
-	fmt.Println("Analyzing sentence patterns...")
-	templates, err := analyzeSentencePatterns(sentences, nounMap)
+	templates, skipped, err := analyzeSentencePatterns(sentences, nounMap)
 	if err != nil {
-		return fmt.Errorf("failed to analyze patterns: %w", err)
+		return nil, fmt.Errorf("failed to analyze patterns: %w", err)
 	}
-	fmt.Printf("Extracted %d unique templates\n", len(templates))
-	fmt.Println()
+	slog.Debug("Extracted templates", "count", len(templates), "skipped", skipped)
 
 	// 4. Insert templates
-	fmt.Println("Creating templates in database...")
 	for i, template := range templates {
 		query := `
 			INSERT INTO sentence_templates (
@@ -237,43 +240,36 @@ func MigrateToTemplates(repo *SQLiteRepository) error {
 			template.CaseType, template.Number, template.DifficultyPhase,
 			template.ContextType, template.Preposition)
 		if err != nil {
-			return fmt.Errorf("failed to insert template %d: %w", i, err)
+			return nil, fmt.Errorf("failed to insert template %d: %w", i, err)
 		}
 
 		id, _ := result.LastInsertId()
 		templates[i].ID = id
 	}
-	fmt.Printf("Created %d templates\n", len(templates))
-	fmt.Println()
 
 	// 5. Validate migration
-	fmt.Println("Validating migration...")
 	err = validateMigration(templates, sentences, nounMap)
 	if err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return nil, fmt.Errorf("validation failed: %w", err)
 	}
-	fmt.Println()
 
 	// 6. Delete old sentences
-	fmt.Println("Deleting old sentences...")
 	_, err = tx.Exec("DELETE FROM sentences")
 	if err != nil {
-		return fmt.Errorf("failed to delete sentences: %w", err)
+		return nil, fmt.Errorf("failed to delete sentences: %w", err)
 	}
-	fmt.Printf("Deleted %d sentences\n", len(sentences))
-	fmt.Println()
 
 	// Commit transaction
-	fmt.Println("Committing migration...")
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	fmt.Println("Migration completed successfully!")
-	fmt.Printf("Summary:\n")
-	fmt.Printf("  - Templates created: %d\n", len(templates))
-	fmt.Printf("  - Sentences migrated: %d\n", len(sentences))
-	fmt.Printf("  - Database size reduced by ~%d%%\n", (len(sentences)-len(templates))*100/len(sentences))
-
-	return nil
+	summary := &TemplateMigration{
+		Sentences: len(sentences),
+		Nouns:     len(nouns),
+		Templates: len(templates),
+		Skipped:   skipped,
+	}
+	slog.Info("Migrated sentences to templates", "sentences", summary.Sentences, "templates", summary.Templates, "skipped", summary.Skipped)
+	return summary, nil
 }
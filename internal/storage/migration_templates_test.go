@@ -195,7 +195,7 @@ func TestAnalyzeSentencePatterns(t *testing.T) {
 		},
 	}
 
-	templates, err := analyzeSentencePatterns(sentences, nouns)
+	templates, _, err := analyzeSentencePatterns(sentences, nouns)
 	if err != nil {
 		t.Fatalf("analyzeSentencePatterns() error = %v", err)
 	}
@@ -287,7 +287,7 @@ func TestMigrateToTemplatesEndToEnd(t *testing.T) {
 	}
 
 	// Run migration
-	err := MigrateToTemplates(repo)
+	_, err := MigrateToTemplates(repo)
 	if err != nil {
 		t.Fatalf("MigrateToTemplates() error = %v", err)
 	}
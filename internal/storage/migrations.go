@@ -3,6 +3,7 @@ package storage
 import (
 	_ "embed"
 	"fmt"
+	"log/slog"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,6 +17,30 @@ var dropExplanations string
 //go:embed migrations/003_create_templates.sql
 var createTemplates string
 
+//go:embed migrations/004_create_tags.sql
+var createTags string
+
+//go:embed migrations/005_create_table_drill_results.sql
+var createTableDrillResults string
+
+//go:embed migrations/006_create_attempts.sql
+var createAttempts string
+
+//go:embed migrations/007_create_presets.sql
+var createPresets string
+
+//go:embed migrations/009_create_saved_sessions.sql
+var createSavedSessions string
+
+//go:embed migrations/010_create_profiles.sql
+var createProfiles string
+
+//go:embed migrations/011_create_import_runs.sql
+var createImportRuns string
+
+//go:embed migrations/012_hash_import_checkpoints.sql
+var hashImportCheckpoints string
+
 // RunMigrations executes all database migrations
 func RunMigrations(db *sqlx.DB) error {
 	// Execute the initial schema
@@ -36,5 +61,235 @@ func RunMigrations(db *sqlx.DB) error {
 		return fmt.Errorf("failed to run migration 003: %w", err)
 	}
 
+	// Create noun and template tag tables
+	_, err = db.Exec(createTags)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 004: %w", err)
+	}
+
+	// Create table drill results table
+	_, err = db.Exec(createTableDrillResults)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 005: %w", err)
+	}
+
+	// Create attempt history table
+	_, err = db.Exec(createAttempts)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 006: %w", err)
+	}
+
+	// Create session presets table
+	_, err = db.Exec(createPresets)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 007: %w", err)
+	}
+
+	// Add answer timings to the attempt history
+	if err := addAttemptTimings(db); err != nil {
+		return fmt.Errorf("failed to run migration 008: %w", err)
+	}
+
+	// Create saved sessions table
+	_, err = db.Exec(createSavedSessions)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 009: %w", err)
+	}
+
+	// Create profiles table and scope progress to profiles
+	_, err = db.Exec(createProfiles)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 010: %w", err)
+	}
+	if err := scopeToProfiles(db); err != nil {
+		return fmt.Errorf("failed to run migration 010: %w", err)
+	}
+
+	// Create import run ledger tables
+	_, err = db.Exec(createImportRuns)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 011: %w", err)
+	}
+
+	// Key import checkpoints and rows on content hashes
+	if err := addImportHashes(db); err != nil {
+		return fmt.Errorf("failed to run migration 012: %w", err)
+	}
+	_, err = db.Exec(hashImportCheckpoints)
+	if err != nil {
+		return fmt.Errorf("failed to run migration 012: %w", err)
+	}
+
+	// Remember which templates have been classified, including those with no tags
+	if err := addTemplateTaggedAt(db); err != nil {
+		return fmt.Errorf("failed to run migration 013: %w", err)
+	}
+
 	return nil
 }
+
+// addTemplateTaggedAt adds the tagged_at column to sentence_templates. Templates
+// that already have tags count as classified; those without are checked once more.
+func addTemplateTaggedAt(db *sqlx.DB) error {
+	exists, err := hasColumn(db, "sentence_templates", "tagged_at")
+	if err != nil || exists {
+		return err
+	}
+
+	statements := []string{
+		"ALTER TABLE sentence_templates ADD COLUMN tagged_at DATETIME",
+		"UPDATE sentence_templates SET tagged_at = CURRENT_TIMESTAMP WHERE id IN (SELECT template_id FROM template_tags)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("failed to add tagged_at to sentence_templates: %w", err)
+		}
+	}
+	return nil
+}
+
+// importHashColumns lists the columns that key imports on content rather than
+// file name. Checkpoints made before them have no hash and are never resumed.
+var importHashColumns = []struct{ table, column string }{
+	{"import_checkpoints", "file_hash"},
+	{"import_checkpoints", "csv_path"},
+	{"import_rows", "row_hash"},
+}
+
+// addImportHashes adds the content hash columns to the import tables
+func addImportHashes(db *sqlx.DB) error {
+	for _, c := range importHashColumns {
+		exists, err := hasColumn(db, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TEXT NOT NULL DEFAULT ''", c.table, c.column)
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %w", c.column, c.table, err)
+		}
+	}
+	return nil
+}
+
+// addAttemptTimings adds the latency_ms and slow columns to attempts. Timings
+// kept in the attempt_timings side table by earlier versions are copied over and
+// the table is dropped.
+func addAttemptTimings(db *sqlx.DB) error {
+	for _, column := range []string{"latency_ms", "slow"} {
+		exists, err := hasColumn(db, "attempts", column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE attempts ADD COLUMN %s INTEGER NOT NULL DEFAULT 0", column)
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add %s to attempts: %w", column, err)
+		}
+	}
+
+	var legacy int
+	if err := db.Get(&legacy, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'attempt_timings'"); err != nil {
+		return fmt.Errorf("failed to inspect attempt timings: %w", err)
+	}
+	if legacy == 0 {
+		return nil
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE attempts SET
+			latency_ms = (SELECT latency_ms FROM attempt_timings WHERE attempt_id = attempts.id),
+			slow = (SELECT slow FROM attempt_timings WHERE attempt_id = attempts.id)
+		WHERE id IN (SELECT attempt_id FROM attempt_timings)`,
+		`DROP TABLE attempt_timings`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to move attempt timings: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	slog.Info("Moved attempt timings into the attempts table")
+	return nil
+}
+
+// profileTables lists the per-profile tables that only need a profile_id column
+var profileTables = []string{"attempts", "table_drill_results", "saved_sessions"}
+
+// scopeToProfiles adds a profile_id column to every table holding progress.
+// Existing rows are assigned to the first profile. Session presets are rebuilt
+// so that preset names only need to be unique within a profile.
+func scopeToProfiles(db *sqlx.DB) error {
+	for _, table := range profileTables {
+		scoped, err := hasColumn(db, table, "profile_id")
+		if err != nil {
+			return err
+		}
+		if !scoped {
+			query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN profile_id INTEGER NOT NULL DEFAULT 1", table)
+			if _, err := db.Exec(query); err != nil {
+				return fmt.Errorf("failed to add profile to %s: %w", table, err)
+			}
+			slog.Info("Scoped table to profiles", "table", table)
+		}
+		index := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_profile ON %s(profile_id)", table, table)
+		if _, err := db.Exec(index); err != nil {
+			return fmt.Errorf("failed to index %s by profile: %w", table, err)
+		}
+	}
+
+	scoped, err := hasColumn(db, "session_presets", "profile_id")
+	if err != nil || scoped {
+		return err
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`CREATE TABLE session_presets_scoped (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_id INTEGER NOT NULL DEFAULT 1,
+			name TEXT NOT NULL,
+			config TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (profile_id, name)
+		)`,
+		`INSERT INTO session_presets_scoped (id, name, config, created_at)
+			SELECT id, name, config, created_at FROM session_presets`,
+		`DROP TABLE session_presets`,
+		`ALTER TABLE session_presets_scoped RENAME TO session_presets`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to scope session presets to profiles: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// hasColumn reports whether a table has a column
+func hasColumn(db *sqlx.DB, table, column string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	if err := db.Get(&count, query, table, column); err != nil {
+		return false, fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	return count > 0, nil
+}
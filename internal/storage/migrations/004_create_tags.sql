@@ -0,0 +1,14 @@
+-- Create semantic tag tables for template-noun compatibility
+CREATE TABLE IF NOT EXISTS noun_tags (
+    noun_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (noun_id, tag),
+    FOREIGN KEY (noun_id) REFERENCES nouns(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS template_tags (
+    template_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (template_id, tag),
+    FOREIGN KEY (template_id) REFERENCES sentence_templates(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- Record per-cell results of declension table drills
+CREATE TABLE IF NOT EXISTS table_drill_results (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    noun_id INTEGER NOT NULL,
+    gender TEXT NOT NULL,
+    declension_class TEXT NOT NULL,
+    case_type TEXT NOT NULL CHECK(case_type IN ('nominative', 'genitive', 'accusative')),
+    number TEXT NOT NULL CHECK(number IN ('singular', 'plural')),
+    correct INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (noun_id) REFERENCES nouns(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_table_drill_results_class ON table_drill_results(gender, declension_class);
@@ -0,0 +1,21 @@
+-- Record every answered practice item
+CREATE TABLE IF NOT EXISTS attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    noun_id INTEGER NOT NULL,
+    template_id INTEGER,
+    mode TEXT NOT NULL,
+    case_type TEXT NOT NULL CHECK(case_type IN ('nominative', 'genitive', 'accusative')),
+    number TEXT NOT NULL CHECK(number IN ('singular', 'plural')),
+    context_type TEXT NOT NULL,
+    user_answer TEXT NOT NULL,
+    correct_answer TEXT NOT NULL,
+    correct INTEGER NOT NULL,
+    hints_used INTEGER NOT NULL DEFAULT 0,
+    score REAL NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (noun_id) REFERENCES nouns(id) ON DELETE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES sentence_templates(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_attempts_noun ON attempts(noun_id);
+CREATE INDEX IF NOT EXISTS idx_attempts_created ON attempts(created_at);
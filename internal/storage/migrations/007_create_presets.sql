@@ -0,0 +1,7 @@
+-- Store named session configurations
+CREATE TABLE IF NOT EXISTS session_presets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    config TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
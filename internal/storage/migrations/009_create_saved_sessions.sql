@@ -0,0 +1,6 @@
+-- Keep the state of an interrupted practice session so it can be resumed
+CREATE TABLE IF NOT EXISTS saved_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    state TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
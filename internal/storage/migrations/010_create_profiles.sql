@@ -0,0 +1,12 @@
+-- Learner profiles; progress is kept per profile while nouns and templates are shared
+CREATE TABLE IF NOT EXISTS profiles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    active INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Progress recorded before profiles existed belongs to the first profile
+INSERT INTO profiles (name, active)
+SELECT 'default', 1
+WHERE NOT EXISTS (SELECT 1 FROM profiles);
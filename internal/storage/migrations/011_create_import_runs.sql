@@ -0,0 +1,25 @@
+-- Ledger of CSV imports: one run per import and the outcome of every row it processed
+CREATE TABLE IF NOT EXISTS import_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    csv_path TEXT NOT NULL,
+    checkpoint_id INTEGER REFERENCES import_checkpoints(id) ON DELETE SET NULL,
+    retry_of INTEGER REFERENCES import_runs(id) ON DELETE SET NULL,
+    status TEXT NOT NULL CHECK(status IN ('in_progress', 'completed')),
+    started_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    finished_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_runs_checkpoint ON import_runs(checkpoint_id);
+
+CREATE TABLE IF NOT EXISTS import_rows (
+    run_id INTEGER NOT NULL REFERENCES import_runs(id) ON DELETE CASCADE,
+    row_num INTEGER NOT NULL,
+    english TEXT NOT NULL,
+    greek TEXT NOT NULL,
+    gender TEXT NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('imported', 'failed', 'duplicate')),
+    error TEXT NOT NULL DEFAULT '',
+    noun_id INTEGER REFERENCES nouns(id) ON DELETE SET NULL,
+    processed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (run_id, row_num)
+);
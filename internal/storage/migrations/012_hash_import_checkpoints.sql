@@ -0,0 +1,6 @@
+-- Checkpoints are found by file content and resume by row content. The
+-- file_hash, csv_path and row_hash columns are added in Go, since SQLite
+-- cannot add a column only if it is missing.
+CREATE INDEX IF NOT EXISTS idx_import_checkpoints_hash ON import_checkpoints(file_hash);
+CREATE INDEX IF NOT EXISTS idx_import_checkpoints_path ON import_checkpoints(csv_path);
+CREATE INDEX IF NOT EXISTS idx_import_rows_hash ON import_rows(row_hash);
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// presetRow is a session_presets row with the config still encoded as JSON
+type presetRow struct {
+	ID        int64     `db:"id"`
+	ProfileID int64     `db:"profile_id"`
+	Name      string    `db:"name"`
+	Config    string    `db:"config"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// decode converts a row into a preset
+func (row *presetRow) decode() (*models.Preset, error) {
+	preset := &models.Preset{ID: row.ID, ProfileID: row.ProfileID, Name: row.Name, CreatedAt: row.CreatedAt}
+	if err := json.Unmarshal([]byte(row.Config), &preset.Config); err != nil {
+		return nil, fmt.Errorf("failed to decode preset '%s': %w", row.Name, err)
+	}
+	return preset, nil
+}
+
+// SavePreset stores a preset, replacing any existing preset with the same name
+func (r *SQLiteRepository) SavePreset(preset *models.Preset) error {
+	config, err := json.Marshal(preset.Config)
+	if err != nil {
+		return fmt.Errorf("failed to encode preset: %w", err)
+	}
+
+	query := `
+		INSERT INTO session_presets (profile_id, name, config) VALUES (?, ?, ?)
+		ON CONFLICT(profile_id, name) DO UPDATE SET config = excluded.config
+	`
+	if _, err := r.db.Exec(query, r.profileID, preset.Name, string(config)); err != nil {
+		return fmt.Errorf("failed to save preset: %w", err)
+	}
+
+	preset.ProfileID = r.profileID
+	query = "SELECT id FROM session_presets WHERE profile_id = ? AND name = ?"
+	if err := r.db.Get(&preset.ID, query, r.profileID, preset.Name); err != nil {
+		return fmt.Errorf("failed to get preset id: %w", err)
+	}
+	return nil
+}
+
+// GetPreset retrieves a preset by name
+func (r *SQLiteRepository) GetPreset(name string) (*models.Preset, error) {
+	var row presetRow
+	err := r.db.Get(&row, "SELECT * FROM session_presets WHERE profile_id = ? AND name = ?", r.profileID, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("preset %w with name '%s'", ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get preset: %w", err)
+	}
+	return row.decode()
+}
+
+// ListPresets retrieves the current profile's presets ordered by name
+func (r *SQLiteRepository) ListPresets() ([]*models.Preset, error) {
+	var rows []presetRow
+	if err := r.db.Select(&rows, "SELECT * FROM session_presets WHERE profile_id = ? ORDER BY name", r.profileID); err != nil {
+		return nil, fmt.Errorf("failed to list presets: %w", err)
+	}
+
+	presets := make([]*models.Preset, 0, len(rows))
+	for i := range rows {
+		preset, err := rows[i].decode()
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, preset)
+	}
+	return presets, nil
+}
+
+// DeletePreset removes a preset by name
+func (r *SQLiteRepository) DeletePreset(name string) error {
+	result, err := r.db.Exec("DELETE FROM session_presets WHERE profile_id = ? AND name = ?", r.profileID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete preset: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted preset: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("preset %w with name '%s'", ErrNotFound, name)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestPresets(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	preset := &models.Preset{
+		Name: "genitive plural warm-up",
+		Config: models.SessionConfig{
+			DifficultyLevel: "intermediate",
+			IncludePlural:   true,
+			QuestionCount:   10,
+			Mode:            "sentence",
+			Filter: models.SessionFilter{
+				Cases:      []string{"genitive"},
+				NounRanges: []models.IDRange{{Min: 1, Max: 50}},
+			},
+		},
+	}
+	if err := repo.SavePreset(preset); err != nil {
+		t.Fatalf("SavePreset() error = %v", err)
+	}
+	if preset.ID == 0 {
+		t.Error("Expected preset ID to be set after saving")
+	}
+
+	got, err := repo.GetPreset("genitive plural warm-up")
+	if err != nil {
+		t.Fatalf("GetPreset() error = %v", err)
+	}
+	if got.Config.DifficultyLevel != "intermediate" || !got.Config.IncludePlural || got.Config.QuestionCount != 10 {
+		t.Errorf("GetPreset() config = %+v", got.Config)
+	}
+	if len(got.Config.Filter.Cases) != 1 || got.Config.Filter.Cases[0] != "genitive" {
+		t.Errorf("Expected case filter [genitive], got %v", got.Config.Filter.Cases)
+	}
+	if len(got.Config.Filter.NounRanges) != 1 || got.Config.Filter.NounRanges[0] != (models.IDRange{Min: 1, Max: 50}) {
+		t.Errorf("Expected noun range 1-50, got %v", got.Config.Filter.NounRanges)
+	}
+
+	// Saving under the same name replaces the preset
+	preset.Config.QuestionCount = 0
+	if err := repo.SavePreset(preset); err != nil {
+		t.Fatalf("SavePreset() replace error = %v", err)
+	}
+	presets, err := repo.ListPresets()
+	if err != nil {
+		t.Fatalf("ListPresets() error = %v", err)
+	}
+	if len(presets) != 1 || presets[0].Config.QuestionCount != 0 {
+		t.Fatalf("Expected one replaced preset, got %d", len(presets))
+	}
+
+	if err := repo.DeletePreset("genitive plural warm-up"); err != nil {
+		t.Fatalf("DeletePreset() error = %v", err)
+	}
+	if _, err := repo.GetPreset("genitive plural warm-up"); err == nil {
+		t.Error("Expected error getting deleted preset")
+	}
+	if err := repo.DeletePreset("genitive plural warm-up"); err == nil {
+		t.Error("Expected error deleting missing preset")
+	}
+}
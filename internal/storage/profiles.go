@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// loadActiveProfile selects the profile marked active, falling back to the oldest one
+func (r *SQLiteRepository) loadActiveProfile() error {
+	query := "SELECT id FROM profiles ORDER BY active DESC, id LIMIT 1"
+	if err := r.db.Get(&r.profileID, query); err != nil {
+		return fmt.Errorf("failed to load active profile: %w", err)
+	}
+	return nil
+}
+
+// getProfile retrieves a profile by name
+func (r *SQLiteRepository) getProfile(name string) (*models.Profile, error) {
+	var profile models.Profile
+	err := r.db.Get(&profile, "SELECT * FROM profiles WHERE name = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile %w with name '%s'", ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// CreateProfile adds a new, inactive profile
+func (r *SQLiteRepository) CreateProfile(name string) (*models.Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("profile name cannot be empty")
+	}
+
+	var exists int
+	if err := r.db.Get(&exists, "SELECT COUNT(*) FROM profiles WHERE name = ?", name); err != nil {
+		return nil, fmt.Errorf("failed to check profile: %w", err)
+	}
+	if exists > 0 {
+		return nil, fmt.Errorf("profile '%s' already exists", name)
+	}
+
+	if _, err := r.db.Exec("INSERT INTO profiles (name) VALUES (?)", name); err != nil {
+		return nil, fmt.Errorf("failed to create profile: %w", err)
+	}
+	slog.Info("Created profile", "profile", name)
+	return r.getProfile(name)
+}
+
+// ListProfiles retrieves all profiles ordered by name
+func (r *SQLiteRepository) ListProfiles() ([]*models.Profile, error) {
+	var profiles []*models.Profile
+	if err := r.db.Select(&profiles, "SELECT * FROM profiles ORDER BY name"); err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// CurrentProfile retrieves the profile progress is read and recorded for
+func (r *SQLiteRepository) CurrentProfile() (*models.Profile, error) {
+	var profile models.Profile
+	if err := r.db.Get(&profile, "SELECT * FROM profiles WHERE id = ?", r.profileID); err != nil {
+		return nil, fmt.Errorf("failed to get current profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// UseProfile makes this repository read and record progress for a profile
+// without changing the active profile
+func (r *SQLiteRepository) UseProfile(name string) error {
+	profile, err := r.getProfile(name)
+	if err != nil {
+		return err
+	}
+	r.profileID = profile.ID
+	return nil
+}
+
+// SwitchProfile makes a profile the active one and uses it
+func (r *SQLiteRepository) SwitchProfile(name string) error {
+	profile, err := r.getProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.Exec("UPDATE profiles SET active = (id = ?)", profile.ID); err != nil {
+		return fmt.Errorf("failed to switch profile: %w", err)
+	}
+	r.profileID = profile.ID
+	slog.Info("Switched profile", "profile", name)
+	return nil
+}
+
+// DeleteProfile removes an inactive profile together with all of its progress
+func (r *SQLiteRepository) DeleteProfile(name string) error {
+	profile, err := r.getProfile(name)
+	if err != nil {
+		return err
+	}
+	if profile.Active || profile.ID == r.profileID {
+		return fmt.Errorf("cannot delete profile '%s' while it is in use; switch to another profile first", name)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		"DELETE FROM attempts WHERE profile_id = ?",
+		"DELETE FROM table_drill_results WHERE profile_id = ?",
+		"DELETE FROM session_presets WHERE profile_id = ?",
+		"DELETE FROM saved_sessions WHERE profile_id = ?",
+		"DELETE FROM profiles WHERE id = ?",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, profile.ID); err != nil {
+			return fmt.Errorf("failed to delete profile: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete profile: %w", err)
+	}
+	slog.Info("Deleted profile and its progress", "profile", name)
+	return nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestDefaultProfile(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	profile, err := repo.CurrentProfile()
+	if err != nil {
+		t.Fatalf("CurrentProfile() error = %v", err)
+	}
+	if profile.Name != "default" || !profile.Active {
+		t.Errorf("Expected the active 'default' profile, got %+v", profile)
+	}
+}
+
+func TestProfilesScopeProgress(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	if _, err := repo.CreateProfile("maria"); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if _, err := repo.CreateProfile(" maria "); err == nil {
+		t.Error("Expected an error creating a duplicate profile")
+	}
+
+	// The same preset name can be saved by each profile
+	preset := &models.Preset{Name: "warm-up", Config: models.SessionConfig{QuestionCount: 10}}
+	if err := repo.SavePreset(preset); err != nil {
+		t.Fatalf("SavePreset() error = %v", err)
+	}
+	if err := repo.SaveSession(&models.SavedSession{CurrentIndex: 3}); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	if err := repo.UseProfile("maria"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+	presets, err := repo.ListPresets()
+	if err != nil {
+		t.Fatalf("ListPresets() error = %v", err)
+	}
+	if len(presets) != 0 {
+		t.Errorf("Expected no presets for a new profile, got %d", len(presets))
+	}
+	saved, err := repo.GetSavedSession()
+	if err != nil {
+		t.Fatalf("GetSavedSession() error = %v", err)
+	}
+	if saved != nil {
+		t.Error("Expected no saved session for a new profile")
+	}
+
+	mariaPreset := &models.Preset{Name: "warm-up", Config: models.SessionConfig{QuestionCount: 5}}
+	if err := repo.SavePreset(mariaPreset); err != nil {
+		t.Fatalf("SavePreset() error = %v", err)
+	}
+	if mariaPreset.ID == preset.ID {
+		t.Error("Expected each profile to get its own preset")
+	}
+
+	// Using a profile doesn't change the active one
+	if err := repo.UseProfile("default"); err != nil {
+		t.Fatalf("UseProfile() error = %v", err)
+	}
+	got, err := repo.GetPreset("warm-up")
+	if err != nil {
+		t.Fatalf("GetPreset() error = %v", err)
+	}
+	if got.Config.QuestionCount != 10 {
+		t.Errorf("Expected the default profile's preset, got %+v", got.Config)
+	}
+}
+
+func TestSwitchAndDeleteProfile(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	if _, err := repo.CreateProfile("nikos"); err != nil {
+		t.Fatalf("CreateProfile() error = %v", err)
+	}
+	if err := repo.SwitchProfile("nikos"); err != nil {
+		t.Fatalf("SwitchProfile() error = %v", err)
+	}
+	if err := repo.SavePreset(&models.Preset{Name: "mine"}); err != nil {
+		t.Fatalf("SavePreset() error = %v", err)
+	}
+
+	profiles, err := repo.ListProfiles()
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Active || !profiles[1].Active {
+		t.Errorf("Expected 'nikos' to be the only active profile, got %+v %+v", profiles[0], profiles[1])
+	}
+
+	if err := repo.DeleteProfile("nikos"); err == nil {
+		t.Error("Expected an error deleting the active profile")
+	}
+	if err := repo.SwitchProfile("default"); err != nil {
+		t.Fatalf("SwitchProfile() error = %v", err)
+	}
+	if err := repo.DeleteProfile("nikos"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+	if err := repo.UseProfile("nikos"); err == nil {
+		t.Error("Expected an error using a deleted profile")
+	}
+
+	var presets int
+	if err := repo.db.Get(&presets, "SELECT COUNT(*) FROM session_presets"); err != nil {
+		t.Fatalf("count presets: %v", err)
+	}
+	if presets != 0 {
+		t.Errorf("Expected the deleted profile's presets to be removed, got %d", presets)
+	}
+}
+
+func TestScopeExistingProgressToProfiles(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// A database created before profiles existed
+	db, err := sqlx.Connect("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if _, err := db.Exec(createPresets); err != nil {
+		t.Fatalf("create presets: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO session_presets (name, config) VALUES ('old', '{"question_count":7}')`); err != nil {
+		t.Fatalf("insert preset: %v", err)
+	}
+	db.Close()
+
+	repo, err := NewSQLiteRepository(dbPath)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	defer repo.Close()
+
+	preset, err := repo.GetPreset("old")
+	if err != nil {
+		t.Fatalf("GetPreset() error = %v", err)
+	}
+	if preset.Config.QuestionCount != 7 {
+		t.Errorf("Expected the existing preset to keep its config, got %+v", preset.Config)
+	}
+
+	// Running the migrations again leaves the scoped tables alone
+	if err := RunMigrations(repo.db); err != nil {
+		t.Fatalf("RunMigrations() error = %v", err)
+	}
+	if _, err := repo.GetPreset("old"); err != nil {
+		t.Errorf("GetPreset() after re-running migrations error = %v", err)
+	}
+}
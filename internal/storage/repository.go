@@ -2,21 +2,45 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/gataky/greekmaster/internal/grammar"
 	"github.com/gataky/greekmaster/internal/models"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// ErrNotFound is wrapped by the errors returned for records that don't exist
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalidSentence is wrapped by the errors returned for sentences that no
+// template and noun in the database could have produced
+var ErrInvalidSentence = errors.New("invalid sentence")
+
+// notFoundError is an ErrNotFound with a message of its own
+type notFoundError string
+
+func (e notFoundError) Error() string {
+	return string(e)
+}
+
+func (e notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // Repository defines the interface for data storage operations
 type Repository interface {
 	// Noun operations
 	CreateNoun(noun *models.Noun) error
 	GetNoun(id int64) (*models.Noun, error)
 	ListNouns() ([]*models.Noun, error)
+	SearchNouns(query string) ([]*models.Noun, error)
+	UpdateNoun(noun *models.Noun) error
 
 	// Sentence operations
 	CreateSentence(sentence *models.Sentence) error
@@ -26,10 +50,46 @@ type Repository interface {
 	CreateTemplate(template *models.SentenceTemplate) error
 	GetTemplate(id int64) (*models.SentenceTemplate, error)
 	ListTemplates() ([]*models.SentenceTemplate, error)
+	UpdateTemplate(template *models.SentenceTemplate) error
 	GetRandomTemplates(phase int, number string, limit int) ([]*models.SentenceTemplate, error)
 
+	// Semantic tag operations
+	SetNounTags(nounID int64, tags []string) error
+	ListNounTags() (map[int64][]string, error)
+	SetTemplateTags(templateID int64, tags []string) error
+	ListTemplateTags() (map[int64][]string, error)
+
+	// Attempt history operations
+	RecordAttempt(attempt *models.Attempt) error
+	ListAttempts() ([]*models.Attempt, error)
+
+	// Session preset operations
+	SavePreset(preset *models.Preset) error
+	GetPreset(name string) (*models.Preset, error)
+	ListPresets() ([]*models.Preset, error)
+	DeletePreset(name string) error
+
+	// Saved session operations
+	SaveSession(session *models.SavedSession) error
+	GetSavedSession() (*models.SavedSession, error)
+	DeleteSavedSession() error
+
+	// Profile operations
+	CreateProfile(name string) (*models.Profile, error)
+	ListProfiles() ([]*models.Profile, error)
+	CurrentProfile() (*models.Profile, error)
+	UseProfile(name string) error
+	SwitchProfile(name string) error
+	DeleteProfile(name string) error
+
+	// Table drill operations
+	RecordTableDrillResults(results []*models.TableDrillResult) error
+	ListTableDrillStats() ([]*models.TableDrillStat, error)
+
 	// Template-based sentence generation
 	GeneratePracticeSentences(phase int, number string, limit int) ([]*models.Sentence, error)
+	GenerateFilteredSentences(phase int, number string, filter models.SessionFilter, limit int) ([]*models.Sentence, error)
+	GenerateAlternativeSentence(sentence *models.Sentence, config models.SessionConfig) (*models.Sentence, error)
 
 	// Close database connection
 	Close() error
@@ -37,7 +97,9 @@ type Repository interface {
 
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	path      string // Database file, used to place snapshots
+	profileID int64  // Profile that progress is read and recorded for
 }
 
 // NewSQLiteRepository creates a new SQLite repository
@@ -60,6 +122,7 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 	}
 
 	// Open database connection
+	slog.Debug("Opening database", "path", dbPath)
 	db, err := sqlx.Connect("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -71,13 +134,34 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 
+	// Snapshot the database before migrations that drop or rebuild tables
+	if dbPath != ":memory:" {
+		destructive, err := needsDestructiveMigration(db)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		if destructive {
+			if _, err := snapshot(db, dbPath, "pre-migration"); err != nil {
+				db.Close()
+				return nil, fmt.Errorf("failed to snapshot database before migrating: %w", err)
+			}
+		}
+	}
+
 	// Run migrations
 	if err := RunMigrations(db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	return &SQLiteRepository{db: db}, nil
+	repo := &SQLiteRepository{db: db, path: dbPath}
+	if err := repo.loadActiveProfile(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return repo, nil
 }
 
 // CreateNoun inserts a new noun into the database
@@ -117,13 +201,34 @@ func (r *SQLiteRepository) GetNoun(id int64) (*models.Noun, error) {
 	err := r.db.Get(&noun, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("noun not found with id %d", id)
+			return nil, fmt.Errorf("noun %w with id %d", ErrNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get noun: %w", err)
 	}
 	return &noun, nil
 }
 
+// UpdateNoun saves the gender and forms of an existing noun
+func (r *SQLiteRepository) UpdateNoun(noun *models.Noun) error {
+	query := `
+		UPDATE nouns SET
+			english = :english, gender = :gender,
+			nominative_sg = :nominative_sg, genitive_sg = :genitive_sg, accusative_sg = :accusative_sg,
+			nominative_pl = :nominative_pl, genitive_pl = :genitive_pl, accusative_pl = :accusative_pl,
+			nom_sg_article = :nom_sg_article, gen_sg_article = :gen_sg_article, acc_sg_article = :acc_sg_article,
+			nom_pl_article = :nom_pl_article, gen_pl_article = :gen_pl_article, acc_pl_article = :acc_pl_article
+		WHERE id = :id
+	`
+	result, err := r.db.NamedExec(query, noun)
+	if err != nil {
+		return fmt.Errorf("failed to update noun: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("noun %w with id %d", ErrNotFound, noun.ID)
+	}
+	return nil
+}
+
 // ListNouns retrieves all nouns ordered by ID
 func (r *SQLiteRepository) ListNouns() ([]*models.Noun, error) {
 	var nouns []*models.Noun
@@ -135,6 +240,29 @@ func (r *SQLiteRepository) ListNouns() ([]*models.Noun, error) {
 	return nouns, nil
 }
 
+// SearchNouns finds nouns whose English meaning or Greek nominative singular
+// contains the query, ignoring case and accents. An empty query matches every noun.
+func (r *SQLiteRepository) SearchNouns(query string) ([]*models.Noun, error) {
+	nouns, err := r.ListNouns()
+	if err != nil {
+		return nil, err
+	}
+
+	query = searchKey(query)
+	matches := make([]*models.Noun, 0, len(nouns))
+	for _, noun := range nouns {
+		if strings.Contains(searchKey(noun.English), query) || strings.Contains(searchKey(noun.NominativeSg), query) {
+			matches = append(matches, noun)
+		}
+	}
+	return matches, nil
+}
+
+// searchKey lowercases and strips accents so "δασκαλος" finds "δάσκαλος"
+func searchKey(s string) string {
+	return strings.ToLower(grammar.StripAccents(strings.TrimSpace(s)))
+}
+
 // CreateSentence inserts a new sentence into the database
 func (r *SQLiteRepository) CreateSentence(sentence *models.Sentence) error {
 	query := `
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/gataky/greekmaster/internal/models"
@@ -61,6 +63,40 @@ func TestCreateAndGetNoun(t *testing.T) {
 	}
 }
 
+func TestUpdateNoun(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{
+		English: "forest", Gender: "masculine",
+		NominativeSg: "δάσος", GenitiveSg: "δάσους", AccusativeSg: "δάσος",
+		NominativePl: "δάση", GenitivePl: "δασών", AccusativePl: "δάση",
+		NomSgArticle: "το", GenSgArticle: "του", AccSgArticle: "το",
+		NomPlArticle: "τα", GenPlArticle: "των", AccPlArticle: "τα",
+	}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	noun.Gender = "neuter"
+	if err := repo.UpdateNoun(noun); err != nil {
+		t.Fatalf("UpdateNoun() error = %v", err)
+	}
+
+	retrieved, err := repo.GetNoun(noun.ID)
+	if err != nil {
+		t.Fatalf("GetNoun() error = %v", err)
+	}
+	if retrieved.Gender != "neuter" || retrieved.NominativeSg != "δάσος" {
+		t.Errorf("updated noun = %+v, want neuter δάσος", retrieved)
+	}
+
+	noun.ID = 999
+	if err := repo.UpdateNoun(noun); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateNoun() of a missing noun error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestListNouns(t *testing.T) {
 	repo := setupTestDB(t)
 	defer repo.Close()
@@ -105,6 +141,46 @@ func TestListNouns(t *testing.T) {
 	}
 }
 
+func TestSearchNouns(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	for _, noun := range []*models.Noun{
+		{English: "teacher", Gender: "masculine", NominativeSg: "δάσκαλος"},
+		{English: "book", Gender: "neuter", NominativeSg: "βιβλίο"},
+		{English: "notebook", Gender: "neuter", NominativeSg: "τετράδιο"},
+	} {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"teacher", "book", "notebook"}},
+		{"BOOK", []string{"book", "notebook"}},
+		{"δασκαλος", []string{"teacher"}},
+		{" τετράδ ", []string{"notebook"}},
+		{"cat", nil},
+	}
+
+	for _, tt := range tests {
+		nouns, err := repo.SearchNouns(tt.query)
+		if err != nil {
+			t.Fatalf("SearchNouns(%q) error = %v", tt.query, err)
+		}
+		var got []string
+		for _, noun := range nouns {
+			got = append(got, noun.English)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SearchNouns(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
 func TestCreateAndGetSentence(t *testing.T) {
 	repo := setupTestDB(t)
 	defer repo.Close()
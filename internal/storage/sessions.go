@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// SaveSession stores the state of an in-progress session, replacing the current
+// profile's earlier one
+func (r *SQLiteRepository) SaveSession(session *models.SavedSession) error {
+	state, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM saved_sessions WHERE profile_id = ?", r.profileID); err != nil {
+		return fmt.Errorf("failed to clear saved session: %w", err)
+	}
+	query := "INSERT INTO saved_sessions (profile_id, state) VALUES (?, ?)"
+	if _, err := tx.Exec(query, r.profileID, string(state)); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetSavedSession retrieves the current profile's saved session, or nil if there is none
+func (r *SQLiteRepository) GetSavedSession() (*models.SavedSession, error) {
+	var row struct {
+		State     string    `db:"state"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	query := "SELECT state, updated_at FROM saved_sessions WHERE profile_id = ? ORDER BY id DESC LIMIT 1"
+	err := r.db.Get(&row, query, r.profileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get saved session: %w", err)
+	}
+
+	var session models.SavedSession
+	if err := json.Unmarshal([]byte(row.State), &session); err != nil {
+		return nil, fmt.Errorf("failed to decode saved session: %w", err)
+	}
+	session.UpdatedAt = row.UpdatedAt
+	return &session, nil
+}
+
+// DeleteSavedSession discards the current profile's saved session, if any
+func (r *SQLiteRepository) DeleteSavedSession() error {
+	if _, err := r.db.Exec("DELETE FROM saved_sessions WHERE profile_id = ?", r.profileID); err != nil {
+		return fmt.Errorf("failed to delete saved session: %w", err)
+	}
+	return nil
+}
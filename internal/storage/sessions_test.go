@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestSavedSession(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	got, err := repo.GetSavedSession()
+	if err != nil {
+		t.Fatalf("GetSavedSession() error = %v", err)
+	}
+	if got != nil {
+		t.Fatalf("Expected no saved session in an empty database, got %+v", got)
+	}
+
+	first := &models.Sentence{NounID: 1, CorrectAnswer: "τον δάσκαλο", CaseType: "accusative", Number: "singular", TemplateID: 3}
+	second := &models.Sentence{NounID: 2, CorrectAnswer: "στη γυναίκα", CaseType: "accusative", Number: "singular", Contracted: true}
+	session := &models.SavedSession{
+		Config:         models.SessionConfig{QuestionCount: 2, Mode: "choice"},
+		Sentences:      []*models.Sentence{first, second},
+		CurrentIndex:   1,
+		IncorrectCount: 1,
+		Mistakes:       []*models.Sentence{first},
+		AnswerTime:     3 * time.Second,
+	}
+	if err := repo.SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	// Saving again replaces the earlier session
+	session.CurrentIndex = 2
+	session.CorrectCount = 1
+	if err := repo.SaveSession(session); err != nil {
+		t.Fatalf("SaveSession() error = %v", err)
+	}
+
+	got, err = repo.GetSavedSession()
+	if err != nil {
+		t.Fatalf("GetSavedSession() error = %v", err)
+	}
+	if got == nil {
+		t.Fatal("Expected a saved session")
+	}
+	if got.CurrentIndex != 2 || got.Answered() != 2 || got.Config.Mode != "choice" || got.AnswerTime != 3*time.Second {
+		t.Errorf("GetSavedSession() = %+v", got)
+	}
+	if len(got.Sentences) != 2 || got.Sentences[0].TemplateID != 3 || !got.Sentences[1].Contracted {
+		t.Errorf("Expected sentences to round-trip, got %+v", got.Sentences)
+	}
+	if len(got.Mistakes) != 1 || got.Mistakes[0].CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("Expected one mistake, got %+v", got.Mistakes)
+	}
+	if got.UpdatedAt.IsZero() {
+		t.Error("Expected UpdatedAt to be set")
+	}
+
+	if err := repo.DeleteSavedSession(); err != nil {
+		t.Fatalf("DeleteSavedSession() error = %v", err)
+	}
+	got, err = repo.GetSavedSession()
+	if err != nil {
+		t.Fatalf("GetSavedSession() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Expected no saved session after delete, got %+v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+// RecordTableDrillResults stores the cell results of one table drill in a single transaction
+func (r *SQLiteRepository) RecordTableDrillResults(results []*models.TableDrillResult) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO table_drill_results (
+			profile_id, noun_id, gender, declension_class, case_type, number, correct
+		) VALUES (
+			:profile_id, :noun_id, :gender, :declension_class, :case_type, :number, :correct
+		)
+	`
+	for _, result := range results {
+		result.ProfileID = r.profileID
+		res, err := tx.NamedExec(query, result)
+		if err != nil {
+			return fmt.Errorf("failed to record table drill result: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		result.ID = id
+	}
+
+	return tx.Commit()
+}
+
+// ListTableDrillStats aggregates the current profile's table drill results per gender, declension class and
+// cell, most missed first
+func (r *SQLiteRepository) ListTableDrillStats() ([]*models.TableDrillStat, error) {
+	var stats []*models.TableDrillStat
+	query := `
+		SELECT gender, declension_class, case_type, number,
+			COUNT(*) AS attempts,
+			SUM(CASE WHEN correct THEN 0 ELSE 1 END) AS misses
+		FROM table_drill_results
+		WHERE profile_id = ?
+		GROUP BY gender, declension_class, case_type, number
+		ORDER BY CAST(misses AS REAL) / attempts DESC, attempts DESC
+	`
+	if err := r.db.Select(&stats, query, r.profileID); err != nil {
+		return nil, fmt.Errorf("failed to list table drill stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/gataky/greekmaster/internal/models"
+)
+
+func TestTableDrillStats(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{
+		English:      "city",
+		Gender:       "feminine",
+		NominativeSg: "πόλη",
+		GenitiveSg:   "πόλης",
+		AccusativeSg: "πόλη",
+		NominativePl: "πόλεις",
+		GenitivePl:   "πόλεων",
+		AccusativePl: "πόλεις",
+		NomSgArticle: "η",
+		GenSgArticle: "της",
+		AccSgArticle: "την",
+		NomPlArticle: "οι",
+		GenPlArticle: "των",
+		AccPlArticle: "τις",
+	}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	result := func(caseType, number string, correct bool) *models.TableDrillResult {
+		return &models.TableDrillResult{
+			NounID:          noun.ID,
+			Gender:          "feminine",
+			DeclensionClass: "feminine_i_eis",
+			CaseType:        caseType,
+			Number:          number,
+			Correct:         correct,
+		}
+	}
+
+	drills := [][]*models.TableDrillResult{
+		{result("genitive", "plural", false), result("nominative", "singular", true)},
+		{result("genitive", "plural", false), result("nominative", "singular", true)},
+		{result("genitive", "plural", true), result("accusative", "plural", false)},
+	}
+	for _, results := range drills {
+		if err := repo.RecordTableDrillResults(results); err != nil {
+			t.Fatalf("RecordTableDrillResults() error = %v", err)
+		}
+		if results[0].ID == 0 {
+			t.Error("Expected result ID to be set after recording")
+		}
+	}
+
+	stats, err := repo.ListTableDrillStats()
+	if err != nil {
+		t.Fatalf("ListTableDrillStats() error = %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 cell stats, got %d", len(stats))
+	}
+
+	// Ordered by miss rate: accusative plural 1/1, genitive plural 2/3, nominative singular 0/2
+	want := []struct {
+		caseType, number string
+		attempts, misses int
+	}{
+		{"accusative", "plural", 1, 1},
+		{"genitive", "plural", 3, 2},
+		{"nominative", "singular", 2, 0},
+	}
+	for i, w := range want {
+		got := stats[i]
+		if got.CaseType != w.caseType || got.Number != w.number || got.Attempts != w.attempts || got.Misses != w.misses {
+			t.Errorf("stats[%d] = %s %s %d/%d, want %s %s %d/%d", i,
+				got.CaseType, got.Number, got.Misses, got.Attempts, w.caseType, w.number, w.misses, w.attempts)
+		}
+		if got.DeclensionClass != "feminine_i_eis" {
+			t.Errorf("stats[%d].DeclensionClass = %q, want feminine_i_eis", i, got.DeclensionClass)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// tagRow is a single row of a tag table
+type tagRow struct {
+	OwnerID int64  `db:"owner_id"`
+	Tag     string `db:"tag"`
+}
+
+// setTags replaces all tags for an owner row in the given tag table
+func (r *SQLiteRepository) setTags(table, column string, ownerID int64, tags []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceTags(tx, table, column, ownerID, tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceTags swaps an owner row's tags within a transaction
+func replaceTags(tx *sqlx.Tx, table, column string, ownerID int64, tags []string) error {
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, column), ownerID); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+
+	for _, tag := range models.NormalizeTags(tags) {
+		query := fmt.Sprintf("INSERT INTO %s (%s, tag) VALUES (?, ?)", table, column)
+		if _, err := tx.Exec(query, ownerID, tag); err != nil {
+			return fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// listTags loads every tag in the given tag table keyed by owner ID
+func (r *SQLiteRepository) listTags(table, column string) (map[int64][]string, error) {
+	var rows []tagRow
+	query := fmt.Sprintf("SELECT %s AS owner_id, tag FROM %s ORDER BY %s, tag", column, table, column)
+	if err := r.db.Select(&rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := make(map[int64][]string)
+	for _, row := range rows {
+		tags[row.OwnerID] = append(tags[row.OwnerID], row.Tag)
+	}
+	return tags, nil
+}
+
+// SetNounTags replaces the semantic tags of a noun
+func (r *SQLiteRepository) SetNounTags(nounID int64, tags []string) error {
+	return r.setTags("noun_tags", "noun_id", nounID, tags)
+}
+
+// ListNounTags retrieves the semantic tags of all nouns keyed by noun ID
+func (r *SQLiteRepository) ListNounTags() (map[int64][]string, error) {
+	return r.listTags("noun_tags", "noun_id")
+}
+
+// SetTemplateTags replaces the required tags of a template and marks it as classified,
+// so a template that accepts any noun isn't sent for tagging again
+func (r *SQLiteRepository) SetTemplateTags(templateID int64, tags []string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceTags(tx, "template_tags", "template_id", templateID, tags); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE sentence_templates SET tagged_at = CURRENT_TIMESTAMP WHERE id = ?", templateID); err != nil {
+		return fmt.Errorf("failed to mark template as tagged: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ListTemplateTags retrieves the required tags of all templates keyed by template ID
+func (r *SQLiteRepository) ListTemplateTags() (map[int64][]string, error) {
+	return r.listTags("template_tags", "template_id")
+}
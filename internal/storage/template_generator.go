@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/gataky/greekmaster/internal/grammar"
 	"github.com/gataky/greekmaster/internal/models"
 )
 
@@ -23,43 +28,145 @@ func getFieldValue(noun *models.Noun, fieldName string) (string, error) {
 	return field.String(), nil
 }
 
-// substituteTemplate generates a Sentence from a template and noun
+// agreementSlot matches agreement slots such as {verb:τρέχει|τρέχουν}
+var agreementSlot = regexp.MustCompile(`\{(\w+):([^{}]*)\}`)
+
+// genderIndex maps a noun gender to its position in a gender agreement slot.
+// Invariable nouns are almost always neuter loanwords, so they use the neuter variant.
+var genderIndex = map[string]int{
+	"masculine":  0,
+	"feminine":   1,
+	"neuter":     2,
+	"invariable": 2,
+}
+
+// resolveAgreement replaces agreement slots with the variant matching the noun.
+// A slot lists its variants separated by '|':
+//   - 2 variants: singular|plural
+//   - 3 variants: masculine|feminine|neuter
+//   - 6 variants: masculine, feminine, neuter singular, then the same in plural
+func resolveAgreement(text, gender, number string) (string, error) {
+	var resolveErr error
+
+	resolved := agreementSlot.ReplaceAllStringFunc(text, func(slot string) string {
+		match := agreementSlot.FindStringSubmatch(slot)
+		variants := strings.Split(match[2], "|")
+
+		plural := 0
+		if number == "plural" {
+			plural = 1
+		}
+		g, ok := genderIndex[gender]
+
+		switch len(variants) {
+		case 2:
+			return variants[plural]
+		case 3, 6:
+			if !ok {
+				resolveErr = fmt.Errorf("slot %s needs a gender, got %q", slot, gender)
+				return slot
+			}
+			if len(variants) == 3 {
+				return variants[g]
+			}
+			return variants[plural*3+g]
+		default:
+			resolveErr = fmt.Errorf("slot %s must have 2, 3 or 6 variants", slot)
+			return slot
+		}
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return resolved, nil
+}
+
+// contractPreposition replaces a contracting preposition written in front of the
+// {article} placeholder with an {article} slot for the fused form (σε {article} → στον).
+// It returns the rewritten template and the fused article, or "" if nothing contracts.
+func contractPreposition(greekTemplate string, prep *string, article string) (string, string) {
+	if prep == nil {
+		return greekTemplate, ""
+	}
+
+	contracted, ok := grammar.Contract(*prep, article)
+	if !ok {
+		return greekTemplate, ""
+	}
+
+	for _, pattern := range grammar.ContractionPatterns(*prep) {
+		if strings.Contains(greekTemplate, pattern) {
+			return strings.ReplaceAll(greekTemplate, pattern, "{article}"), contracted
+		}
+	}
+
+	return greekTemplate, ""
+}
+
+// substituteTemplate generates a Sentence from a template and noun.
+// Templates marked 'both' render in the number of their stored fields.
 func substituteTemplate(template *models.SentenceTemplate, noun *models.Noun) (*models.Sentence, error) {
+	number := template.Number
+	if number == "both" {
+		// Infer from the noun form field which number was used
+		if strings.Contains(template.NounFormField, "Sg") {
+			number = "singular"
+		} else if strings.Contains(template.NounFormField, "Pl") {
+			number = "plural"
+		}
+	}
+	return substituteTemplateNumber(template, noun, number)
+}
+
+// substituteTemplateNumber generates a Sentence from a template and noun in the given number.
+// For templates marked 'both' the article and noun form fields follow the requested number.
+func substituteTemplateNumber(template *models.SentenceTemplate, noun *models.Noun, number string) (*models.Sentence, error) {
+	articleField := template.ArticleField
+	nounFormField := template.NounFormField
+	if template.Number == "both" {
+		var err error
+		articleField, nounFormField, err = TemplateFields(template.CaseType, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve fields: %w", err)
+		}
+	}
+
 	// 1. Get the article value from noun
-	article, err := getFieldValue(noun, template.ArticleField)
+	article, err := getFieldValue(noun, articleField)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article field: %w", err)
 	}
 
 	// 2. Get the noun form value
-	nounForm, err := getFieldValue(noun, template.NounFormField)
+	nounForm, err := getFieldValue(noun, nounFormField)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get noun form field: %w", err)
 	}
 
 	// 3. Substitute English template
 	englishPrompt := strings.ReplaceAll(template.EnglishTemplate, "{noun}", noun.English)
+	englishPrompt, err = resolveAgreement(englishPrompt, noun.Gender, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve english agreement: %w", err)
+	}
 
-	// 4. Substitute Greek template
-	greekSentence := template.GreekTemplate
+	// 4. Substitute Greek template, fusing σε with the article where the template places it
+	greekSentence, contracted := contractPreposition(template.GreekTemplate, template.Preposition, article)
+	if contracted != "" {
+		article = contracted
+	}
 	greekSentence = strings.ReplaceAll(greekSentence, "{article}", article)
 	greekSentence = strings.ReplaceAll(greekSentence, "{noun_form}", nounForm)
+	greekSentence, err = resolveAgreement(greekSentence, noun.Gender, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve greek agreement: %w", err)
+	}
 
 	// 5. Generate correct answer (article + noun form)
 	correctAnswer := article + " " + nounForm
 
-	// 6. Determine the number for the sentence (map 'both' to actual number)
-	number := template.Number
-	if number == "both" {
-		// Infer from the noun form field which number was used
-		if strings.Contains(template.NounFormField, "Sg") {
-			number = "singular"
-		} else if strings.Contains(template.NounFormField, "Pl") {
-			number = "plural"
-		}
-	}
-
-	// 7. Create Sentence struct
+	// 6. Create Sentence struct
 	return &models.Sentence{
 		NounID:          noun.ID,
 		EnglishPrompt:   englishPrompt,
@@ -70,38 +177,124 @@ func substituteTemplate(template *models.SentenceTemplate, noun *models.Noun) (*
 		DifficultyPhase: template.DifficultyPhase,
 		ContextType:     template.ContextType,
 		Preposition:     template.Preposition,
+		Contracted:      contracted != "",
+		TemplateID:      template.ID,
 	}, nil
 }
 
-// GeneratePracticeSentences generates practice sentences from templates
-func (r *SQLiteRepository) GeneratePracticeSentences(phase int, number string, limit int) ([]*models.Sentence, error) {
-	// 1. Get all nouns
-	nouns, err := r.ListNouns()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nouns: %w", err)
+// TemplateFields returns the Noun article and form field names for a case and number
+func TemplateFields(caseType, number string) (articleField, nounFormField string, err error) {
+	var suffix string
+	switch number {
+	case "singular":
+		suffix = "Sg"
+	case "plural":
+		suffix = "Pl"
+	default:
+		return "", "", fmt.Errorf("invalid number %q", number)
+	}
+
+	switch caseType {
+	case "nominative":
+		return "Nom" + suffix + "Article", "Nominative" + suffix, nil
+	case "genitive":
+		return "Gen" + suffix + "Article", "Genitive" + suffix, nil
+	case "accusative":
+		return "Acc" + suffix + "Article", "Accusative" + suffix, nil
+	default:
+		return "", "", fmt.Errorf("invalid case type %q", caseType)
+	}
+}
+
+// containsPhrase reports whether phrase appears in text as whole words
+func containsPhrase(text, phrase string) bool {
+	for offset := 0; ; {
+		idx := strings.Index(text[offset:], phrase)
+		if idx == -1 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(phrase)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !unicode.IsLetter(before) && !unicode.IsLetter(after) {
+			return true
+		}
+		offset = start + 1
 	}
+}
 
+// ValidateTemplate checks that a template agrees with the grammar catalog and renders
+// correctly for every given noun. Each rendered sentence must contain the correct
+// answer verbatim and no leftover placeholders.
+func ValidateTemplate(template *models.SentenceTemplate, nouns []*models.Noun) error {
+	if !strings.Contains(template.EnglishTemplate, "{noun}") {
+		return fmt.Errorf("english template is missing {noun} placeholder")
+	}
+	if !strings.Contains(template.GreekTemplate, "{article} {noun_form}") {
+		return fmt.Errorf("greek template is missing {article} {noun_form} placeholder")
+	}
+	if err := grammar.Default().CheckTemplate(template.GreekTemplate, template.CaseType, template.ContextType, template.Preposition); err != nil {
+		return err
+	}
 	if len(nouns) == 0 {
-		return nil, fmt.Errorf("no nouns found in database")
+		return fmt.Errorf("no nouns to validate against")
 	}
 
-	// 2. Get templates matching filters (get more than needed for variety)
-	templateLimit := limit * 2
-	if templateLimit < 100 {
-		templateLimit = 100
+	numbers := []string{template.Number}
+	if template.Number == "both" {
+		numbers = []string{"singular", "plural"}
 	}
-	templates, err := r.GetRandomTemplates(phase, number, templateLimit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get templates: %w", err)
+
+	for _, noun := range nouns {
+		for _, number := range numbers {
+			sentence, err := substituteTemplateNumber(template, noun, number)
+			if err != nil {
+				return err
+			}
+
+			if strings.Contains(sentence.EnglishPrompt, "{") || strings.Contains(sentence.GreekSentence, "{") {
+				return fmt.Errorf("unresolved placeholder for noun '%s'", noun.English)
+			}
+
+			if !containsPhrase(sentence.GreekSentence, sentence.CorrectAnswer) {
+				return fmt.Errorf("answer '%s' does not appear in '%s'", sentence.CorrectAnswer, sentence.GreekSentence)
+			}
+		}
 	}
 
-	if len(templates) == 0 {
-		return nil, fmt.Errorf("no templates found for phase %d and number %s", phase, number)
+	return nil
+}
+
+// minCompatibleSentences is the smallest pool of confirmed pairs used before nouns
+// without tags for a template's requirements are admitted
+const minCompatibleSentences = 10
+
+// pairTemplates combines random templates with random nouns accepted by compatible
+// (any noun when nil), skipping combinations already recorded in used.
+// Templates marked 'both' render in the requested number, or a random one when number is empty.
+func pairTemplates(templates []*models.SentenceTemplate, nouns []*models.Noun, number string, limit int, used map[string]bool, compatible func(*models.SentenceTemplate, *models.Noun) bool) []*models.Sentence {
+	// Precompute the nouns each template may be paired with
+	pool := make([]*models.SentenceTemplate, 0, len(templates))
+	candidates := make([][]*models.Noun, 0, len(templates))
+	for _, template := range templates {
+		var matches []*models.Noun
+		for _, noun := range nouns {
+			if compatible == nil || compatible(template, noun) {
+				matches = append(matches, noun)
+			}
+		}
+		if len(matches) > 0 {
+			pool = append(pool, template)
+			candidates = append(candidates, matches)
+		}
 	}
 
-	// 3. Generate sentences by combining templates with random nouns
 	sentences := make([]*models.Sentence, 0, limit)
-	used := make(map[string]bool) // Track used combinations to avoid duplicates
+	if len(pool) == 0 {
+		return sentences
+	}
 
 	// Try to generate the requested number of sentences
 	attempts := 0
@@ -110,18 +303,28 @@ func (r *SQLiteRepository) GeneratePracticeSentences(phase int, number string, l
 	for len(sentences) < limit && attempts < maxAttempts {
 		attempts++
 
-		// Pick random template and noun
-		template := templates[rand.Intn(len(templates))]
-		noun := nouns[rand.Intn(len(nouns))]
+		// Pick random template and one of its compatible nouns
+		i := rand.Intn(len(pool))
+		template := pool[i]
+		noun := candidates[i][rand.Intn(len(candidates[i]))]
+
+		// Resolve the number for templates that support both
+		sentenceNumber := template.Number
+		if sentenceNumber == "both" {
+			sentenceNumber = number
+			if sentenceNumber == "" {
+				sentenceNumber = []string{"singular", "plural"}[rand.Intn(2)]
+			}
+		}
 
 		// Create unique key for this combination
-		key := fmt.Sprintf("%d-%d", template.ID, noun.ID)
+		key := fmt.Sprintf("%d-%d-%s", template.ID, noun.ID, sentenceNumber)
 		if used[key] {
 			continue // Skip if we've already used this combination
 		}
 
 		// Generate sentence
-		sentence, err := substituteTemplate(template, noun)
+		sentence, err := substituteTemplateNumber(template, noun, sentenceNumber)
 		if err != nil {
 			// Skip invalid combinations (e.g., field mismatch)
 			continue
@@ -131,7 +334,223 @@ func (r *SQLiteRepository) GeneratePracticeSentences(phase int, number string, l
 		used[key] = true
 	}
 
-	// If we couldn't generate enough unique combinations, that's okay
-	// Just return what we have
+	return sentences
+}
+
+// GeneratePracticeSentences generates practice sentences from templates
+func (r *SQLiteRepository) GeneratePracticeSentences(phase int, number string, limit int) ([]*models.Sentence, error) {
+	return r.GenerateFilteredSentences(phase, number, models.SessionFilter{}, limit)
+}
+
+// GenerateFilteredSentences generates practice sentences from the templates and nouns
+// that pass a session filter. Phase 0 draws from every difficulty phase.
+func (r *SQLiteRepository) GenerateFilteredSentences(phase int, number string, filter models.SessionFilter, limit int) ([]*models.Sentence, error) {
+	// 1. Get all nouns
+	nouns, err := r.ListNouns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nouns: %w", err)
+	}
+
+	if len(nouns) == 0 {
+		return nil, notFoundError("no nouns found in database")
+	}
+
+	// 2. Get templates matching filters (get more than needed for variety).
+	// A filter may discard most templates, so filtered sessions consider all of them.
+	templateLimit := limit * 2
+	if templateLimit < 100 {
+		templateLimit = 100
+	}
+	if !filter.IsEmpty() {
+		templateLimit = -1
+	}
+	templates, err := r.GetRandomTemplates(phase, number, templateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+
+	if len(templates) == 0 {
+		return nil, notFoundError(fmt.Sprintf("no templates found for phase %d and number %s", phase, number))
+	}
+
+	// 3. Load semantic tags so templates are only paired with compatible nouns
+	nounTags, err := r.ListNounTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get noun tags: %w", err)
+	}
+	templateTags, err := r.ListTemplateTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template tags: %w", err)
+	}
+
+	// 4. Apply the session filter
+	if !filter.IsEmpty() {
+		nouns = slices.DeleteFunc(nouns, func(noun *models.Noun) bool {
+			return !filter.MatchNoun(noun, nounTags[noun.ID])
+		})
+		templates = slices.DeleteFunc(templates, func(template *models.SentenceTemplate) bool {
+			return !filter.MatchTemplate(template)
+		})
+		if len(nouns) == 0 {
+			return nil, notFoundError("no nouns match the session filters")
+		}
+		if len(templates) == 0 {
+			return nil, notFoundError("no templates match the session filters")
+		}
+	}
+
+	// 5. Generate sentences by combining templates with nouns whose tags confirm
+	// the template's requirements
+	used := make(map[string]bool) // Track used combinations to avoid duplicates
+	sentences := pairTemplates(templates, nouns, number, limit, used, func(template *models.SentenceTemplate, noun *models.Noun) bool {
+		return models.TagsConfirmed(nounTags[noun.ID], templateTags[template.ID])
+	})
+
+	// When that pool is too small, admit nouns that lack tags for a requirement.
+	// Nouns whose tags conflict with a template are never paired with it.
+	if len(sentences) < min(limit, minCompatibleSentences) {
+		sentences = append(sentences, pairTemplates(templates, nouns, number, limit-len(sentences), used, func(template *models.SentenceTemplate, noun *models.Noun) bool {
+			return models.TagsCompatible(nounTags[noun.ID], templateTags[template.ID])
+		})...)
+	}
+
+	// A small compatible pool gives a shorter session rather than mismatched prompts
+	return sentences, nil
+}
+
+// GenerateAlternativeSentence builds a sentence for the same noun, case and number as
+// the given one from a different template within the session's difficulty and filter,
+// preferring templates the noun's tags are confirmed for. It returns nil when no other
+// compatible template fits.
+func (r *SQLiteRepository) GenerateAlternativeSentence(sentence *models.Sentence, config models.SessionConfig) (*models.Sentence, error) {
+	noun, err := r.GetNoun(sentence.NounID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := r.ListTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+
+	nounTags, err := r.ListNounTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get noun tags: %w", err)
+	}
+	templateTags, err := r.ListTemplateTags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template tags: %w", err)
+	}
+
+	var confirmed, compatible []*models.SentenceTemplate
+	for _, template := range templates {
+		if template.ID == sentence.TemplateID || template.CaseType != sentence.CaseType {
+			continue
+		}
+		if template.Number != sentence.Number && template.Number != "both" {
+			continue
+		}
+		if phase := config.Phase(); phase != 0 && template.DifficultyPhase != phase {
+			continue
+		}
+		if !config.Filter.MatchTemplate(template) {
+			continue
+		}
+		switch {
+		case models.TagsConfirmed(nounTags[noun.ID], templateTags[template.ID]):
+			confirmed = append(confirmed, template)
+		case models.TagsCompatible(nounTags[noun.ID], templateTags[template.ID]):
+			compatible = append(compatible, template)
+		}
+	}
+
+	for _, pool := range [][]*models.SentenceTemplate{confirmed, compatible} {
+		for _, i := range rand.Perm(len(pool)) {
+			alternative, err := substituteTemplateNumber(pool[i], noun, sentence.Number)
+			if err == nil {
+				return alternative, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// ExpectedSentence rebuilds a sentence from the stored noun and the template it was
+// generated from, so that answers are graded against the database rather than
+// against whatever a client sends back. Sentences without a template take the
+// noun's form for their case and number. It returns the rebuilt sentence and its noun.
+func ExpectedSentence(repo Repository, sentence *models.Sentence) (*models.Sentence, *models.Noun, error) {
+	if sentence.NounID == 0 {
+		return nil, nil, fmt.Errorf("%w: noun_id is required", ErrInvalidSentence)
+	}
+	noun, err := repo.GetNoun(sentence.NounID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sentence.TemplateID != 0 {
+		template, err := repo.GetTemplate(sentence.TemplateID)
+		if err != nil {
+			return nil, nil, err
+		}
+		number := template.Number
+		if number == "both" {
+			number = sentence.Number
+		}
+		expected, err := substituteTemplateNumber(template, noun, number)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSentence, err)
+		}
+		return expected, noun, nil
+	}
+
+	articleField, nounFormField, err := TemplateFields(sentence.CaseType, sentence.Number)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidSentence, err)
+	}
+	article, err := getFieldValue(noun, articleField)
+	if err != nil {
+		return nil, nil, err
+	}
+	nounForm, err := getFieldValue(noun, nounFormField)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expected := *sentence
+	expected.Contracted = false
+	if sentence.Contracted && sentence.Preposition != nil {
+		if contracted, ok := grammar.Contract(*sentence.Preposition, article); ok {
+			article = contracted
+			expected.Contracted = true
+		}
+	}
+	expected.CorrectAnswer = article + " " + nounForm
+	return &expected, noun, nil
+}
+
+// GenerateSessionSentences generates the shuffled sentences for a session,
+// drawing extra candidates for variety and keeping QuestionCount of them
+func GenerateSessionSentences(repo Repository, config models.SessionConfig, rng *rand.Rand) ([]*models.Sentence, error) {
+	limit := 1000
+	if config.QuestionCount > 0 {
+		limit = config.QuestionCount * 2 // Get extra for variety
+	}
+
+	sentences, err := repo.GenerateFilteredSentences(config.Phase(), config.NumberFilter(), config.Filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sentences: %w", err)
+	}
+
+	rng.Shuffle(len(sentences), func(i, j int) {
+		sentences[i], sentences[j] = sentences[j], sentences[i]
+	})
+
+	// Limit to question count if set
+	if config.QuestionCount > 0 && len(sentences) > config.QuestionCount {
+		sentences = sentences[:config.QuestionCount]
+	}
+
 	return sentences, nil
 }
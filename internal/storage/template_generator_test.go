@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -369,3 +370,502 @@ func TestGeneratePracticeSentencesFiltering(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplateFields(t *testing.T) {
+	tests := []struct {
+		caseType    string
+		number      string
+		wantArticle string
+		wantForm    string
+		wantErr     bool
+	}{
+		{"accusative", "singular", "AccSgArticle", "AccusativeSg", false},
+		{"genitive", "plural", "GenPlArticle", "GenitivePl", false},
+		{"nominative", "singular", "NomSgArticle", "NominativeSg", false},
+		{"dative", "singular", "", "", true},
+		{"genitive", "both", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.caseType+"/"+tt.number, func(t *testing.T) {
+			article, form, err := TemplateFields(tt.caseType, tt.number)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TemplateFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if article != tt.wantArticle || form != tt.wantForm {
+				t.Errorf("TemplateFields() = (%v, %v), want (%v, %v)", article, form, tt.wantArticle, tt.wantForm)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	nouns := []*models.Noun{
+		{
+			English: "teacher", Gender: "masculine",
+			GenitiveSg: "δασκάλου", GenSgArticle: "του",
+		},
+		{
+			English: "woman", Gender: "feminine",
+			GenitiveSg: "γυναίκας", GenSgArticle: "της",
+		},
+	}
+
+	base := models.SentenceTemplate{
+		EnglishTemplate: "The bag of ___ (the {noun})",
+		GreekTemplate:   "Η τσάντα {article} {noun_form}",
+		ArticleField:    "GenSgArticle",
+		NounFormField:   "GenitiveSg",
+		CaseType:        "genitive",
+		Number:          "singular",
+		DifficultyPhase: 2,
+		ContextType:     "possession",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*models.SentenceTemplate)
+		nouns   []*models.Noun
+		wantErr bool
+	}{
+		{"valid", func(*models.SentenceTemplate) {}, nouns, false},
+		{"missing english placeholder", func(tmpl *models.SentenceTemplate) {
+			tmpl.EnglishTemplate = "The bag of ___"
+		}, nouns, true},
+		{"missing greek placeholder", func(tmpl *models.SentenceTemplate) {
+			tmpl.GreekTemplate = "Η τσάντα {noun_form}"
+		}, nouns, true},
+		{"contracted article", func(tmpl *models.SentenceTemplate) {
+			tmpl.GreekTemplate = "Πάω σ{article} {noun_form}"
+		}, nouns, true},
+		{"unknown placeholder", func(tmpl *models.SentenceTemplate) {
+			tmpl.GreekTemplate = "Η {adjective} τσάντα {article} {noun_form}"
+		}, nouns, true},
+		{"preposition governs another case", func(tmpl *models.SentenceTemplate) {
+			prep := "σε"
+			tmpl.ContextType = "preposition"
+			tmpl.Preposition = &prep
+			tmpl.GreekTemplate = "Πάω σε {article} {noun_form}"
+		}, nouns, true},
+		{"invalid field", func(tmpl *models.SentenceTemplate) {
+			tmpl.NounFormField = "DativeSg"
+		}, nouns, true},
+		{"no nouns", func(*models.SentenceTemplate) {}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := base
+			tt.modify(&tmpl)
+			err := ValidateTemplate(&tmpl, tt.nouns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeneratePracticeSentencesTagCompatibility(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	person := &models.Noun{English: "teacher", Gender: "masculine", AccSgArticle: "τον", AccusativeSg: "δάσκαλο"}
+	afternoon := &models.Noun{English: "afternoon", Gender: "neuter", AccSgArticle: "το", AccusativeSg: "απόγευμα"}
+	for _, noun := range []*models.Noun{person, afternoon} {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+	if err := repo.SetNounTags(person.ID, []string{"person", "count"}); err != nil {
+		t.Fatalf("SetNounTags() error = %v", err)
+	}
+	if err := repo.SetNounTags(afternoon.ID, []string{"time", "count"}); err != nil {
+		t.Fatalf("SetNounTags() error = %v", err)
+	}
+
+	feed := &models.SentenceTemplate{
+		EnglishTemplate: "I feed ___ (the {noun})",
+		GreekTemplate:   "Ταΐζω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(feed); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+	if err := repo.SetTemplateTags(feed.ID, []string{"person", "animal"}); err != nil {
+		t.Fatalf("SetTemplateTags() error = %v", err)
+	}
+
+	// With a limit of one the compatible pool is large enough, so no fallback occurs
+	for i := 0; i < 20; i++ {
+		sentences, err := repo.GeneratePracticeSentences(1, "singular", 1)
+		if err != nil {
+			t.Fatalf("GeneratePracticeSentences() error = %v", err)
+		}
+		if len(sentences) != 1 {
+			t.Fatalf("GeneratePracticeSentences() returned %d sentences, want 1", len(sentences))
+		}
+		if sentences[0].NounID != person.ID {
+			t.Fatalf("Template paired with incompatible noun %d", sentences[0].NounID)
+		}
+	}
+
+	// A larger request comes up short rather than pairing incompatible nouns
+	sentences, err := repo.GeneratePracticeSentences(1, "singular", 2)
+	if err != nil {
+		t.Fatalf("GeneratePracticeSentences() error = %v", err)
+	}
+	if len(sentences) != 1 || sentences[0].NounID != person.ID {
+		t.Errorf("GeneratePracticeSentences() returned %d sentences, want only the compatible one", len(sentences))
+	}
+
+	// A noun without a class tag is admitted once the confirmed pool runs short
+	cat := &models.Noun{English: "cat", Gender: "feminine", AccSgArticle: "τη", AccusativeSg: "γάτα"}
+	if err := repo.CreateNoun(cat); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+	sentences, err = repo.GeneratePracticeSentences(1, "singular", 3)
+	if err != nil {
+		t.Fatalf("GeneratePracticeSentences() error = %v", err)
+	}
+	if len(sentences) != 2 {
+		t.Fatalf("GeneratePracticeSentences() returned %d sentences, want the tagged and untagged nouns", len(sentences))
+	}
+	for _, sentence := range sentences {
+		if sentence.NounID == afternoon.ID {
+			t.Errorf("Template paired with incompatible noun %d", sentence.NounID)
+		}
+	}
+}
+
+func TestResolveAgreement(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		gender  string
+		number  string
+		want    string
+		wantErr bool
+	}{
+		{"number singular", "Ο δάσκαλος {verb:τρέχει|τρέχουν}", "masculine", "singular", "Ο δάσκαλος τρέχει", false},
+		{"number plural", "Οι δάσκαλοι {verb:τρέχει|τρέχουν}", "masculine", "plural", "Οι δάσκαλοι τρέχουν", false},
+		{"empty variant", "the {noun}{pl:|s}", "neuter", "singular", "the {noun}", false},
+		{"gender only", "{adj:καλός|καλή|καλό}", "feminine", "singular", "καλή", false},
+		{"gender and number", "{adj:σπασμένος|σπασμένη|σπασμένο|σπασμένοι|σπασμένες|σπασμένα}", "feminine", "plural", "σπασμένες", false},
+		{"invariable uses neuter", "{adj:σπασμένος|σπασμένη|σπασμένο|σπασμένοι|σπασμένες|σπασμένα}", "invariable", "singular", "σπασμένο", false},
+		{"no slots", "Βλέπω τον δάσκαλο", "masculine", "singular", "Βλέπω τον δάσκαλο", false},
+		{"wrong variant count", "{adj:α|β|γ|δ}", "masculine", "singular", "", true},
+		{"missing gender", "{adj:καλός|καλή|καλό}", "", "singular", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAgreement(tt.text, tt.gender, tt.number)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveAgreement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAgreement() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteTemplateNumberBoth(t *testing.T) {
+	nurse := &models.Noun{
+		ID: 1, English: "nurse", Gender: "feminine",
+		NominativeSg: "νοσοκόμα", NomSgArticle: "η",
+		NominativePl: "νοσοκόμες", NomPlArticle: "οι",
+	}
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "___ (the {noun}{pl:|s}) {be:is|are} tired",
+		GreekTemplate:   "{article} {noun_form} {verb:είναι|είναι} {adj:κουρασμένος|κουρασμένη|κουρασμένο|κουρασμένοι|κουρασμένες|κουρασμένα}",
+		ArticleField:    "NomSgArticle",
+		NounFormField:   "NominativeSg",
+		CaseType:        "nominative",
+		Number:          "both",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+
+	tests := []struct {
+		number  string
+		wantEng string
+		wantGr  string
+		wantAns string
+	}{
+		{"singular", "___ (the nurse) is tired", "η νοσοκόμα είναι κουρασμένη", "η νοσοκόμα"},
+		{"plural", "___ (the nurses) are tired", "οι νοσοκόμες είναι κουρασμένες", "οι νοσοκόμες"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			got, err := substituteTemplateNumber(template, nurse, tt.number)
+			if err != nil {
+				t.Fatalf("substituteTemplateNumber() error = %v", err)
+			}
+			if got.EnglishPrompt != tt.wantEng {
+				t.Errorf("EnglishPrompt = %q, want %q", got.EnglishPrompt, tt.wantEng)
+			}
+			if got.GreekSentence != tt.wantGr {
+				t.Errorf("GreekSentence = %q, want %q", got.GreekSentence, tt.wantGr)
+			}
+			if got.CorrectAnswer != tt.wantAns {
+				t.Errorf("CorrectAnswer = %q, want %q", got.CorrectAnswer, tt.wantAns)
+			}
+			if got.Number != tt.number {
+				t.Errorf("Number = %q, want %q", got.Number, tt.number)
+			}
+		})
+	}
+
+	if err := ValidateTemplate(template, []*models.Noun{nurse}); err != nil {
+		t.Errorf("ValidateTemplate() error = %v", err)
+	}
+}
+
+func TestSubstituteTemplateContraction(t *testing.T) {
+	noun := &models.Noun{
+		ID: 1, English: "teacher", Gender: "masculine",
+		AccusativeSg: "δάσκαλο", AccSgArticle: "τον",
+		AccusativePl: "δασκάλους", AccPlArticle: "τους",
+	}
+
+	se := "σε"
+	gia := "για"
+
+	tests := []struct {
+		name           string
+		greek          string
+		prep           *string
+		articleField   string
+		nounFormField  string
+		wantGr         string
+		wantAns        string
+		wantContracted bool
+	}{
+		{"σε contracts", "Πάω σε {article} {noun_form}", &se, "AccSgArticle", "AccusativeSg", "Πάω στον δάσκαλο", "στον δάσκαλο", true},
+		{"elided σ form", "Πάμε σ{article} {noun_form} στην αγορά", &se, "AccPlArticle", "AccusativePl", "Πάμε στους δασκάλους στην αγορά", "στους δασκάλους", true},
+		{"σε elsewhere in sentence", "Βάζω {article} {noun_form} στο τραπέζι", &se, "AccSgArticle", "AccusativeSg", "Βάζω τον δάσκαλο στο τραπέζι", "τον δάσκαλο", false},
+		{"non-contracting preposition", "Μιλάει για {article} {noun_form}", &gia, "AccSgArticle", "AccusativeSg", "Μιλάει για τον δάσκαλο", "τον δάσκαλο", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &models.SentenceTemplate{
+				EnglishTemplate: "I go to ___ (the {noun})",
+				GreekTemplate:   tt.greek,
+				ArticleField:    tt.articleField,
+				NounFormField:   tt.nounFormField,
+				CaseType:        "accusative",
+				Number:          "singular",
+				DifficultyPhase: 3,
+				ContextType:     "preposition",
+				Preposition:     tt.prep,
+			}
+
+			got, err := substituteTemplate(template, noun)
+			if err != nil {
+				t.Fatalf("substituteTemplate() error = %v", err)
+			}
+			if got.GreekSentence != tt.wantGr {
+				t.Errorf("GreekSentence = %q, want %q", got.GreekSentence, tt.wantGr)
+			}
+			if got.CorrectAnswer != tt.wantAns {
+				t.Errorf("CorrectAnswer = %q, want %q", got.CorrectAnswer, tt.wantAns)
+			}
+			if got.Contracted != tt.wantContracted {
+				t.Errorf("Contracted = %v, want %v", got.Contracted, tt.wantContracted)
+			}
+			if err := ValidateTemplate(template, []*models.Noun{noun}); err != nil {
+				t.Errorf("ValidateTemplate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateAlternativeSentence(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{English: "teacher", Gender: "masculine", AccSgArticle: "τον", AccusativeSg: "δάσκαλο",
+		GenSgArticle: "του", GenitiveSg: "δασκάλου"}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+
+	newTemplate := func(english, greek, caseType, article, form string) *models.SentenceTemplate {
+		template := &models.SentenceTemplate{
+			EnglishTemplate: english,
+			GreekTemplate:   greek,
+			ArticleField:    article,
+			NounFormField:   form,
+			CaseType:        caseType,
+			Number:          "singular",
+			DifficultyPhase: 1,
+			ContextType:     "direct_object",
+		}
+		if err := repo.CreateTemplate(template); err != nil {
+			t.Fatalf("CreateTemplate() error = %v", err)
+		}
+		return template
+	}
+	see := newTemplate("I see ___ (the {noun})", "Βλέπω {article} {noun_form}", "accusative", "AccSgArticle", "AccusativeSg")
+	newTemplate("The book of ___ (the {noun})", "Το βιβλίο {article} {noun_form}", "genitive", "GenSgArticle", "GenitiveSg")
+
+	missed, err := substituteTemplate(see, noun)
+	if err != nil {
+		t.Fatalf("substituteTemplate() error = %v", err)
+	}
+
+	// Only the genitive template is left, which doesn't fit the accusative
+	alternative, err := repo.GenerateAlternativeSentence(missed, models.SessionConfig{})
+	if err != nil {
+		t.Fatalf("GenerateAlternativeSentence() error = %v", err)
+	}
+	if alternative != nil {
+		t.Fatalf("GenerateAlternativeSentence() = %q, want nil", alternative.GreekSentence)
+	}
+
+	wait := newTemplate("I wait for ___ (the {noun})", "Περιμένω {article} {noun_form}", "accusative", "AccSgArticle", "AccusativeSg")
+	alternative, err = repo.GenerateAlternativeSentence(missed, models.SessionConfig{})
+	if err != nil {
+		t.Fatalf("GenerateAlternativeSentence() error = %v", err)
+	}
+	if alternative == nil {
+		t.Fatal("GenerateAlternativeSentence() = nil, want a sentence")
+	}
+	if alternative.TemplateID != wait.ID || alternative.CorrectAnswer != "τον δάσκαλο" {
+		t.Errorf("GenerateAlternativeSentence() = template %d %q, want template %d τον δάσκαλο",
+			alternative.TemplateID, alternative.CorrectAnswer, wait.ID)
+	}
+
+	// Templates outside the session's difficulty or filter are never used
+	for _, config := range []models.SessionConfig{
+		{DifficultyLevel: "advanced"},
+		{Filter: models.SessionFilter{ContextTypes: []string{"possession"}}},
+	} {
+		alternative, err = repo.GenerateAlternativeSentence(missed, config)
+		if err != nil {
+			t.Fatalf("GenerateAlternativeSentence() error = %v", err)
+		}
+		if alternative != nil {
+			t.Errorf("GenerateAlternativeSentence(%+v) = %q, want nil", config, alternative.GreekSentence)
+		}
+	}
+	alternative, err = repo.GenerateAlternativeSentence(missed, models.SessionConfig{DifficultyLevel: "beginner"})
+	if err != nil || alternative == nil || alternative.TemplateID != wait.ID {
+		t.Errorf("GenerateAlternativeSentence(beginner) = %v, %v; want template %d", alternative, err, wait.ID)
+	}
+}
+
+func TestExpectedSentence(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	noun := &models.Noun{English: "teacher", Gender: "masculine", AccSgArticle: "τον", AccusativeSg: "δάσκαλο",
+		AccPlArticle: "τους", AccusativePl: "δασκάλους"}
+	if err := repo.CreateNoun(noun); err != nil {
+		t.Fatalf("CreateNoun() error = %v", err)
+	}
+	se := "σε"
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I go to ___ (the {noun})",
+		GreekTemplate:   "Πηγαίνω σε {article} {noun_form}",
+		CaseType:        "accusative",
+		Number:          "both",
+		DifficultyPhase: 1,
+		ContextType:     "preposition",
+		Preposition:     &se,
+	}
+	template.ArticleField, template.NounFormField, _ = TemplateFields("accusative", "singular")
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		sentence *models.Sentence
+		want     string
+		wantErr  error
+	}{
+		{"template, forged answer", &models.Sentence{NounID: noun.ID, TemplateID: template.ID, Number: "plural", CorrectAnswer: "ο δάσκαλος"}, "στους δασκάλους", nil},
+		{"no template", &models.Sentence{NounID: noun.ID, CaseType: "accusative", Number: "singular", CorrectAnswer: "ο δάσκαλος"}, "τον δάσκαλο", nil},
+		{"no template, contracted", &models.Sentence{NounID: noun.ID, CaseType: "accusative", Number: "singular", Preposition: &se, Contracted: true}, "στον δάσκαλο", nil},
+		{"unknown noun", &models.Sentence{NounID: 99, CaseType: "accusative", Number: "singular"}, "", ErrNotFound},
+		{"unknown template", &models.Sentence{NounID: noun.ID, TemplateID: 99}, "", ErrNotFound},
+		{"invalid case", &models.Sentence{NounID: noun.ID, CaseType: "dative", Number: "singular"}, "", ErrInvalidSentence},
+		{"missing noun", &models.Sentence{CaseType: "accusative", Number: "singular"}, "", ErrInvalidSentence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, _, err := ExpectedSentence(repo, tt.sentence)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ExpectedSentence() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpectedSentence() error = %v", err)
+			}
+			if expected.CorrectAnswer != tt.want {
+				t.Errorf("ExpectedSentence() answer = %q, want %q", expected.CorrectAnswer, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateFilteredSentences(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	teacher := &models.Noun{English: "teacher", Gender: "masculine", AccSgArticle: "τον", AccusativeSg: "δάσκαλο",
+		GenSgArticle: "του", GenitiveSg: "δασκάλου"}
+	cat := &models.Noun{English: "cat", Gender: "feminine", AccSgArticle: "τη", AccusativeSg: "γάτα",
+		GenSgArticle: "της", GenitiveSg: "γάτας"}
+	for _, noun := range []*models.Noun{teacher, cat} {
+		if err := repo.CreateNoun(noun); err != nil {
+			t.Fatalf("CreateNoun() error = %v", err)
+		}
+	}
+
+	templates := []*models.SentenceTemplate{
+		{EnglishTemplate: "I see ___ (the {noun})", GreekTemplate: "Βλέπω {article} {noun_form}",
+			ArticleField: "AccSgArticle", NounFormField: "AccusativeSg", CaseType: "accusative",
+			Number: "singular", DifficultyPhase: 1, ContextType: "direct_object"},
+		{EnglishTemplate: "The name of ___ (the {noun})", GreekTemplate: "Το όνομα {article} {noun_form}",
+			ArticleField: "GenSgArticle", NounFormField: "GenitiveSg", CaseType: "genitive",
+			Number: "singular", DifficultyPhase: 2, ContextType: "possession"},
+	}
+	for _, template := range templates {
+		if err := repo.CreateTemplate(template); err != nil {
+			t.Fatalf("CreateTemplate() error = %v", err)
+		}
+	}
+
+	// Phase 0 draws from every phase, narrowed by the filter
+	filter := models.SessionFilter{Genders: []string{"feminine"}, Cases: []string{"genitive"}}
+	sentences, err := repo.GenerateFilteredSentences(0, "singular", filter, 10)
+	if err != nil {
+		t.Fatalf("GenerateFilteredSentences() error = %v", err)
+	}
+	if len(sentences) != 1 {
+		t.Fatalf("GenerateFilteredSentences() returned %d sentences, want 1", len(sentences))
+	}
+	if sentences[0].CorrectAnswer != "της γάτας" {
+		t.Errorf("Expected 'της γάτας', got %q", sentences[0].CorrectAnswer)
+	}
+
+	// A filter nothing matches is an error rather than an empty session
+	filter = models.SessionFilter{NounRanges: []models.IDRange{{Min: 100, Max: 200}}}
+	if _, err := repo.GenerateFilteredSentences(0, "singular", filter, 10); err == nil {
+		t.Error("Expected error when no nouns match the filter")
+	}
+}
@@ -38,7 +38,7 @@ func (r *SQLiteRepository) GetTemplate(id int64) (*models.SentenceTemplate, erro
 	err := r.db.Get(&template, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("template not found with id %d", id)
+			return nil, fmt.Errorf("template %w with id %d", ErrNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get template: %w", err)
 	}
@@ -56,21 +56,41 @@ func (r *SQLiteRepository) ListTemplates() ([]*models.SentenceTemplate, error) {
 	return templates, nil
 }
 
+// UpdateTemplate saves the text and fields of an existing template
+func (r *SQLiteRepository) UpdateTemplate(template *models.SentenceTemplate) error {
+	query := `
+		UPDATE sentence_templates SET
+			english_template = :english_template, greek_template = :greek_template,
+			article_field = :article_field, noun_form_field = :noun_form_field,
+			case_type = :case_type, number = :number, difficulty_phase = :difficulty_phase,
+			context_type = :context_type, preposition = :preposition
+		WHERE id = :id
+	`
+	result, err := r.db.NamedExec(query, template)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return fmt.Errorf("template %w with id %d", ErrNotFound, template.ID)
+	}
+	return nil
+}
+
 // GetRandomTemplates retrieves random templates filtered by phase and number
 func (r *SQLiteRepository) GetRandomTemplates(phase int, number string, limit int) ([]*models.SentenceTemplate, error) {
 	var templates []*models.SentenceTemplate
 	var query string
 	var args []interface{}
 
-	// Build query based on number filter
+	// Build query based on number filter; phase 0 matches every phase
 	if number == "" || number == "both" {
 		// Include templates for singular, plural, and both
-		query = "SELECT * FROM sentence_templates WHERE difficulty_phase = ? AND (number = 'singular' OR number = 'plural' OR number = 'both') ORDER BY RANDOM() LIMIT ?"
-		args = []interface{}{phase, limit}
+		query = "SELECT * FROM sentence_templates WHERE (? = 0 OR difficulty_phase = ?) AND (number = 'singular' OR number = 'plural' OR number = 'both') ORDER BY RANDOM() LIMIT ?"
+		args = []interface{}{phase, phase, limit}
 	} else {
 		// Filter by specific number (singular or plural), but also include templates marked as 'both'
-		query = "SELECT * FROM sentence_templates WHERE difficulty_phase = ? AND (number = ? OR number = 'both') ORDER BY RANDOM() LIMIT ?"
-		args = []interface{}{phase, number, limit}
+		query = "SELECT * FROM sentence_templates WHERE (? = 0 OR difficulty_phase = ?) AND (number = ? OR number = 'both') ORDER BY RANDOM() LIMIT ?"
+		args = []interface{}{phase, phase, number, limit}
 	}
 
 	err := r.db.Select(&templates, query, args...)
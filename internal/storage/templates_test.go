@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/gataky/greekmaster/internal/models"
@@ -73,6 +74,43 @@ func TestGetTemplate(t *testing.T) {
 	}
 }
 
+func TestUpdateTemplate(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "The smell of {noun} is nice",
+		GreekTemplate:   "Η μυρωδιά {article} {noun_form} είναι ωραία",
+		ArticleField:    "GenSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "genitive",
+		Number:          "singular",
+		DifficultyPhase: 2,
+		ContextType:     "possession",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	template.NounFormField = "GenitiveSg"
+	if err := repo.UpdateTemplate(template); err != nil {
+		t.Fatalf("UpdateTemplate() error = %v", err)
+	}
+
+	retrieved, err := repo.GetTemplate(template.ID)
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+	if retrieved.NounFormField != "GenitiveSg" || retrieved.GreekTemplate != template.GreekTemplate {
+		t.Errorf("updated template = %+v", retrieved)
+	}
+
+	template.ID = 999
+	if err := repo.UpdateTemplate(template); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateTemplate() of a missing template error = %v, want ErrNotFound", err)
+	}
+}
+
 func TestListTemplates(t *testing.T) {
 	repo := setupTestDB(t)
 	defer repo.Close()
@@ -272,3 +310,49 @@ func TestTemplateConstraints(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTemplateTagsMarksTagged(t *testing.T) {
+	repo := setupTestDB(t)
+	defer repo.Close()
+
+	template := &models.SentenceTemplate{
+		EnglishTemplate: "I see {noun}",
+		GreekTemplate:   "Βλέπω {article} {noun_form}",
+		ArticleField:    "AccSgArticle",
+		NounFormField:   "AccusativeSg",
+		CaseType:        "accusative",
+		Number:          "singular",
+		DifficultyPhase: 1,
+		ContextType:     "direct_object",
+	}
+	if err := repo.CreateTemplate(template); err != nil {
+		t.Fatalf("CreateTemplate() error = %v", err)
+	}
+
+	got, err := repo.GetTemplate(template.ID)
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+	if got.TaggedAt != nil {
+		t.Errorf("Expected a new template to be unclassified, got TaggedAt %v", got.TaggedAt)
+	}
+
+	// A template that accepts any noun has no tags but is remembered as classified
+	if err := repo.SetTemplateTags(template.ID, nil); err != nil {
+		t.Fatalf("SetTemplateTags() error = %v", err)
+	}
+	got, err = repo.GetTemplate(template.ID)
+	if err != nil {
+		t.Fatalf("GetTemplate() error = %v", err)
+	}
+	if got.TaggedAt == nil {
+		t.Error("Expected SetTemplateTags() to mark the template as classified")
+	}
+	tags, err := repo.ListTemplateTags()
+	if err != nil {
+		t.Fatalf("ListTemplateTags() error = %v", err)
+	}
+	if len(tags[template.ID]) != 0 {
+		t.Errorf("Expected no tags, got %v", tags[template.ID])
+	}
+}
@@ -1,14 +1,16 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gataky/greekmaster/internal/grading"
 	"github.com/gataky/greekmaster/internal/models"
 )
 
 // RenderFeedback renders the feedback screen after an answer
-func RenderFeedback(isCorrect bool, userAnswer string, sentence *models.Sentence, explanation *models.Explanation, terminalWidth int) string {
+func RenderFeedback(isCorrect bool, hintsUsed int, userAnswer string, sentence *models.Sentence, explanation *models.Explanation, terminalWidth int) string {
 	var s strings.Builder
 
 	borderStyle := lipgloss.NewStyle().
@@ -55,7 +57,10 @@ func RenderFeedback(isCorrect bool, userAnswer string, sentence *models.Sentence
 		MarginTop(1)
 
 	// Header
-	if isCorrect {
+	if isCorrect && hintsUsed > 0 {
+		s.WriteString(correctStyle.Render(fmt.Sprintf("✓ Correct! (%d %s, score %.2f)",
+			hintsUsed, plural(hintsUsed, "hint", "hints"), grading.Score(true, hintsUsed))))
+	} else if isCorrect {
 		s.WriteString(correctStyle.Render("✓ Correct!"))
 	} else {
 		s.WriteString(incorrectStyle.Render("✗ Incorrect"))
@@ -83,6 +88,25 @@ func RenderFeedback(isCorrect bool, userAnswer string, sentence *models.Sentence
 
 		s.WriteString(labelStyle.Render("Morphology: "))
 		s.WriteString(textStyle.Render(explanation.Morphology))
+
+		if explanation.Pattern != "" {
+			s.WriteString("\n\n")
+			s.WriteString(labelStyle.Render("Pattern: "))
+			s.WriteString(textStyle.Render(explanation.Pattern))
+		}
+
+		if explanation.Paradigm != "" {
+			s.WriteString("\n\n")
+			s.WriteString(labelStyle.Render("Paradigm:"))
+			s.WriteString("\n")
+			s.WriteString(textStyle.Render(explanation.Paradigm))
+		}
+
+		if explanation.Usage != "" {
+			s.WriteString("\n\n")
+			s.WriteString(labelStyle.Render("Usage: "))
+			s.WriteString(textStyle.Render(explanation.Usage))
+		}
 	} else {
 		s.WriteString(textStyle.Render("Full Greek sentence: " + sentence.GreekSentence))
 	}
@@ -92,3 +116,11 @@ func RenderFeedback(isCorrect bool, userAnswer string, sentence *models.Sentence
 
 	return borderStyle.Render(s.String())
 }
+
+// plural picks the singular or plural form of a word for a count
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -2,103 +2,96 @@ package tui
 
 import (
 	"fmt"
-	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grading"
 	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/session"
 	"github.com/gataky/greekmaster/internal/storage"
 )
 
-// PracticeModel represents the practice session
+// PracticeModel represents the practice session. The session package runs the
+// session; the model renders it and forwards keys.
 type PracticeModel struct {
-	repo            storage.Repository
-	config          models.SessionConfig
-	sentences       []*models.Sentence
-	currentIndex    int
-	userInput       string
-	state           string // "question", "feedback", "complete"
-	correctCount    int
-	incorrectCount  int
-	currentSentence *models.Sentence
-	isCorrect       bool
-	explanation     *models.Explanation
-	err             error
-	rng             *rand.Rand // Random number generator
-	width           int        // Terminal width
+	session   *session.Session
+	userInput string
+	clockID   int // Identifies the running clock so stale ticks are dropped
+	err       error
+	width     int // Terminal width
 }
 
 // NewPracticeModel creates a new practice model
 func NewPracticeModel(repo storage.Repository, config models.SessionConfig) (*PracticeModel, error) {
-	// Map difficulty to phase
-	phaseMap := map[string]int{
-		"beginner":     1,
-		"intermediate": 2,
-		"advanced":     3,
-	}
-	phase := phaseMap[config.DifficultyLevel]
-
-	// Determine number filter
-	numberFilter := "singular"
-	if config.IncludePlural {
-		numberFilter = "" // Empty means both
+	sess, err := session.New(repo, config)
+	if sess == nil {
+		return nil, err
 	}
+	return &PracticeModel{session: sess, err: err, width: 80}, nil
+}
 
-	// Generate sentences from templates
-	limit := 1000
-	if config.QuestionCount > 0 {
-		limit = config.QuestionCount * 2 // Get extra for variety
+// ResumePracticeModel restores a practice session saved by an earlier run
+func ResumePracticeModel(repo storage.Repository, saved *models.SavedSession) (*PracticeModel, error) {
+	sess, err := session.Resume(repo, saved)
+	if sess == nil {
+		return nil, err
 	}
+	return &PracticeModel{session: sess, err: err, width: 80}, nil
+}
 
-	sentences, err := repo.GeneratePracticeSentences(phase, numberFilter, limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate sentences: %w", err)
+// submitAnswer grades the current input and shows its feedback
+func (m *PracticeModel) submitAnswer() {
+	if _, err := m.session.Submit(m.userInput); err != nil {
+		m.err = err
 	}
+}
 
-	if len(sentences) == 0 {
-		return nil, fmt.Errorf("no sentences found for this difficulty level. Please run 'greekmaster import' first")
-	}
+// clockInterval is how often the countdown and sprint clocks are checked
+const clockInterval = 250 * time.Millisecond
 
-	// Create random number generator
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+// clockTickMsg drives the countdown and sprint clocks
+type clockTickMsg struct {
+	id int
+}
 
-	// Shuffle sentences
-	rng.Shuffle(len(sentences), func(i, j int) {
-		sentences[i], sentences[j] = sentences[j], sentences[i]
+// tick schedules the next clock check
+func (m *PracticeModel) tick() tea.Cmd {
+	id := m.clockID
+	return tea.Tick(clockInterval, func(time.Time) tea.Msg {
+		return clockTickMsg{id: id}
 	})
+}
 
-	// Limit to question count if set
-	if config.QuestionCount > 0 && len(sentences) > config.QuestionCount {
-		sentences = sentences[:config.QuestionCount]
-	}
+// clockNeeded reports whether a countdown or sprint clock has to run
+func (m *PracticeModel) clockNeeded() bool {
+	return m.session.Config().TimeLimit > 0 || !m.session.SprintEnd().IsZero()
+}
 
-	model := &PracticeModel{
-		repo:         repo,
-		config:       config,
-		sentences:    sentences,
-		currentIndex: 0,
-		state:        "question",
-		rng:          rng,
-		width:        80, // Default width
+// restartClock replaces any running clock, returning nil when nothing is timed
+func (m *PracticeModel) restartClock() tea.Cmd {
+	m.clockID++
+	if !m.clockNeeded() {
+		return nil
 	}
-
-	// Load first sentence
-	model.loadCurrentSentence()
-
-	return model, nil
+	return m.tick()
 }
 
-func (m *PracticeModel) loadCurrentSentence() {
-	if m.currentIndex < len(m.sentences) {
-		m.currentSentence = m.sentences[m.currentIndex]
+// timeLeft returns the time remaining until deadline, rounded up to whole seconds
+func timeLeft(deadline time.Time) time.Duration {
+	left := time.Until(deadline)
+	if left <= 0 {
+		return 0
 	}
+	return (left + time.Second - 1).Truncate(time.Second)
 }
 
 func (m PracticeModel) Init() tea.Cmd {
-	return nil
+	if !m.clockNeeded() {
+		return nil
+	}
+	return m.tick()
 }
 
 func (m PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -107,34 +100,45 @@ func (m PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		return m, nil
 
+	case clockTickMsg:
+		if msg.id != m.clockID || m.session.State() == session.StateComplete {
+			return m, nil
+		}
+
+		// The sprint ends the session; a question left unanswered doesn't count
+		if m.session.SprintOver() {
+			if err := m.session.Finish(); err != nil {
+				m.err = err
+			}
+			return m, nil
+		}
+
+		if m.session.QuestionTimedOut() {
+			m.submitAnswer()
+		}
+		return m, m.tick()
+
 	case tea.KeyMsg:
-		switch m.state {
-		case "question":
+		switch m.session.State() {
+		case session.StateQuestion:
 			switch msg.String() {
 			case "ctrl+c", "q":
+				// Keep the session so the next run can resume it
+				if err := m.session.Save(); err != nil {
+					m.err = err
+				}
 				return m, tea.Quit
 
-			case "enter":
-				// Submit answer
-				m.isCorrect = m.validateAnswer()
-				if m.isCorrect {
-					m.correctCount++
-				} else {
-					m.incorrectCount++
-				}
+			case "tab":
+				// Reveal the next hint
+				m.session.RevealHint()
+				return m, nil
 
-				// Generate explanation using template
-				noun, err := m.repo.GetNoun(m.currentSentence.NounID)
-				if err != nil {
-					m.err = err
-				} else {
-					m.explanation, err = explanations.Generate(m.currentSentence, noun)
-					if err != nil {
-						m.err = err
-					}
+			case "enter":
+				if m.session.Config().Mode == "choice" {
+					return m, nil
 				}
-
-				m.state = "feedback"
+				m.submitAnswer()
 				return m, nil
 
 			case "backspace":
@@ -147,49 +151,52 @@ func (m PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 			default:
+				// In choice mode a number key picks an option
+				if m.session.Config().Mode == "choice" {
+					key := msg.String()
+					choices := m.session.Choices()
+					if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+						if choice := int(key[0] - '1'); choice < len(choices) {
+							m.userInput = choices[choice]
+							m.submitAnswer()
+						}
+					}
+					return m, nil
+				}
+
 				// Add character to input
 				if len(msg.Runes) > 0 {
 					m.userInput += string(msg.Runes)
 				}
 			}
 
-		case "feedback":
+		case session.StateFeedback:
 			// Any key continues to next question
-			m.currentIndex++
 			m.userInput = ""
-
-			// Check if we're done
-			if m.config.QuestionCount > 0 && m.currentIndex >= len(m.sentences) {
-				m.state = "complete"
-			} else if m.currentIndex >= len(m.sentences) {
-				// Endless mode - reshuffle and continue
-				m.rng.Shuffle(len(m.sentences), func(i, j int) {
-					m.sentences[i], m.sentences[j] = m.sentences[j], m.sentences[i]
-				})
-				m.currentIndex = 0
-				m.loadCurrentSentence()
-				m.state = "question"
-			} else {
-				m.loadCurrentSentence()
-				m.state = "question"
+			if err := m.session.Next(); err != nil {
+				m.err = err
 			}
 			return m, nil
 
-		case "complete":
+		case session.StateComplete:
 			switch msg.String() {
 			case "q":
 				return m, tea.Quit
+			case "m":
+				if m.session.CanReview() {
+					if err := m.session.Review(); err != nil {
+						m.err = err
+					}
+					m.userInput = ""
+					return m, m.restartClock()
+				}
 			case "r":
 				// Restart session
-				m.currentIndex = 0
-				m.correctCount = 0
-				m.incorrectCount = 0
+				if err := m.session.Restart(); err != nil {
+					m.err = err
+				}
 				m.userInput = ""
-				m.rng.Shuffle(len(m.sentences), func(i, j int) {
-					m.sentences[i], m.sentences[j] = m.sentences[j], m.sentences[i]
-				})
-				m.loadCurrentSentence()
-				m.state = "question"
+				return m, m.restartClock()
 			}
 		}
 	}
@@ -197,18 +204,13 @@ func (m PracticeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *PracticeModel) validateAnswer() bool {
-	// Exact Unicode string comparison
-	return strings.TrimSpace(m.userInput) == strings.TrimSpace(m.currentSentence.CorrectAnswer)
-}
-
 func (m PracticeModel) View() string {
-	switch m.state {
-	case "question":
+	switch m.session.State() {
+	case session.StateQuestion:
 		return m.renderQuestion()
-	case "feedback":
+	case session.StateFeedback:
 		return m.renderFeedback()
-	case "complete":
+	case session.StateComplete:
 		return m.renderComplete()
 	default:
 		return ""
@@ -243,32 +245,133 @@ func (m PracticeModel) renderQuestion() string {
 
 	// Header
 	var header string
-	if m.config.QuestionCount > 0 {
-		header = fmt.Sprintf("Greek Case Master - Question %d/%d", m.currentIndex+1, len(m.sentences))
+	progress := m.session.Progress()
+	if progress.ReviewRound > 0 {
+		header = fmt.Sprintf("Greek Case Master - Review %d - Question %d/%d", progress.ReviewRound, progress.Question, progress.Total)
+	} else if !progress.Endless {
+		header = fmt.Sprintf("Greek Case Master - Question %d/%d", progress.Question, progress.Total)
 	} else {
-		header = fmt.Sprintf("Greek Case Master - Question %d (Endless)", m.currentIndex+1)
+		header = fmt.Sprintf("Greek Case Master - Question %d (Endless)", progress.Question)
 	}
 	s.WriteString(titleStyle.Render(header))
-	s.WriteString("\n\n")
+	s.WriteString("\n")
+	if clock := m.renderClock(); clock != "" {
+		s.WriteString(clock)
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
 
 	// Prompt
-	s.WriteString(promptStyle.Render(m.currentSentence.EnglishPrompt))
+	s.WriteString(promptStyle.Render(m.session.Sentence().EnglishPrompt))
 	s.WriteString("\n\n")
 
+	// Revealed hints
+	if hints := m.session.Hints(); len(hints) > 0 {
+		revealedStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214"))
+		for i, hint := range hints {
+			s.WriteString(revealedStyle.Render(fmt.Sprintf("Hint %d: %s", i+1, hint)))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	// Key help, offering a hint while any remain
+	help := "[Ctrl+C or q to quit]"
+	if m.session.HintsLeft() > 0 {
+		help = fmt.Sprintf("[Tab for hint (-%d%%)] %s", int(grading.HintPenalty*100), help)
+	}
+
+	if choices := m.session.Choices(); m.session.Config().Mode == "choice" {
+		// Options
+		s.WriteString("Choose the answer:\n")
+		for i, choice := range choices {
+			s.WriteString(inputStyle.Render(fmt.Sprintf("  %d. %s", i+1, choice)))
+			s.WriteString("\n")
+		}
+
+		// Hints
+		s.WriteString(hintStyle.Render(fmt.Sprintf("[1-%d to answer] %s", len(choices), help)))
+
+		return borderStyle.Render(s.String())
+	}
+
 	// Input
 	s.WriteString("Your answer:\n")
 	s.WriteString(inputStyle.Render("> " + m.userInput + "_"))
 	s.WriteString("\n")
 
 	// Hints
-	s.WriteString(hintStyle.Render("[Enter to submit] [Ctrl+C or q to quit]"))
+	s.WriteString(hintStyle.Render("[Enter to submit] " + help))
 
 	return borderStyle.Render(s.String())
 }
 
+// renderClock shows the question countdown and the sprint time left, if any
+func (m PracticeModel) renderClock() string {
+	clockStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214"))
+
+	var parts []string
+	if deadline := m.session.QuestionDeadline(); !deadline.IsZero() {
+		left := timeLeft(deadline)
+		parts = append(parts, fmt.Sprintf("⏱ %ds", int(left.Seconds())))
+	}
+	if sprint := m.sprintLeft(); sprint != "" {
+		parts = append(parts, sprint)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return clockStyle.Render(strings.Join(parts, "  "))
+}
+
+// sprintLeft describes the time left in a running sprint
+func (m PracticeModel) sprintLeft() string {
+	sprintEnd := m.session.SprintEnd()
+	if sprintEnd.IsZero() {
+		return ""
+	}
+	left := int(timeLeft(sprintEnd).Seconds())
+	return fmt.Sprintf("Sprint %d:%02d left", left/60, left%60)
+}
+
 func (m PracticeModel) renderFeedback() string {
+	result := m.session.Result()
+	userAnswer := result.Answer
+	if result.TimedOut {
+		userAnswer = strings.TrimSpace(userAnswer + " (time's up)")
+	}
+
 	// Delegate to feedback.go
-	return RenderFeedback(m.isCorrect, m.userInput, m.currentSentence, m.explanation, m.width)
+	feedback := RenderFeedback(result.Correct, result.HintsUsed, userAnswer, result.Sentence, result.Explanation, m.width)
+
+	var notes []string
+	if result.Slow {
+		notes = append(notes, fmt.Sprintf("⏱ Correct but slow (%.1fs) - added to review", result.Latency.Seconds()))
+	}
+	if sprint := m.sprintLeft(); sprint != "" {
+		notes = append(notes, sprint)
+	}
+	if len(notes) == 0 {
+		return feedback
+	}
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214"))
+	return feedback + "\n" + noteStyle.Render(strings.Join(notes, "  "))
+}
+
+// reviewSummary describes what the next review round covers
+func reviewSummary(summary session.Summary) string {
+	var parts []string
+	if summary.Missed > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", summary.Missed, plural(summary.Missed, "mistake", "mistakes")))
+	}
+	if summary.Slow > 0 {
+		parts = append(parts, fmt.Sprintf("%d slow %s", summary.Slow, plural(summary.Slow, "answer", "answers")))
+	}
+	return strings.Join(parts, " + ")
 }
 
 func (m PracticeModel) renderComplete() string {
@@ -294,18 +397,33 @@ func (m PracticeModel) renderComplete() string {
 	s.WriteString(titleStyle.Render("Session Complete!"))
 	s.WriteString("\n\n")
 
-	total := m.correctCount + m.incorrectCount
-	accuracy := 0
-	if total > 0 {
-		accuracy = (m.correctCount * 100) / total
-	}
+	summary := m.session.Summary()
+	total := summary.Answered
 
-	s.WriteString(statsStyle.Render(fmt.Sprintf("Answered: %d/%d", total, len(m.sentences))))
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Answered: %d/%d", total, summary.Length)))
 	s.WriteString("\n")
-	s.WriteString(statsStyle.Render(fmt.Sprintf("Accuracy: %d%%", accuracy)))
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Accuracy: %d%%", summary.Accuracy)))
 	s.WriteString("\n")
+	if summary.TotalHints > 0 {
+		s.WriteString(statsStyle.Render(fmt.Sprintf("Score: %.2f/%d (%d hints used)", summary.Score, total, summary.TotalHints)))
+		s.WriteString("\n")
+	}
+	if total > 0 && summary.AnswerTime > 0 {
+		s.WriteString(statsStyle.Render(fmt.Sprintf("Speed: %.1f answers/min (%.1fs per answer)",
+			grading.AnswersPerMinute(total, summary.AnswerTime), summary.AnswerTime.Seconds()/float64(total))))
+		s.WriteString("\n")
+	}
+	if summary.ReviewRounds > 0 {
+		s.WriteString(statsStyle.Render(fmt.Sprintf("All mistakes corrected ✓ (%d review %s)",
+			summary.ReviewRounds, plural(summary.ReviewRounds, "round", "rounds"))))
+		s.WriteString("\n")
+	}
 
-	s.WriteString(hintStyle.Render("\n[q] Quit  [r] Restart session"))
+	if m.session.CanReview() {
+		s.WriteString(hintStyle.Render(fmt.Sprintf("\n[m] Review %s  [q] Quit  [r] Restart session", reviewSummary(summary))))
+	} else {
+		s.WriteString(hintStyle.Render("\n[q] Quit  [r] Restart session"))
+	}
 
 	return borderStyle.Render(s.String())
 }
@@ -0,0 +1,439 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/session"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// reverseField is one of the choices the learner makes about the highlighted noun phrase
+type reverseField struct {
+	label   string
+	options []string
+}
+
+// reverseFields lists the selectable fields in display order; the lemma field follows them
+var reverseFields = []reverseField{
+	{"Case", []string{"nominative", "genitive", "accusative"}},
+	{"Number", []string{"singular", "plural"}},
+	{"Gender", []string{"masculine", "feminine", "neuter"}},
+}
+
+// identificationRow is one line of the per-field feedback
+type identificationRow struct {
+	label    string
+	ok       bool
+	answered string
+	expected string
+}
+
+// lemmaField is the cursor position of the optional lemma input
+var lemmaField = len(reverseFields)
+
+// ReverseModel represents a recognition session: the learner reads a Greek sentence
+// and identifies the case, number and gender of the highlighted noun phrase
+type ReverseModel struct {
+	session    *session.Session
+	selections []int // Chosen option per field, -1 when unset
+	lemma      string
+	cursor     int
+	err        error
+	width      int
+}
+
+// NewReverseModel creates a new recognition session
+func NewReverseModel(repo storage.Repository, config models.SessionConfig) (*ReverseModel, error) {
+	sess, err := session.New(repo, config)
+	if sess == nil {
+		return nil, err
+	}
+	return newReverseModel(sess, err), nil
+}
+
+// ResumeReverseModel continues a recognition session saved by an earlier run
+func ResumeReverseModel(repo storage.Repository, saved *models.SavedSession) (*ReverseModel, error) {
+	sess, err := session.Resume(repo, saved)
+	if sess == nil {
+		return nil, err
+	}
+	return newReverseModel(sess, err), nil
+}
+
+func newReverseModel(sess *session.Session, err error) *ReverseModel {
+	model := &ReverseModel{
+		session: sess,
+		err:     err,
+		width:   80, // Default width
+	}
+	model.clearSelections()
+	return model
+}
+
+// clearSelections resets the fields for a new question
+func (m *ReverseModel) clearSelections() {
+	m.selections = make([]int, len(reverseFields))
+	for i := range m.selections {
+		m.selections[i] = -1
+	}
+	m.lemma = ""
+	m.cursor = 0
+}
+
+// setErr shows a session error without interrupting practice
+func (m *ReverseModel) setErr(err error) {
+	if err != nil {
+		m.err = err
+	}
+}
+
+func (m ReverseModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ReverseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.session.State() {
+		case session.StateQuestion:
+			switch msg.String() {
+			case "ctrl+c":
+				// Keep the session so the next run can resume it
+				m.setErr(m.session.Save())
+				return m, tea.Quit
+
+			case "up", "shift+tab":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+
+			case "down", "tab":
+				if m.cursor < lemmaField {
+					m.cursor++
+				}
+
+			case "left", "right":
+				if m.cursor == lemmaField {
+					return m, nil
+				}
+				options := len(reverseFields[m.cursor].options)
+				if msg.String() == "right" {
+					m.selections[m.cursor] = (m.selections[m.cursor] + 1) % options
+				} else {
+					m.selections[m.cursor] = (m.selections[m.cursor] + options - 1) % options
+				}
+
+			case "enter":
+				if m.session.Noun() == nil || !m.complete() {
+					return m, nil
+				}
+				_, err := m.session.SubmitIdentification(grading.Identification{
+					CaseType: m.selected(0),
+					Number:   m.selected(1),
+					Gender:   m.selected(2),
+					Lemma:    m.lemma,
+				})
+				m.setErr(err)
+
+			case "backspace":
+				runes := []rune(m.lemma)
+				if m.cursor == lemmaField && len(runes) > 0 {
+					m.lemma = string(runes[:len(runes)-1])
+				}
+
+			default:
+				if m.cursor == lemmaField && len(msg.Runes) > 0 {
+					m.lemma += string(msg.Runes)
+				} else if msg.String() == "q" {
+					m.setErr(m.session.Save())
+					return m, tea.Quit
+				}
+			}
+
+		case session.StateFeedback:
+			// Any key continues to next question
+			m.setErr(m.session.Next())
+			m.clearSelections()
+			return m, nil
+
+		case session.StateComplete:
+			switch msg.String() {
+			case "q":
+				return m, tea.Quit
+			case "m":
+				if m.session.CanReview() {
+					m.setErr(m.session.Review())
+					m.clearSelections()
+				}
+			case "r":
+				// Restart session
+				m.setErr(m.session.Restart())
+				m.clearSelections()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// complete reports whether case, number and gender have all been chosen
+func (m ReverseModel) complete() bool {
+	for _, selection := range m.selections {
+		if selection < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// selected returns the chosen option of a field
+func (m ReverseModel) selected(field int) string {
+	if m.selections[field] < 0 {
+		return ""
+	}
+	return reverseFields[field].options[m.selections[field]]
+}
+
+func (m ReverseModel) View() string {
+	switch m.session.State() {
+	case session.StateQuestion:
+		return m.renderQuestion()
+	case session.StateFeedback:
+		return m.renderFeedback()
+	case session.StateComplete:
+		return m.renderComplete()
+	default:
+		return ""
+	}
+}
+
+// highlightedSentence renders the Greek sentence with the noun phrase emphasized
+func (m ReverseModel) highlightedSentence() string {
+	highlightStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Bold(true).
+		Underline(true)
+
+	sentence := m.session.Sentence().GreekSentence
+	answer := m.session.Sentence().CorrectAnswer
+	return strings.Replace(sentence, answer, highlightStyle.Render(answer), 1)
+}
+
+func (m ReverseModel) renderQuestion() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39"))
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		MarginTop(1).
+		MarginBottom(1)
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")).
+		Bold(true)
+
+	fieldStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true).
+		MarginTop(1)
+
+	// Header
+	var header string
+	progress := m.session.Progress()
+	if progress.ReviewRound > 0 {
+		header = fmt.Sprintf("Greek Case Master - Review %d - Question %d/%d", progress.ReviewRound, progress.Question, progress.Total)
+	} else if !progress.Endless {
+		header = fmt.Sprintf("Greek Case Master - Question %d/%d", progress.Question, progress.Total)
+	} else {
+		header = fmt.Sprintf("Greek Case Master - Question %d (Endless)", progress.Question)
+	}
+	s.WriteString(titleStyle.Render(header))
+	s.WriteString("\n\n")
+
+	// Sentence
+	s.WriteString(promptStyle.Render(m.highlightedSentence()))
+	s.WriteString("\n\n")
+	s.WriteString("Identify the highlighted noun phrase:\n")
+
+	// Fields
+	for i, field := range reverseFields {
+		value := m.selected(i)
+		if value == "" {
+			value = "?"
+		}
+		line := fmt.Sprintf("%-8s ◀ %s ▶", field.label+":", value)
+		if i == m.cursor {
+			s.WriteString(activeStyle.Render("> " + line))
+		} else {
+			s.WriteString(fieldStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+
+	lemma := fmt.Sprintf("%-8s %s", "Lemma:", m.lemma)
+	if m.cursor == lemmaField {
+		s.WriteString(activeStyle.Render("> " + lemma + "_"))
+	} else {
+		s.WriteString(fieldStyle.Render("  " + lemma + " (optional)"))
+	}
+	s.WriteString("\n")
+
+	// Hints
+	s.WriteString(hintStyle.Render("[↑/↓ to move] [←/→ to choose] [Enter to submit] [Ctrl+C to quit]"))
+
+	return borderStyle.Render(s.String())
+}
+
+func (m ReverseModel) renderFeedback() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	correctStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("120"))
+
+	incorrectStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("196"))
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Bold(true)
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true).
+		MarginTop(1)
+
+	// Header
+	result := m.session.Result()
+	grade := result.Identification
+	noun := m.session.Noun()
+	if result.Correct {
+		s.WriteString(correctStyle.Render("✓ Correct!"))
+	} else {
+		s.WriteString(incorrectStyle.Render("✗ Incorrect"))
+	}
+	s.WriteString("\n\n")
+	s.WriteString(textStyle.Render(m.highlightedSentence()))
+	s.WriteString("\n\n")
+
+	// Per-field results
+	mark := func(ok bool) string {
+		if ok {
+			return correctStyle.Render("✓")
+		}
+		return incorrectStyle.Render("✗")
+	}
+	rows := []identificationRow{
+		{"Case", grade.Case, m.selected(0), result.Sentence.CaseType},
+		{"Number", grade.Number, m.selected(1), result.Sentence.Number},
+		{"Gender", grade.Gender, m.selected(2), grammar.GrammaticalGender(noun)},
+	}
+	if strings.TrimSpace(m.lemma) != "" {
+		rows = append(rows, identificationRow{"Lemma", grade.Lemma, strings.TrimSpace(m.lemma), noun.NomSgArticle + " " + noun.NominativeSg})
+	}
+	for _, row := range rows {
+		s.WriteString(fmt.Sprintf("%s %s ", mark(row.ok), labelStyle.Render(fmt.Sprintf("%-8s", row.label+":"))))
+		if row.ok {
+			s.WriteString(textStyle.Render(row.expected))
+		} else {
+			s.WriteString(textStyle.Render(fmt.Sprintf("%s (you chose %s)", row.expected, row.answered)))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(strings.Repeat("─", 50))
+	s.WriteString("\n\n")
+
+	// Explanation
+	if explanation := result.Explanation; explanation != nil {
+		s.WriteString(labelStyle.Render("Translation: "))
+		s.WriteString(textStyle.Render(explanation.Translation))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Syntactic Role: "))
+		s.WriteString(textStyle.Render(explanation.SyntacticRole))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Morphology: "))
+		s.WriteString(textStyle.Render(explanation.Morphology))
+	} else if m.err != nil {
+		s.WriteString(incorrectStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(hintStyle.Render("\n[Press any key to continue]"))
+
+	return borderStyle.Render(s.String())
+}
+
+func (m ReverseModel) renderComplete() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("120")).
+		MarginBottom(1)
+
+	statsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1)
+
+	s.WriteString(titleStyle.Render("Session Complete!"))
+	s.WriteString("\n\n")
+
+	summary := m.session.Summary()
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Answered: %d/%d", summary.Answered, summary.Length)))
+	s.WriteString("\n")
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Accuracy: %d%%", summary.Accuracy)))
+	s.WriteString("\n")
+	if summary.ReviewRounds > 0 {
+		s.WriteString(statsStyle.Render(fmt.Sprintf("All mistakes corrected ✓ (%d review %s)",
+			summary.ReviewRounds, plural(summary.ReviewRounds, "round", "rounds"))))
+		s.WriteString("\n")
+	}
+
+	if m.session.CanReview() {
+		s.WriteString(hintStyle.Render(fmt.Sprintf("\n[m] Review %s  [q] Quit  [r] Restart session", reviewSummary(summary))))
+	} else {
+		s.WriteString(hintStyle.Render("\n[q] Quit  [r] Restart session"))
+	}
+
+	return borderStyle.Render(s.String())
+}
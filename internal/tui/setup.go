@@ -11,15 +11,33 @@ import (
 
 // SetupModel represents the session setup screen
 type SetupModel struct {
-	step          int // 0=difficulty, 1=plural, 2=session type
+	step          int // 0=difficulty, 1=plural, 2=gender, 3=case (advanced only), 4=session type
 	difficulty    string
 	includePlural bool
+	gender        string // "" for all genders
+	caseType      string // "" for all cases
 	questionCount int
 	quitting      bool
 	complete      bool
 	err           error
 }
 
+// setupGenders maps the gender step's keys to a gender filter
+var setupGenders = map[string]string{
+	"1": "",
+	"2": "masculine",
+	"3": "feminine",
+	"4": "neuter",
+}
+
+// setupCases maps the case step's keys to a case filter
+var setupCases = map[string]string{
+	"1": "",
+	"2": "nominative",
+	"3": "genitive",
+	"4": "accusative",
+}
+
 // NewSetupModel creates a new setup model
 func NewSetupModel() SetupModel {
 	return SetupModel{
@@ -42,8 +60,8 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 
-		case "1", "2", "3":
-			if m.step == 0 {
+		case "1", "2", "3", "4":
+			if m.step == 0 && msg.String() != "4" {
 				// Difficulty selection
 				switch msg.String() {
 				case "1":
@@ -55,6 +73,18 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.step = 1
 			} else if m.step == 2 {
+				// Gender filter
+				m.gender = setupGenders[msg.String()]
+				m.step = 3
+				if m.difficulty != "advanced" {
+					// Only advanced sessions mix cases
+					m.step = 4
+				}
+			} else if m.step == 3 {
+				// Case filter
+				m.caseType = setupCases[msg.String()]
+				m.step = 4
+			} else if m.step == 4 {
 				// Session type selection
 				switch msg.String() {
 				case "1":
@@ -63,20 +93,15 @@ func (m SetupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.questionCount = 25
 				case "3":
 					m.questionCount = 50
+				case "4":
+					// Endless mode
+					m.questionCount = 0
 				}
 				// Setup complete
 				m.complete = true
 				return m, tea.Quit
 			}
 
-		case "4":
-			if m.step == 2 {
-				// Endless mode
-				m.questionCount = 0
-				m.complete = true
-				return m, tea.Quit
-			}
-
 		case "y", "Y":
 			if m.step == 1 {
 				m.includePlural = true
@@ -122,9 +147,9 @@ func (m SetupModel) View() string {
 		s.WriteString("\n\n")
 		s.WriteString(optionStyle.Render("  1. Beginner     - Focus on accusative (direct objects)"))
 		s.WriteString("\n")
-		s.WriteString(optionStyle.Render("  2. Intermediate - Focus on genitive (possession)"))
+		s.WriteString(optionStyle.Render("  2. Intermediate - Focus on genitive (possession, recipients)"))
 		s.WriteString("\n")
-		s.WriteString(optionStyle.Render("  3. Advanced     - Mixed cases with prepositions"))
+		s.WriteString(optionStyle.Render("  3. Advanced     - Mixed cases with prepositions and time"))
 		s.WriteString("\n\n")
 
 	case 1:
@@ -138,6 +163,45 @@ func (m SetupModel) View() string {
 		s.WriteString("\n")
 		s.WriteString(questionStyle.Render(fmt.Sprintf("Plural forms: %v", m.includePlural)))
 		s.WriteString("\n\n")
+		s.WriteString(questionStyle.Render("Practice nouns of which gender?"))
+		s.WriteString("\n\n")
+		s.WriteString(optionStyle.Render("  1. All genders"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  2. Masculine"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  3. Feminine"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  4. Neuter"))
+		s.WriteString("\n\n")
+
+	case 3:
+		s.WriteString(questionStyle.Render(fmt.Sprintf("Difficulty: %s", m.difficulty)))
+		s.WriteString("\n")
+		s.WriteString(questionStyle.Render(fmt.Sprintf("Gender: %s", orAll(m.gender))))
+		s.WriteString("\n\n")
+		s.WriteString(questionStyle.Render("Focus on a case?"))
+		s.WriteString("\n\n")
+		s.WriteString(optionStyle.Render("  1. All cases"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  2. Nominative"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  3. Genitive"))
+		s.WriteString("\n")
+		s.WriteString(optionStyle.Render("  4. Accusative"))
+		s.WriteString("\n\n")
+
+	case 4:
+		s.WriteString(questionStyle.Render(fmt.Sprintf("Difficulty: %s", m.difficulty)))
+		s.WriteString("\n")
+		s.WriteString(questionStyle.Render(fmt.Sprintf("Plural forms: %v", m.includePlural)))
+		s.WriteString("\n")
+		s.WriteString(questionStyle.Render(fmt.Sprintf("Gender: %s", orAll(m.gender))))
+		s.WriteString("\n")
+		if m.difficulty == "advanced" {
+			s.WriteString(questionStyle.Render(fmt.Sprintf("Case: %s", orAll(m.caseType))))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
 		s.WriteString(questionStyle.Render("Select session type:"))
 		s.WriteString("\n\n")
 		s.WriteString(optionStyle.Render("  1. Quick (10 questions)"))
@@ -160,11 +224,27 @@ func (m SetupModel) GetConfig() (models.SessionConfig, bool) {
 	if !m.complete {
 		return models.SessionConfig{}, false
 	}
-	return models.SessionConfig{
+	config := models.SessionConfig{
 		DifficultyLevel: m.difficulty,
 		IncludePlural:   m.includePlural,
 		QuestionCount:   m.questionCount,
-	}, true
+		Mode:            "sentence",
+	}
+	if m.gender != "" {
+		config.Filter.Genders = []string{m.gender}
+	}
+	if m.caseType != "" {
+		config.Filter.Cases = []string{m.caseType}
+	}
+	return config, true
+}
+
+// orAll displays an unset filter value as "all"
+func orAll(value string) string {
+	if value == "" {
+		return "all"
+	}
+	return value
 }
 
 // SessionConfigMsg is sent when setup is complete
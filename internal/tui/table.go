@@ -0,0 +1,542 @@
+package tui
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gataky/greekmaster/internal/explanations"
+	"github.com/gataky/greekmaster/internal/grading"
+	"github.com/gataky/greekmaster/internal/grammar"
+	"github.com/gataky/greekmaster/internal/models"
+	"github.com/gataky/greekmaster/internal/storage"
+)
+
+// tableCell identifies one cell of the declension grid
+type tableCell struct {
+	caseType string
+	number   string
+}
+
+// tableCells lists the grid cells in input order: down the singular column, then the plural one
+var tableCells = []tableCell{
+	{"nominative", "singular"},
+	{"genitive", "singular"},
+	{"accusative", "singular"},
+	{"nominative", "plural"},
+	{"genitive", "plural"},
+	{"accusative", "plural"},
+}
+
+// tableCases lists the grid rows in display order
+var tableCases = []string{"nominative", "genitive", "accusative"}
+
+// maxMissedHints is how many frequently missed cells are shown after a table
+const maxMissedHints = 2
+
+// TableDrillModel represents a declension table drill session
+type TableDrillModel struct {
+	repo         storage.Repository
+	config       models.SessionConfig
+	nouns        []*models.Noun
+	currentIndex int
+	currentNoun  *models.Noun
+	inputs       []string
+	cursor       int
+	results      []bool
+	state        string // "question", "feedback", "complete"
+	correctCells int
+	totalCells   int
+	perfectNouns int
+	stats        []*models.TableDrillStat
+	err          error
+	rng          *rand.Rand
+	width        int
+}
+
+// NewTableDrillModel creates a table drill over config.QuestionCount random nouns (0 for endless)
+func NewTableDrillModel(repo storage.Repository, config models.SessionConfig) (*TableDrillModel, error) {
+	nouns, err := repo.ListNouns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nouns: %w", err)
+	}
+	if len(nouns) == 0 {
+		return nil, fmt.Errorf("no nouns found in database. Please run 'greekmaster import <csv-file>' first")
+	}
+
+	// Table drills only use the noun filters
+	if !config.Filter.IsEmpty() {
+		nounTags, err := repo.ListNounTags()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load noun tags: %w", err)
+		}
+		nouns = slices.DeleteFunc(nouns, func(noun *models.Noun) bool {
+			return !config.Filter.MatchNoun(noun, nounTags[noun.ID])
+		})
+		if len(nouns) == 0 {
+			return nil, fmt.Errorf("no nouns match the session filters")
+		}
+	}
+
+	stats, err := repo.ListTableDrillStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load table drill stats: %w", err)
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng.Shuffle(len(nouns), func(i, j int) {
+		nouns[i], nouns[j] = nouns[j], nouns[i]
+	})
+
+	if config.QuestionCount > 0 && len(nouns) > config.QuestionCount {
+		nouns = nouns[:config.QuestionCount]
+	}
+
+	model := &TableDrillModel{
+		repo:   repo,
+		config: config,
+		nouns:  nouns,
+		state:  "question",
+		stats:  stats,
+		rng:    rng,
+		width:  80, // Default width
+	}
+	model.loadCurrentNoun()
+
+	return model, nil
+}
+
+func (m *TableDrillModel) loadCurrentNoun() {
+	if m.currentIndex < len(m.nouns) {
+		m.currentNoun = m.nouns[m.currentIndex]
+	}
+	m.inputs = make([]string, len(tableCells))
+	m.results = make([]bool, len(tableCells))
+	m.cursor = 0
+}
+
+// declensionClass names the declension class of a noun for result tracking
+func declensionClass(noun *models.Noun) string {
+	if class, ok := grammar.Default().Classify(noun); ok {
+		return class.Name
+	}
+	if grammar.IsInvariable(noun) {
+		return "invariable"
+	}
+	return "unclassified"
+}
+
+// declensionTitle returns a readable name for a stored declension class
+func declensionTitle(gender, className string) string {
+	if class, ok := grammar.Default().Declension(className); ok {
+		return class.Title
+	}
+	return fmt.Sprintf("%s %s", className, gender)
+}
+
+// expectedAnswer returns the article and form expected in a cell
+func expectedAnswer(noun *models.Noun, cell tableCell) string {
+	article, form, _ := explanations.FormFor(noun, cell.caseType, cell.number)
+	return article + " " + form
+}
+
+func (m TableDrillModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TableDrillModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.state {
+		case "question":
+			switch msg.String() {
+			case "ctrl+c", "q":
+				return m, tea.Quit
+
+			case "up", "shift+tab":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+
+			case "down", "tab":
+				if m.cursor < len(tableCells)-1 {
+					m.cursor++
+				}
+
+			case "enter":
+				// Enter moves to the next cell and submits from the last one
+				if m.cursor < len(tableCells)-1 {
+					m.cursor++
+					return m, nil
+				}
+				m.submit()
+				return m, nil
+
+			case "backspace":
+				runes := []rune(m.inputs[m.cursor])
+				if len(runes) > 0 {
+					m.inputs[m.cursor] = string(runes[:len(runes)-1])
+				}
+
+			default:
+				if len(msg.Runes) > 0 {
+					m.inputs[m.cursor] += string(msg.Runes)
+				}
+			}
+
+		case "feedback":
+			// Any key continues to the next noun
+			m.currentIndex++
+
+			if m.config.QuestionCount > 0 && m.currentIndex >= len(m.nouns) {
+				m.state = "complete"
+				return m, nil
+			}
+			if m.currentIndex >= len(m.nouns) {
+				// Endless mode - reshuffle and continue
+				m.rng.Shuffle(len(m.nouns), func(i, j int) {
+					m.nouns[i], m.nouns[j] = m.nouns[j], m.nouns[i]
+				})
+				m.currentIndex = 0
+			}
+			m.loadCurrentNoun()
+			m.state = "question"
+			return m, nil
+
+		case "complete":
+			switch msg.String() {
+			case "q":
+				return m, tea.Quit
+			case "r":
+				// Restart session
+				m.currentIndex = 0
+				m.correctCells = 0
+				m.totalCells = 0
+				m.perfectNouns = 0
+				m.rng.Shuffle(len(m.nouns), func(i, j int) {
+					m.nouns[i], m.nouns[j] = m.nouns[j], m.nouns[i]
+				})
+				m.loadCurrentNoun()
+				m.state = "question"
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// submit grades every cell, records the results and refreshes the miss statistics
+func (m *TableDrillModel) submit() {
+	class := declensionClass(m.currentNoun)
+	results := make([]*models.TableDrillResult, 0, len(tableCells))
+
+	perfect := true
+	for i, cell := range tableCells {
+		m.results[i] = grading.GradeAnswer(m.inputs[i], expectedAnswer(m.currentNoun, cell))
+		if m.results[i] {
+			m.correctCells++
+		} else {
+			perfect = false
+		}
+		m.totalCells++
+
+		results = append(results, &models.TableDrillResult{
+			NounID:          m.currentNoun.ID,
+			Gender:          m.currentNoun.Gender,
+			DeclensionClass: class,
+			CaseType:        cell.caseType,
+			Number:          cell.number,
+			Correct:         m.results[i],
+		})
+	}
+	if perfect {
+		m.perfectNouns++
+	}
+
+	if err := m.repo.RecordTableDrillResults(results); err != nil {
+		m.err = err
+	} else if stats, err := m.repo.ListTableDrillStats(); err != nil {
+		m.err = err
+	} else {
+		m.stats = stats
+	}
+
+	m.state = "feedback"
+}
+
+// missedCells returns the most frequently missed cells, optionally limited to one
+// gender and declension class
+func (m TableDrillModel) missedCells(gender, class string, limit int) []*models.TableDrillStat {
+	var missed []*models.TableDrillStat
+	for _, stat := range m.stats {
+		if len(missed) == limit {
+			break
+		}
+		if stat.Misses == 0 {
+			continue
+		}
+		if class != "" && (stat.Gender != gender || stat.DeclensionClass != class) {
+			continue
+		}
+		missed = append(missed, stat)
+	}
+	return missed
+}
+
+func (m TableDrillModel) View() string {
+	switch m.state {
+	case "question":
+		return m.renderQuestion()
+	case "feedback":
+		return m.renderFeedback()
+	case "complete":
+		return m.renderComplete()
+	default:
+		return ""
+	}
+}
+
+// cellIndex returns the input index of a grid cell
+func cellIndex(caseType, number string) int {
+	for i, cell := range tableCells {
+		if cell.caseType == caseType && cell.number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderGrid lays out one rendered string per cell as a case by number table
+func renderGrid(cells []string) string {
+	width := len("Singular")
+	for _, cell := range cells {
+		width = max(width, lipgloss.Width(cell))
+	}
+
+	pad := func(s string) string {
+		return s + strings.Repeat(" ", width-lipgloss.Width(s))
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%-12s%s  %s", "", pad("Singular"), "Plural"))
+	for _, c := range tableCases {
+		s.WriteString(fmt.Sprintf("\n%-12s%s  %s", explanations.CaseName(c),
+			pad(cells[cellIndex(c, "singular")]), cells[cellIndex(c, "plural")]))
+	}
+	return s.String()
+}
+
+func (m TableDrillModel) renderQuestion() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39"))
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		MarginTop(1).
+		MarginBottom(1)
+
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true).
+		MarginTop(1)
+
+	// Header
+	var header string
+	if m.config.QuestionCount > 0 {
+		header = fmt.Sprintf("Greek Case Master - Table %d/%d", m.currentIndex+1, len(m.nouns))
+	} else {
+		header = fmt.Sprintf("Greek Case Master - Table %d (Endless)", m.currentIndex+1)
+	}
+	s.WriteString(titleStyle.Render(header))
+	s.WriteString("\n\n")
+
+	// Prompt
+	prompt := fmt.Sprintf("Decline %s %s (%s, %s)", m.currentNoun.NomSgArticle, m.currentNoun.NominativeSg,
+		m.currentNoun.English, m.currentNoun.Gender)
+	s.WriteString(promptStyle.Render(prompt))
+	s.WriteString("\n\n")
+
+	// Grid
+	cells := make([]string, len(tableCells))
+	for i := range tableCells {
+		if i == m.cursor {
+			cells[i] = activeStyle.Render("> " + m.inputs[i] + "_")
+		} else {
+			cells[i] = inputStyle.Render("  " + m.inputs[i])
+		}
+	}
+	s.WriteString(renderGrid(cells))
+	s.WriteString("\n")
+
+	// Hints
+	s.WriteString(hintStyle.Render("[↑/↓ or Tab to move] [Enter for next cell, submits on the last] [Ctrl+C or q to quit]"))
+
+	return borderStyle.Render(s.String())
+}
+
+func (m TableDrillModel) renderFeedback() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	correctStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("120"))
+
+	incorrectStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("196"))
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Bold(true)
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	answerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Strikethrough(true)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Italic(true).
+		MarginTop(1)
+
+	correct := 0
+	for _, ok := range m.results {
+		if ok {
+			correct++
+		}
+	}
+
+	// Header
+	if correct == len(tableCells) {
+		s.WriteString(correctStyle.Render("✓ All six forms correct!"))
+	} else {
+		s.WriteString(incorrectStyle.Render(fmt.Sprintf("✗ %d/%d forms correct", correct, len(tableCells))))
+	}
+	s.WriteString("\n\n")
+
+	// Grid with wrong cells showing the learner's answer and the correction
+	cells := make([]string, len(tableCells))
+	for i, cell := range tableCells {
+		expected := expectedAnswer(m.currentNoun, cell)
+		if m.results[i] {
+			cells[i] = answerStyle.Render(expected)
+			continue
+		}
+		entered := strings.TrimSpace(m.inputs[i])
+		if entered == "" {
+			entered = "—"
+		}
+		cells[i] = errorStyle.Render(entered) + " " + incorrectStyle.Render(expected)
+	}
+	s.WriteString(renderGrid(cells))
+
+	class := declensionClass(m.currentNoun)
+	if declension, ok := grammar.Default().Declension(class); ok {
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Pattern: "))
+		s.WriteString(textStyle.Render(fmt.Sprintf("%s (like %s)", declension.Title, declension.Example)))
+	}
+
+	// Cells learners of this class tend to miss
+	if missed := m.missedCells(m.currentNoun.Gender, class, maxMissedHints); len(missed) > 0 {
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render(fmt.Sprintf("Often missed (%s):", declensionTitle(m.currentNoun.Gender, class))))
+		for _, stat := range missed {
+			s.WriteString("\n")
+			s.WriteString(textStyle.Render(fmt.Sprintf("  %s %s: %d/%d missed",
+				stat.CaseType, stat.Number, stat.Misses, stat.Attempts)))
+		}
+	}
+
+	if m.err != nil {
+		s.WriteString("\n\n")
+		s.WriteString(incorrectStyle.Render(fmt.Sprintf("Could not save results: %v", m.err)))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(hintStyle.Render("\n[Press any key to continue]"))
+
+	return borderStyle.Render(s.String())
+}
+
+func (m TableDrillModel) renderComplete() string {
+	var s strings.Builder
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("120")).
+		MarginBottom(1)
+
+	statsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		MarginTop(1)
+
+	s.WriteString(titleStyle.Render("Session Complete!"))
+	s.WriteString("\n\n")
+
+	accuracy := 0
+	if m.totalCells > 0 {
+		accuracy = (m.correctCells * 100) / m.totalCells
+	}
+
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Tables: %d (%d perfect)", len(m.nouns), m.perfectNouns)))
+	s.WriteString("\n")
+	s.WriteString(statsStyle.Render(fmt.Sprintf("Cell accuracy: %d%%", accuracy)))
+	s.WriteString("\n")
+
+	if missed := m.missedCells("", "", 3); len(missed) > 0 {
+		s.WriteString("\n")
+		s.WriteString(statsStyle.Render("Most missed cells overall:"))
+		for _, stat := range missed {
+			s.WriteString("\n")
+			s.WriteString(statsStyle.Render(fmt.Sprintf("  %s: %s %s (%.0f%% missed)",
+				declensionTitle(stat.Gender, stat.DeclensionClass), stat.CaseType, stat.Number, stat.MissRate()*100)))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(hintStyle.Render("\n[q] Quit  [r] Restart session"))
+
+	return borderStyle.Render(s.String())
+}
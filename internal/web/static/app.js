@@ -0,0 +1,343 @@
+// Browser version of the terminal practice session. The server runs the session
+// (questions, grading, review rounds, score and saving for resume); this script
+// renders the state it returns and sends the learner's input.
+"use strict";
+
+// How often the countdown and sprint clocks are redrawn
+const CLOCK_INTERVAL = 250;
+
+const $ = (id) => document.getElementById(id);
+
+// view is the latest session state returned by the server
+let view = null;
+let clock = null;
+
+// api calls the JSON API and throws its error message on failure
+async function api(method, path, body) {
+  const options = { method, headers: {} };
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+  const response = await fetch(path, options);
+  const data = await response.json();
+  if (!response.ok) {
+    throw new Error(data.error || response.statusText);
+  }
+  return data;
+}
+
+// act sends a session action and renders the session's new state
+async function act(action, body = {}) {
+  render(await api("POST", `/api/sessions/${view.id}/${action}`, body));
+}
+
+function show(id) {
+  for (const section of ["setup", "question", "feedback", "complete"]) {
+    $(section).hidden = section !== id;
+  }
+}
+
+function plural(n, singular, pluralForm) {
+  return n === 1 ? singular : pluralForm;
+}
+
+// Setup
+
+async function showSetup() {
+  show("setup");
+  try {
+    const saved = await api("GET", "/api/sessions/saved");
+    const count = saved.config.question_count;
+    const progress = count > 0
+      ? `${saved.answered} of ${count} answered, ${saved.correct} correct`
+      : `${saved.answered} answered, ${saved.correct} correct`;
+    $("resume-summary").textContent =
+      `Unfinished ${saved.config.mode} session from ${new Date(saved.updated_at).toLocaleString()}: ${progress}`;
+    $("resume").hidden = false;
+  } catch {
+    $("resume").hidden = true;
+  }
+}
+
+function startSession(event) {
+  event.preventDefault();
+  const form = new FormData($("setup-form"));
+
+  const filter = {};
+  if (form.get("gender")) filter.genders = [form.get("gender")];
+  if (form.get("case")) filter.cases = [form.get("case")];
+
+  return begin({
+    config: {
+      difficulty_level: form.get("difficulty"),
+      include_plural: form.get("plural") !== null,
+      question_count: Number(form.get("count")),
+      mode: form.get("mode"),
+      time_limit: Number(form.get("time-limit")),
+      sprint_seconds: Number(form.get("sprint")),
+      filter,
+    },
+  });
+}
+
+// begin starts a new session or resumes the saved one
+async function begin(body) {
+  $("setup-error").hidden = true;
+  $("answer").value = "";
+  try {
+    render(await api("POST", "/api/sessions", body));
+  } catch (err) {
+    $("setup-error").textContent = err.message;
+    $("setup-error").hidden = false;
+  }
+}
+
+// render shows the screen for the session's state
+function render(next) {
+  view = next;
+  stopClock();
+  if (view.state === "question") {
+    renderQuestion();
+  } else if (view.state === "feedback") {
+    renderFeedback();
+  } else {
+    renderComplete();
+  }
+  startClock();
+}
+
+// Question
+
+function renderQuestion() {
+  const q = view.question;
+
+  let header;
+  if (q.review_round > 0) {
+    header = `Greek Case Master - Review ${q.review_round} - Question ${q.number}/${q.total}`;
+  } else if (q.endless) {
+    header = `Greek Case Master - Question ${q.number} (Endless)`;
+  } else {
+    header = `Greek Case Master - Question ${q.number}/${q.total}`;
+  }
+  $("question-header").textContent = header;
+  $("prompt").textContent = q.prompt;
+  $("hints").replaceChildren(...q.hints.map((hint) => {
+    const item = document.createElement("li");
+    item.textContent = hint;
+    return item;
+  }));
+
+  const hint = $("hint-button");
+  hint.disabled = q.hints_left === 0;
+  hint.textContent = `Hint (-${Math.round(q.hint_penalty * 100)}%)`;
+
+  const choice = view.config.mode === "choice";
+  $("typed-answer").hidden = choice;
+  $("submit-button").hidden = choice;
+  $("choices").hidden = !choice;
+  if (choice) {
+    $("choices").replaceChildren(...q.choices.map((text, i) => {
+      const button = document.createElement("button");
+      button.type = "button";
+      button.className = "secondary";
+      button.textContent = `${i + 1}. ${text}`;
+      button.addEventListener("click", reportError(() => act("answers", { answer: text })));
+      return button;
+    }));
+  }
+
+  show("question");
+  if (!choice) $("answer").focus();
+}
+
+// In choice mode a number key picks an option
+function pickChoice(event) {
+  if (!view || view.state !== "question" || view.config.mode !== "choice") return;
+  const choice = Number(event.key) - 1;
+  if (Number.isInteger(choice) && choice >= 0 && choice < view.question.choices.length) {
+    reportError(() => act("answers", { answer: view.question.choices[choice] }))();
+  }
+}
+
+// Feedback
+
+function addEntry(list, term, text, className) {
+  const dt = document.createElement("dt");
+  dt.textContent = term;
+  const dd = document.createElement("dd");
+  dd.textContent = text;
+  if (className) dd.className = className;
+  list.append(dt, dd);
+}
+
+function renderFeedback() {
+  const result = view.result;
+  const header = $("feedback-header");
+  const summary = $("answer-summary");
+  summary.replaceChildren();
+
+  if (result.correct && result.hints_used > 0) {
+    header.textContent = `✓ Correct! (${result.hints_used} ${plural(result.hints_used, "hint", "hints")}, score ${result.score.toFixed(2)})`;
+    header.className = "correct";
+  } else if (result.correct) {
+    header.textContent = "✓ Correct!";
+    header.className = "correct";
+  } else {
+    header.textContent = "✗ Incorrect";
+    header.className = "incorrect";
+    const answer = result.timed_out ? `${result.answer} (time's up)`.trim() : result.answer;
+    addEntry(summary, "You entered", answer);
+    addEntry(summary, "Correct answer", result.correct_answer);
+  }
+
+  const explanation = $("explanation");
+  explanation.replaceChildren();
+  const e = result.explanation;
+  if (e) {
+    addEntry(explanation, "Translation", e.translation);
+    addEntry(explanation, "Syntactic Role", e.syntactic_role);
+    addEntry(explanation, "Morphology", e.morphology);
+    if (e.pattern) addEntry(explanation, "Pattern", e.pattern);
+    if (e.paradigm) addEntry(explanation, "Paradigm", e.paradigm, "paradigm");
+    if (e.usage) addEntry(explanation, "Usage", e.usage);
+  }
+
+  $("answer").value = "";
+  show("feedback");
+  $("continue-button").focus();
+}
+
+// Completion
+
+// reviewSummary describes what the next review round covers
+function reviewSummary(summary) {
+  const parts = [];
+  if (summary.missed > 0) parts.push(`${summary.missed} ${plural(summary.missed, "mistake", "mistakes")}`);
+  if (summary.slow > 0) parts.push(`${summary.slow} slow ${plural(summary.slow, "answer", "answers")}`);
+  return parts.join(" + ");
+}
+
+function renderComplete() {
+  const s = view.summary;
+  const lines = [
+    `Answered: ${s.answered}/${s.length}`,
+    `Accuracy: ${s.accuracy}%`,
+  ];
+  if (s.total_hints > 0) {
+    lines.push(`Score: ${s.score.toFixed(2)}/${s.answered} (${s.total_hints} hints used)`);
+  }
+  if (s.answers_per_minute > 0) {
+    lines.push(`Speed: ${s.answers_per_minute.toFixed(1)} answers/min (${s.seconds_per_answer.toFixed(1)}s per answer)`);
+  }
+  if (s.review_rounds > 0) {
+    lines.push(`All mistakes corrected ✓ (${s.review_rounds} review ${plural(s.review_rounds, "round", "rounds")})`);
+  }
+
+  $("stats").replaceChildren(...lines.map((line) => {
+    const item = document.createElement("li");
+    item.textContent = line;
+    return item;
+  }));
+
+  const review = $("review-button");
+  review.hidden = s.missed + s.slow === 0;
+  review.textContent = `Review ${reviewSummary(s)}`;
+
+  show("complete");
+}
+
+// Clocks
+
+// startClock counts down the question and sprint times the server reported. When
+// the countdown runs out the current input is submitted; when the sprint is over
+// the server ends the session.
+function startClock() {
+  const now = performance.now();
+  clock = {
+    question: view.state === "question" && view.config.time_limit > 0 ? now + (view.question.time_left_ms || 0) : null,
+    sprint: view.state !== "complete" && "sprint_left_ms" in view ? now + view.sprint_left_ms : null,
+    timer: null,
+  };
+  if (clock.question === null && clock.sprint === null) {
+    drawClock();
+    return;
+  }
+  tickClock();
+  clock.timer = setInterval(tickClock, CLOCK_INTERVAL);
+}
+
+function stopClock() {
+  if (clock) clearInterval(clock.timer);
+  clock = null;
+}
+
+function secondsLeft(deadline) {
+  return Math.max(0, Math.ceil((deadline - performance.now()) / 1000));
+}
+
+function tickClock() {
+  const now = performance.now();
+  if (clock.sprint !== null && now >= clock.sprint) {
+    stopClock();
+    reportError(async () => render(await api("GET", `/api/sessions/${view.id}`)))();
+    return;
+  }
+  if (clock.question !== null && now >= clock.question) {
+    stopClock();
+    const answer = view.config.mode === "choice" ? "" : $("answer").value;
+    reportError(() => act("answers", { answer }))();
+    return;
+  }
+  drawClock();
+}
+
+// drawClock shows the time left, and on the feedback screen whether the answer was slow
+function drawClock() {
+  const parts = [];
+  if (clock.question !== null) {
+    parts.push(`⏱ ${secondsLeft(clock.question)}s`);
+  }
+  if (clock.sprint !== null) {
+    const left = secondsLeft(clock.sprint);
+    parts.push(`Sprint ${Math.floor(left / 60)}:${String(left % 60).padStart(2, "0")} left`);
+  }
+
+  if (view.state === "question") {
+    $("clock").textContent = parts.join("  ");
+    $("clock").hidden = parts.length === 0;
+  } else if (view.state === "feedback") {
+    if (view.result.slow) {
+      parts.unshift(`⏱ Correct but slow (${(view.result.latency_ms / 1000).toFixed(1)}s) - added to review`);
+    }
+    $("feedback-note").textContent = parts.join("  ");
+    $("feedback-note").hidden = parts.length === 0;
+  }
+}
+
+// reportError shows a failed API call without losing the session
+function reportError(fn) {
+  return async (...args) => {
+    try {
+      await fn(...args);
+    } catch (err) {
+      alert(err.message);
+    }
+  };
+}
+
+$("setup-form").addEventListener("submit", startSession);
+$("resume-button").addEventListener("click", () => begin({ resume: true }));
+$("answer-form").addEventListener("submit", reportError((event) => {
+  event.preventDefault();
+  return act("answers", { answer: $("answer").value });
+}));
+$("hint-button").addEventListener("click", reportError(() => act("hints")));
+$("stop-button").addEventListener("click", reportError(() => act("finish")));
+$("continue-button").addEventListener("click", reportError(() => act("next")));
+$("review-button").addEventListener("click", reportError(() => act("review")));
+$("restart-button").addEventListener("click", reportError(() => act("restart")));
+$("new-button").addEventListener("click", showSetup);
+document.addEventListener("keydown", pickChoice);
+
+showSetup();
@@ -0,0 +1,20 @@
+// Package web embeds the browser practice UI served by 'greekmaster serve'
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the single-page practice UI. It talks to the JSON API under /api/.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	return http.FileServerFS(files)
+}